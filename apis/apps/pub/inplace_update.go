@@ -87,6 +87,8 @@ type InPlaceUpdateState struct {
 // InPlaceUpdatePreCheckBeforeNext contains the pre-check that must pass before the next containers can be in-place update.
 type InPlaceUpdatePreCheckBeforeNext struct {
 	ContainersRequiredReady []string `json:"containersRequiredReady,omitempty"`
+	// IntervalSeconds is the minimum seconds to wait since the previous batch updated, before the next batch can be updated.
+	IntervalSeconds int32 `json:"intervalSeconds,omitempty"`
 }
 
 // InPlaceUpdateContainerBatch indicates the timestamp and containers for a batch update
@@ -108,6 +110,24 @@ type InPlaceUpdateStrategy struct {
 	// GracePeriodSeconds is the timespan between set Pod status to not-ready and update images in Pod spec
 	// when in-place update a Pod.
 	GracePeriodSeconds int32 `json:"gracePeriodSeconds,omitempty"`
+
+	// ContainerUpdateOrder is the ordered batches of containers to in-place update.
+	// The containers in a batch will be updated only after the containers in previous batches have updated ready
+	// and the intervalSeconds of previous batch passed.
+	// The containers not listed here will be updated after all the listed batches.
+	// If it is empty, the batches are decided by container launch priority.
+	// +optional
+	ContainerUpdateOrder []InPlaceUpdateContainerOrder `json:"containerUpdateOrder,omitempty"`
+}
+
+// InPlaceUpdateContainerOrder is a batch of containers that will be in-place updated together.
+type InPlaceUpdateContainerOrder struct {
+	// Containers is the names of containers in this batch.
+	Containers []string `json:"containers"`
+
+	// IntervalSeconds is the seconds to wait after this batch updated, before the next batch can be updated.
+	// +optional
+	IntervalSeconds int32 `json:"intervalSeconds,omitempty"`
 }
 
 func GetInPlaceUpdateState(obj metav1.Object) (string, bool) {
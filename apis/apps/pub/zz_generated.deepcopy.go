@@ -46,6 +46,26 @@ func (in *InPlaceUpdateContainerBatch) DeepCopy() *InPlaceUpdateContainerBatch {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InPlaceUpdateContainerOrder) DeepCopyInto(out *InPlaceUpdateContainerOrder) {
+	*out = *in
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InPlaceUpdateContainerOrder.
+func (in *InPlaceUpdateContainerOrder) DeepCopy() *InPlaceUpdateContainerOrder {
+	if in == nil {
+		return nil
+	}
+	out := new(InPlaceUpdateContainerOrder)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InPlaceUpdateContainerStatus) DeepCopyInto(out *InPlaceUpdateContainerStatus) {
 	*out = *in
@@ -140,6 +160,13 @@ func (in *InPlaceUpdateState) DeepCopy() *InPlaceUpdateState {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InPlaceUpdateStrategy) DeepCopyInto(out *InPlaceUpdateStrategy) {
 	*out = *in
+	if in.ContainerUpdateOrder != nil {
+		in, out := &in.ContainerUpdateOrder, &out.ContainerUpdateOrder
+		*out = make([]InPlaceUpdateContainerOrder, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InPlaceUpdateStrategy.
@@ -29,12 +29,32 @@ const (
 	// Each pod and the pvcs it owns have the same instance-id.
 	CloneSetInstanceID = "apps.kruise.io/cloneset-instance-id"
 
+	// CloneSetStandbyPodLabelKey is the label key of standby Pods, which are kept out of service
+	// until they are promoted to replace the scaled out Pods.
+	CloneSetStandbyPodLabelKey = "apps.kruise.io/cloneset-standby"
+
+	// CloneSetInPlaceUpdateSurgePodLabelKey is the label key of the temporary Pods created by
+	// updateStrategy.inPlaceUpdateSurge, which will be deleted after the in-place update.
+	CloneSetInPlaceUpdateSurgePodLabelKey = "apps.kruise.io/cloneset-in-place-update-surge"
+
+	// CloneSetReplacementForLabelKey is the label key of the Pods created to replace the Pods specified to replace,
+	// and the value is the instance-id of the replaced Pod.
+	CloneSetReplacementForLabelKey = "apps.kruise.io/cloneset-replacement-for"
+
 	// DefaultCloneSetMaxUnavailable is the default value of maxUnavailable for CloneSet update strategy.
 	DefaultCloneSetMaxUnavailable = "20%"
 
 	// CloneSetScalingExcludePreparingDeleteKey is the label key that enables scalingExcludePreparingDelete
 	// only for this CloneSet, which means it will calculate scale number excluding Pods in PreparingDelete state.
 	CloneSetScalingExcludePreparingDeleteKey = "apps.kruise.io/cloneset-scaling-exclude-preparing-delete"
+
+	// CloneSetRestoredSnapshotDeletedAnnotation is added to the PVC restored from the VolumeSnapshot by pvcSnapshotPolicy,
+	// once the snapshot has been deleted.
+	CloneSetRestoredSnapshotDeletedAnnotation = "apps.kruise.io/cloneset-restored-snapshot-deleted"
+
+	// CloneSetRetryUpdateStepAnnotation is the annotation to retry the checks of the update step in StepFailed state.
+	// Each new value, such as the current time, retries the checks once.
+	CloneSetRetryUpdateStepAnnotation = "apps.kruise.io/cloneset-retry-update-step"
 )
 
 // CloneSetSpec defines the desired state of CloneSet
@@ -88,6 +108,19 @@ type CloneSetSpec struct {
 	// condition when timeout occurs, while excluding paused state duration from the deadline calculation.
 	// This field is optional. If not set, the controller will not track progress deadlines or add the condition.
 	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+
+	// RollbackOnFailure indicates the controller will switch the template back to the current revision,
+	// if the update has not completed when progressDeadlineSeconds is exceeded, even before any pod is updated.
+	// The reason will be recorded in the RolledBack condition.
+	// It only works when progressDeadlineSeconds is set.
+	RollbackOnFailure bool `json:"rollbackOnFailure,omitempty"`
+
+	// StandbyReplicas is the number of standby Pods besides the replicas.
+	// Standby Pods are created and kept running, but not ready through the KruisePodReady readiness gate,
+	// so that they are excluded from the Service endpoints.
+	// When scaling out, the standby Pods will be promoted to serve instead of creating new Pods.
+	// Defaults to nil, which means no standby Pods.
+	StandbyReplicas *int32 `json:"standbyReplicas,omitempty"`
 }
 
 // CloneSetScaleStrategy defines strategies for pods scale.
@@ -104,6 +137,59 @@ type CloneSetScaleStrategy struct {
 	// Indicate if cloneSet will reuse already existed pvc to
 	// rebuild a new pod
 	DisablePVCReuse bool `json:"disablePVCReuse,omitempty"`
+
+	// ScaleDownPolicy is the policy to choose pods to delete when scaling down.
+	// If it is nil, pods are chosen by the default order, including pod-deletion-cost and spread on nodes.
+	// +optional
+	ScaleDownPolicy *CloneSetScaleDownPolicy `json:"scaleDownPolicy,omitempty"`
+
+	// PVCSnapshotPolicy indicates to take VolumeSnapshots of the PVCs before deleting Pods for scaling in,
+	// and the PVCs of a Pod recreated with the same instance-id will be restored from these snapshots.
+	// It requires the VolumeSnapshot CRDs and a CSI driver that supports snapshots in the cluster.
+	// +optional
+	PVCSnapshotPolicy *CloneSetPVCSnapshotPolicy `json:"pvcSnapshotPolicy,omitempty"`
+}
+
+// CloneSetPVCSnapshotPolicy defines how to snapshot the PVCs of the Pods to scale in.
+type CloneSetPVCSnapshotPolicy struct {
+	// VolumeSnapshotClassName is the name of VolumeSnapshotClass to create snapshots.
+	// If it is empty, the default VolumeSnapshotClass will be used.
+	// +optional
+	VolumeSnapshotClassName *string `json:"volumeSnapshotClassName,omitempty"`
+}
+
+// CloneSetScaleDownPolicy contains the weighted scorers to choose pods to delete when scaling down.
+// Pods not scheduled, not running or not ready are still deleted first,
+// then the pods with higher weighted score are preferred to be deleted.
+type CloneSetScaleDownPolicy struct {
+	// Scorers is the list of scorers, the score of a pod is the weighted sum of the scores from all scorers.
+	Scorers []CloneSetScaleDownScorer `json:"scorers"`
+}
+
+// CloneSetScaleDownScorerType is the type of scorer to choose pods to delete.
+type CloneSetScaleDownScorerType string
+
+const (
+	// DeletionCostScaleDownScorer prefers to delete pods with lower controller.kubernetes.io/pod-deletion-cost.
+	DeletionCostScaleDownScorer CloneSetScaleDownScorerType = "DeletionCost"
+	// NodeBinPackingScaleDownScorer prefers to delete pods on nodes with lower resource utilization,
+	// so that these nodes can be emptied and reclaimed by cluster autoscaler.
+	NodeBinPackingScaleDownScorer CloneSetScaleDownScorerType = "NodeBinPacking"
+	// ZoneSpreadScaleDownScorer prefers to delete pods in the topology with more pods of this CloneSet,
+	// so that pods keep balanced among the topologies.
+	ZoneSpreadScaleDownScorer CloneSetScaleDownScorerType = "ZoneSpread"
+)
+
+// CloneSetScaleDownScorer is a scorer with its weight.
+type CloneSetScaleDownScorer struct {
+	// Type is the type of this scorer.
+	Type CloneSetScaleDownScorerType `json:"type"`
+	// Weight of this scorer, in the range 1-100.
+	Weight int32 `json:"weight"`
+	// TopologyKey is the node label key for ZoneSpread scorer.
+	// Defaults to topology.kubernetes.io/zone.
+	// +optional
+	TopologyKey string `json:"topologyKey,omitempty"`
 }
 
 // CloneSetUpdateStrategy defines strategies for pods update.
@@ -128,6 +214,11 @@ type CloneSetUpdateStrategy struct {
 	// Absolute number is calculated from percentage by rounding up.
 	// Defaults to 0.
 	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+	// InPlaceUpdateSurge indicates that maxSurge is used to keep the capacity during in-place update.
+	// Temporary Pods in update revision will be created before in-place updating the existing Pods,
+	// and will be deleted after the updated Pods become available.
+	// It requires maxSurge and can not be used with ReCreate type.
+	InPlaceUpdateSurge bool `json:"inPlaceUpdateSurge,omitempty"`
 	// Paused indicates that the CloneSet is paused.
 	// Default value is false
 	Paused bool `json:"paused,omitempty"`
@@ -141,6 +232,57 @@ type CloneSetUpdateStrategy struct {
 	ScatterStrategy UpdateScatterStrategy `json:"scatterStrategy,omitempty"`
 	// InPlaceUpdateStrategy contains strategies for in-place update.
 	InPlaceUpdateStrategy *appspub.InPlaceUpdateStrategy `json:"inPlaceUpdateStrategy,omitempty"`
+	// Steps defines the batches of a progressive update. The controller moves through the steps one by one,
+	// and the partition of the current step takes the place of updateStrategy.partition.
+	// It can not be used together with a non-zero partition.
+	Steps []CloneSetUpdateStep `json:"steps,omitempty"`
+	// UpdateWindows are the time windows in which pods are allowed to start updating.
+	// Pods that have started updating will continue even if the window closes.
+	// If it is empty, pods can be updated at any time.
+	UpdateWindows []CloneSetUpdateWindow `json:"updateWindows,omitempty"`
+}
+
+// CloneSetUpdateWindow defines a daily time range in which pods are allowed to start updating.
+type CloneSetUpdateWindow struct {
+	// Start is the start time of the window in "HH:MM" format.
+	Start string `json:"start"`
+	// End is the end time of the window in "HH:MM" format.
+	// If it is not later than start, the window ends at that time of the next day.
+	End string `json:"end"`
+	// Days are the days of week that the window starts on, such as Mon and Sat.
+	// If it is empty, the window starts on every day.
+	// +optional
+	Days []string `json:"days,omitempty"`
+	// TimeZone is the IANA name of the time zone for start and end, such as Asia/Shanghai.
+	// Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// CloneSetUpdateStep defines one batch of a progressive update.
+type CloneSetUpdateStep struct {
+	// Partition is the desired number of pods in old revisions in this step.
+	// Value can be an absolute number (ex: 5) or a percentage of desired pods (ex: 10%).
+	// Absolute number is calculated from percentage by rounding up.
+	Partition intstr.IntOrString `json:"partition"`
+	// PauseSeconds is the time to wait after all pods of this step are updated and available,
+	// before moving on to the next step.
+	// Defaults to 0.
+	PauseSeconds int32 `json:"pauseSeconds,omitempty"`
+	// Checks must all pass on the updated pods before moving on to the next step.
+	Checks []CloneSetUpdateStepCheck `json:"checks,omitempty"`
+}
+
+// CloneSetUpdateStepCheck defines a check on the updated pods of a step.
+type CloneSetUpdateStepCheck struct {
+	// PodConditionType is the type of condition that must be True on all updated pods,
+	// such as the condition maintained by a PodProbeMarker probe or by an external metric analysis.
+	PodConditionType v1.PodConditionType `json:"podConditionType"`
+	// TimeoutSeconds is the maximum time to wait for the check to pass after the pause of this step.
+	// The update stops in the StepFailed state if it times out, until the checks are retried by
+	// the apps.kruise.io/cloneset-retry-update-step annotation.
+	// Defaults to 0, which means no timeout.
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
 }
 
 // CloneSetUpdateStrategyType defines strategies for pods in-place update.
@@ -187,6 +329,9 @@ type CloneSetStatus struct {
 	// indicated by updateRevision and have a Ready Condition.
 	UpdatedReadyReplicas int32 `json:"updatedReadyReplicas"`
 
+	// StandbyReplicas is the number of standby Pods, which are not included in the replicas above.
+	StandbyReplicas int32 `json:"standbyReplicas,omitempty"`
+
 	// UpdatedAvailableReplicas is the number of Pods created by the CloneSet controller from the CloneSet version
 	// indicated by updateRevision and have a Ready Condition for at least minReadySeconds.
 	// Notice: when enable InPlaceWorkloadVerticalScaling, pod during resource resizing will also be unavailable.
@@ -213,6 +358,86 @@ type CloneSetStatus struct {
 
 	// LabelSelector is label selectors for query over pods that should match the replica count used by HPA.
 	LabelSelector string `json:"labelSelector,omitempty"`
+
+	// UpdateStepStatus records the progress of updateStrategy.steps for the update revision.
+	UpdateStepStatus *CloneSetUpdateStepStatus `json:"updateStepStatus,omitempty"`
+
+	// UpdateRevisionDiff explains what changed between currentRevision and updateRevision.
+	// It is empty when the two revisions are the same.
+	UpdateRevisionDiff *CloneSetRevisionDiff `json:"updateRevisionDiff,omitempty"`
+}
+
+// CloneSetUpdateStepState is the state of the current update step.
+type CloneSetUpdateStepState string
+
+const (
+	// CloneSetStepUpdating means the pods of the current step are being updated.
+	CloneSetStepUpdating CloneSetUpdateStepState = "StepUpdating"
+	// CloneSetStepVerifying means the pods of the current step are available,
+	// and the controller is waiting for the pause and checks of the step.
+	CloneSetStepVerifying CloneSetUpdateStepState = "StepVerifying"
+	// CloneSetStepFailed means the checks of the current step have timed out, so the update stops at this step
+	// until the checks are retried by the apps.kruise.io/cloneset-retry-update-step annotation.
+	CloneSetStepFailed CloneSetUpdateStepState = "StepFailed"
+	// CloneSetStepCompleted means all the steps have been completed.
+	CloneSetStepCompleted CloneSetUpdateStepState = "StepCompleted"
+)
+
+// CloneSetUpdateStepStatus records the progress of updateStrategy.steps.
+type CloneSetUpdateStepStatus struct {
+	// UpdateRevision is the revision that these steps are rolling out.
+	UpdateRevision string `json:"updateRevision"`
+	// CurrentStepIndex is the index of the current step in updateStrategy.steps.
+	CurrentStepIndex int32 `json:"currentStepIndex"`
+	// CurrentStepState is the state of the current step.
+	CurrentStepState CloneSetUpdateStepState `json:"currentStepState"`
+	// LastTransitionTime is the last time the current step or its state changed.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// PausedTime is the time when the update was paused during the verifying of the current step.
+	// The time paused does not count for the pauseSeconds and the timeout of checks of the step.
+	PausedTime *metav1.Time `json:"pausedTime,omitempty"`
+	// ObservedRetry is the last value of the apps.kruise.io/cloneset-retry-update-step annotation
+	// that has retried the checks of a failed step.
+	ObservedRetry string `json:"observedRetry,omitempty"`
+	// A human readable message indicating details about the current step.
+	Message string `json:"message,omitempty"`
+}
+
+// CloneSetRevisionUpdateType is the expected way to update pods from one revision to another.
+type CloneSetRevisionUpdateType string
+
+const (
+	// InPlaceCloneSetRevisionUpdateType indicates pods will be updated in-place.
+	InPlaceCloneSetRevisionUpdateType CloneSetRevisionUpdateType = "InPlace"
+	// RecreateCloneSetRevisionUpdateType indicates pods will be recreated.
+	RecreateCloneSetRevisionUpdateType CloneSetRevisionUpdateType = "Recreate"
+)
+
+// CloneSetRevisionDiff describes the changes between two revisions of CloneSet.
+type CloneSetRevisionDiff struct {
+	// CurrentRevision is the revision that the diff is calculated from.
+	CurrentRevision string `json:"currentRevision"`
+	// UpdateRevision is the revision that the diff is calculated to.
+	UpdateRevision string `json:"updateRevision"`
+	// UpdateStrategyType is the type of update strategy that the diff is calculated with.
+	UpdateStrategyType CloneSetUpdateStrategyType `json:"updateStrategyType,omitempty"`
+	// UpdateType is the expected way to update pods from currentRevision to updateRevision.
+	UpdateType CloneSetRevisionUpdateType `json:"updateType"`
+	// Containers are the changed containers that exist in both revisions.
+	Containers []CloneSetContainerDiff `json:"containers,omitempty"`
+	// Fields are the changed fields of pod template besides the containers above,
+	// such as metadata.labels, spec.volumes or spec.containers if containers are added or removed.
+	Fields []string `json:"fields,omitempty"`
+	// VolumeClaimTemplatesChanged indicates whether volumeClaimTemplates changed.
+	VolumeClaimTemplatesChanged bool `json:"volumeClaimTemplatesChanged,omitempty"`
+}
+
+// CloneSetContainerDiff describes the changed fields of a container.
+type CloneSetContainerDiff struct {
+	// Name of the container.
+	Name string `json:"name"`
+	// Fields are the changed fields of the container, such as image, env and resources.
+	Fields []string `json:"fields"`
 }
 
 // CloneSetConditionReason is type for CloneSet reasons.
@@ -241,6 +466,8 @@ const (
 	CloneSetConditionFailedUpdate CloneSetConditionType = "FailedUpdate"
 	// CloneSetConditionTypeProgressing indicates cloneset controller is progressing.
 	CloneSetConditionTypeProgressing CloneSetConditionType = "Progressing"
+	// CloneSetConditionTypeRolledBack indicates cloneset controller has rolled back the template to the current revision.
+	CloneSetConditionTypeRolledBack CloneSetConditionType = "RolledBack"
 )
 
 // CloneSetCondition describes the state of a CloneSet at a certain point.
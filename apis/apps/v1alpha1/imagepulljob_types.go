@@ -25,6 +25,10 @@ const (
 	ImagePreDownloadParallelismKey      = "apps.kruise.io/image-predownload-parallelism"
 	ImagePreDownloadTimeoutSecondsKey   = "apps.kruise.io/image-predownload-timeout-seconds"
 	ImagePreDownloadMinUpdatedReadyPods = "apps.kruise.io/image-predownload-min-updated-ready-pods"
+	// ImagePreDownloadGateUpdateKey set to "true" makes the DaemonSet update the pod on a node only after
+	// the NodeImage of this node reports the new images pre-downloaded. The pod stops waiting once the images
+	// failed or timed out to pull on the node, or the ImagePullJobs have been completed.
+	ImagePreDownloadGateUpdateKey = "apps.kruise.io/image-predownload-gate-update"
 )
 
 // ImagePullPolicy describes a policy for if/when to pull a container image
@@ -49,7 +49,10 @@ type SidecarSetSpec struct {
 
 	// InitContainers is the list of init containers to be injected into the selected pod
 	// We will inject those containers by their name in ascending order
-	// We only inject init containers when a new pod is created, it does not apply to any existing pod
+	// We only inject init containers when a new pod is created, and the updates of plain init containers
+	// do not apply to any existing pod. The native sidecar containers, whose restartPolicy is Always, are
+	// also injected into new pods only, but their updates are upgraded in-place or hot-upgraded in the
+	// existing pods as the containers.
 	// +patchMergeKey=name
 	// +patchStrategy=merge
 	InitContainers []SidecarContainer `json:"initContainers,omitempty" patchStrategy:"merge" patchMergeKey:"name"`
@@ -13,6 +13,11 @@ const (
 	// SpecifiedDeleteKey indicates this object should be deleted, and the value could be the deletion option.
 	SpecifiedDeleteKey = "apps.kruise.io/specified-delete"
 
+	// SpecifiedReplaceKey indicates this object should be replaced, which means a new one will be created
+	// before this object is deleted. The number of replacing objects is limited by updateStrategy.maxSurge,
+	// or by updateStrategy.maxUnavailable if maxSurge is 0.
+	SpecifiedReplaceKey = "apps.kruise.io/specified-replace"
+
 	// ImagePreDownloadCreatedKey indicates the images of this revision have been pre-downloaded
 	ImagePreDownloadCreatedKey = "apps.kruise.io/pre-predownload-created"
 
@@ -389,6 +389,26 @@ func (in *CloneSetCondition) DeepCopy() *CloneSetCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloneSetContainerDiff) DeepCopyInto(out *CloneSetContainerDiff) {
+	*out = *in
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneSetContainerDiff.
+func (in *CloneSetContainerDiff) DeepCopy() *CloneSetContainerDiff {
+	if in == nil {
+		return nil
+	}
+	out := new(CloneSetContainerDiff)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CloneSetList) DeepCopyInto(out *CloneSetList) {
 	*out = *in
@@ -421,6 +441,88 @@ func (in *CloneSetList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloneSetPVCSnapshotPolicy) DeepCopyInto(out *CloneSetPVCSnapshotPolicy) {
+	*out = *in
+	if in.VolumeSnapshotClassName != nil {
+		in, out := &in.VolumeSnapshotClassName, &out.VolumeSnapshotClassName
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneSetPVCSnapshotPolicy.
+func (in *CloneSetPVCSnapshotPolicy) DeepCopy() *CloneSetPVCSnapshotPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(CloneSetPVCSnapshotPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloneSetRevisionDiff) DeepCopyInto(out *CloneSetRevisionDiff) {
+	*out = *in
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]CloneSetContainerDiff, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneSetRevisionDiff.
+func (in *CloneSetRevisionDiff) DeepCopy() *CloneSetRevisionDiff {
+	if in == nil {
+		return nil
+	}
+	out := new(CloneSetRevisionDiff)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloneSetScaleDownPolicy) DeepCopyInto(out *CloneSetScaleDownPolicy) {
+	*out = *in
+	if in.Scorers != nil {
+		in, out := &in.Scorers, &out.Scorers
+		*out = make([]CloneSetScaleDownScorer, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneSetScaleDownPolicy.
+func (in *CloneSetScaleDownPolicy) DeepCopy() *CloneSetScaleDownPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(CloneSetScaleDownPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloneSetScaleDownScorer) DeepCopyInto(out *CloneSetScaleDownScorer) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneSetScaleDownScorer.
+func (in *CloneSetScaleDownScorer) DeepCopy() *CloneSetScaleDownScorer {
+	if in == nil {
+		return nil
+	}
+	out := new(CloneSetScaleDownScorer)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CloneSetScaleStrategy) DeepCopyInto(out *CloneSetScaleStrategy) {
 	*out = *in
@@ -434,6 +536,16 @@ func (in *CloneSetScaleStrategy) DeepCopyInto(out *CloneSetScaleStrategy) {
 		*out = new(intstr.IntOrString)
 		**out = **in
 	}
+	if in.ScaleDownPolicy != nil {
+		in, out := &in.ScaleDownPolicy, &out.ScaleDownPolicy
+		*out = new(CloneSetScaleDownPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PVCSnapshotPolicy != nil {
+		in, out := &in.PVCSnapshotPolicy, &out.PVCSnapshotPolicy
+		*out = new(CloneSetPVCSnapshotPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneSetScaleStrategy.
@@ -484,6 +596,11 @@ func (in *CloneSetSpec) DeepCopyInto(out *CloneSetSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.StandbyReplicas != nil {
+		in, out := &in.StandbyReplicas, &out.StandbyReplicas
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneSetSpec.
@@ -511,6 +628,16 @@ func (in *CloneSetStatus) DeepCopyInto(out *CloneSetStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.UpdateStepStatus != nil {
+		in, out := &in.UpdateStepStatus, &out.UpdateStepStatus
+		*out = new(CloneSetUpdateStepStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpdateRevisionDiff != nil {
+		in, out := &in.UpdateRevisionDiff, &out.UpdateRevisionDiff
+		*out = new(CloneSetRevisionDiff)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneSetStatus.
@@ -540,6 +667,62 @@ func (in *CloneSetTemplateSpec) DeepCopy() *CloneSetTemplateSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloneSetUpdateStep) DeepCopyInto(out *CloneSetUpdateStep) {
+	*out = *in
+	out.Partition = in.Partition
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]CloneSetUpdateStepCheck, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneSetUpdateStep.
+func (in *CloneSetUpdateStep) DeepCopy() *CloneSetUpdateStep {
+	if in == nil {
+		return nil
+	}
+	out := new(CloneSetUpdateStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloneSetUpdateStepCheck) DeepCopyInto(out *CloneSetUpdateStepCheck) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneSetUpdateStepCheck.
+func (in *CloneSetUpdateStepCheck) DeepCopy() *CloneSetUpdateStepCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(CloneSetUpdateStepCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloneSetUpdateStepStatus) DeepCopyInto(out *CloneSetUpdateStepStatus) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	if in.PausedTime != nil {
+		in, out := &in.PausedTime, &out.PausedTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneSetUpdateStepStatus.
+func (in *CloneSetUpdateStepStatus) DeepCopy() *CloneSetUpdateStepStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CloneSetUpdateStepStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CloneSetUpdateStrategy) DeepCopyInto(out *CloneSetUpdateStrategy) {
 	*out = *in
@@ -571,7 +754,21 @@ func (in *CloneSetUpdateStrategy) DeepCopyInto(out *CloneSetUpdateStrategy) {
 	if in.InPlaceUpdateStrategy != nil {
 		in, out := &in.InPlaceUpdateStrategy, &out.InPlaceUpdateStrategy
 		*out = new(pub.InPlaceUpdateStrategy)
-		**out = **in
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]CloneSetUpdateStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.UpdateWindows != nil {
+		in, out := &in.UpdateWindows, &out.UpdateWindows
+		*out = make([]CloneSetUpdateWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -585,6 +782,26 @@ func (in *CloneSetUpdateStrategy) DeepCopy() *CloneSetUpdateStrategy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloneSetUpdateWindow) DeepCopyInto(out *CloneSetUpdateWindow) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneSetUpdateWindow.
+func (in *CloneSetUpdateWindow) DeepCopy() *CloneSetUpdateWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(CloneSetUpdateWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CompletionPolicy) DeepCopyInto(out *CompletionPolicy) {
 	*out = *in
@@ -2719,7 +2936,7 @@ func (in *RollingUpdateStatefulSetStrategy) DeepCopyInto(out *RollingUpdateState
 	if in.InPlaceUpdateStrategy != nil {
 		in, out := &in.InPlaceUpdateStrategy, &out.InPlaceUpdateStrategy
 		*out = new(pub.InPlaceUpdateStrategy)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.MinReadySeconds != nil {
 		in, out := &in.MinReadySeconds, &out.MinReadySeconds
@@ -20,6 +20,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	appspub "github.com/openkruise/kruise/apis/apps/pub"
@@ -53,6 +54,16 @@ const (
 	InplaceRollingUpdateType RollingUpdateType = "InPlaceIfPossible"
 )
 
+const (
+	// DaemonSetHostPortHandedOffLabel is labeled on the old pod with value "true" once it has released
+	// its host ports to the surge pod on the same node.
+	DaemonSetHostPortHandedOffLabel = "daemonset.kruise.io/host-port-handed-off"
+
+	// DaemonSetHostPortsRemovedAnnotation is annotated on the surge pod with value "true" if it has been
+	// created without the host ports of template, and it will be replaced by a pod with the full template.
+	DaemonSetHostPortsRemovedAnnotation = "daemonset.kruise.io/host-ports-removed"
+)
+
 // Spec to control the desired behavior of daemon set rolling update.
 type RollingUpdateDaemonSet struct {
 	// Type is to specify which kind of rollingUpdate.
@@ -112,6 +123,38 @@ type RollingUpdateDaemonSet struct {
 	// daemon set controller.
 	// +optional
 	Paused *bool `json:"paused,omitempty"`
+
+	// Waves divide the nodes into groups, such as node pools, which are updated one by one.
+	// The next wave starts once all the pods in the previous wave are updated and available.
+	// A node selected by several waves belongs to the first one, and the nodes not selected by
+	// any wave are updated after all the waves.
+	// Selector and partition still work with waves, and the pods they hold back do not block the next wave.
+	// +optional
+	Waves []RollingUpdateWave `json:"waves,omitempty"`
+
+	// HostPortHandoff allows surge for the daemons using host ports, such as the node agents in host network.
+	// The surge pod is created without host ports, and the old pod is deleted only after it has handed off
+	// the ports by labeling itself with daemonset.kruise.io/host-port-handed-off=true, or become unavailable.
+	// Since the ports of pod are immutable, once the old pod is gone, the surge pod is replaced in the same way
+	// by a pod with the full template, which it has to hand off the ports to.
+	// It requires MaxSurge to be non-zero.
+	// +optional
+	HostPortHandoff bool `json:"hostPortHandoff,omitempty"`
+}
+
+// RollingUpdateWave describes a group of nodes that are updated together.
+type RollingUpdateWave struct {
+	// Name is the unique name of this wave.
+	Name string `json:"name"`
+
+	// NodeSelector is a label query over the nodes in this wave.
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector"`
+
+	// The maximum number of DaemonSet pods in this wave that can be unavailable during the update,
+	// which overrides the maxUnavailable of rollingUpdate.
+	// Value can be an absolute number (ex: 5) or a percentage of the nodes in this wave (ex: 10%).
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
 }
 
 // DaemonSetSpec defines the desired state of DaemonSet
@@ -164,6 +207,48 @@ type DaemonSetSpec struct {
 	// employed to create Pods in the DaemonSet.
 	// +optional
 	ScaleStrategy *DaemonSetScaleStrategy `json:"scaleStrategy,omitempty"`
+
+	// NodeOverrides patch the pod template for the selected nodes, such as different resources or env
+	// for different types of nodes. All the overrides matching the node are applied in order.
+	// The overrides are included in the revision, so changing them triggers a rolling update,
+	// and the pods are recreated instead of in-place updated if the overrides changed, or the overrides
+	// on their nodes patch the metadata, or the images or resources of containers.
+	// +optional
+	NodeOverrides []DaemonSetNodeOverride `json:"nodeOverrides,omitempty"`
+
+	// NodeDrainPolicy controls the pods on the nodes being drained, which are cordoned or tainted.
+	// No new pod is created on the draining nodes once it is set.
+	// +optional
+	NodeDrainPolicy *DaemonSetNodeDrainPolicy `json:"nodeDrainPolicy,omitempty"`
+}
+
+// DaemonSetNodeDrainPolicy defines how to handle the pods on the nodes being drained.
+type DaemonSetNodeDrainPolicy struct {
+	// Taints are the keys of taints that mark the nodes being drained, besides the cordoned nodes.
+	// +optional
+	Taints []string `json:"taints,omitempty"`
+
+	// EvictPods indicates the pods on the draining nodes should be deleted, which goes through
+	// the preDelete lifecycle hook if it is defined.
+	// +optional
+	EvictPods bool `json:"evictPods,omitempty"`
+
+	// WaitForOtherPods keeps the pod on the draining node until the other pods on the node are gone,
+	// excluding the pods of DaemonSets, mirror pods and the completed pods.
+	// It only works with EvictPods.
+	// +optional
+	WaitForOtherPods bool `json:"waitForOtherPods,omitempty"`
+}
+
+// DaemonSetNodeOverride patches the pod template for the nodes selected.
+type DaemonSetNodeOverride struct {
+	// NodeSelector is a label query over the nodes to which the patch applies.
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector"`
+
+	// Patch is a strategic merge patch to the pod template, similar to the patch in WorkloadSpreadSubset.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	Patch runtime.RawExtension `json:"patch"`
 }
 
 // DaemonSetScaleStrategy defines strategies for DaemonSet scaling.
@@ -233,6 +318,11 @@ type DaemonSetStatus struct {
 
 	// UpdateRevision is the controller-revision-hash, which represents the latest version of the DaemonSet.
 	UpdateRevision string `json:"updateRevision,omitempty"`
+
+	// CurrentWave is the name of the rollout wave being updated.
+	// It is empty if there is no wave or all the waves have been updated.
+	// +optional
+	CurrentWave string `json:"currentWave,omitempty"`
 }
 
 // +genclient
@@ -46,7 +46,10 @@ type SidecarSetSpec struct {
 
 	// InitContainers is the list of init containers to be injected into the selected pod
 	// We will inject those containers by their name in ascending order
-	// We only inject init containers when a new pod is created, it does not apply to any existing pod
+	// We only inject init containers when a new pod is created, and the updates of plain init containers
+	// do not apply to any existing pod. The native sidecar containers, whose restartPolicy is Always, are
+	// also injected into new pods only, but their updates are upgraded in-place or hot-upgraded in the
+	// existing pods as the containers.
 	// +patchMergeKey=name
 	// +patchStrategy=merge
 	InitContainers []SidecarContainer `json:"initContainers,omitempty" patchStrategy:"merge" patchMergeKey:"name"`
@@ -373,6 +376,54 @@ type SidecarSetUpdateStrategy struct {
 	// - Note that pods will be scattered after priority sort. So, although priority strategy and scatter strategy can be applied together, we suggest to use either one of them.
 	// - If scatterStrategy is used, we suggest to just use one term. Otherwise, the update order can be hard to understand.
 	ScatterStrategy UpdateScatterStrategy `json:"scatterStrategy,omitempty"`
+
+	// RolloutPhases divide the pods into ordered phases, such as the canary namespaces first and then the others.
+	// A pod belongs to the first phase that selects it, and the pods not selected by any phase belong to an
+	// implicit last phase. The pods of a phase are updated only after all the pods of the previous phases
+	// are updated and pass the health gates of their phases.
+	// MaxUnavailable works within each phase, and it cannot be used together with Partition.
+	// +optional
+	RolloutPhases []SidecarSetRolloutPhase `json:"rolloutPhases,omitempty"`
+}
+
+// SidecarSetRolloutPhase describes a group of pods updated together and the health gate to finish the phase.
+type SidecarSetRolloutPhase struct {
+	// Name is the unique name of this phase.
+	Name string `json:"name"`
+
+	// NamespaceSelector selects the pods in the namespaces whose labels are matched.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// Workloads select the pods controlled by the workloads.
+	// +optional
+	Workloads []SidecarSetRolloutWorkload `json:"workloads,omitempty"`
+
+	// HealthGate has to be passed by all the updated pods of this phase before the next phase starts.
+	// +optional
+	HealthGate *SidecarSetRolloutHealthGate `json:"healthGate,omitempty"`
+}
+
+// SidecarSetRolloutWorkload selects the pods by the workload owning them.
+type SidecarSetRolloutWorkload struct {
+	// Kind of the workload, such as Deployment, CloneSet and StatefulSet.
+	Kind string `json:"kind"`
+
+	// Name of the workload, and all the workloads of the kind are selected if it is empty.
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// SidecarSetRolloutHealthGate describes when the updated pods are considered healthy.
+type SidecarSetRolloutHealthGate struct {
+	// MinReadySeconds is the minimum seconds for which the updated sidecar containers should be running
+	// and the pod should be ready.
+	// +optional
+	MinReadySeconds int32 `json:"minReadySeconds,omitempty"`
+
+	// NoRestarts requires the updated sidecar containers not to have been restarted since updated.
+	// +optional
+	NoRestarts bool `json:"noRestarts,omitempty"`
 }
 
 type SidecarSetUpdateStrategyType string
@@ -407,6 +458,10 @@ type SidecarSetStatus struct {
 	// uses this field as a collision avoidance mechanism when it needs to create the name for the
 	// newest ControllerRevision.
 	CollisionCount *int32 `json:"collisionCount,omitempty"`
+
+	// RolloutPhase is the name of the rollout phase whose pods are being updated.
+	// It is empty if the pods out of all the phases are being updated or the rollout has finished.
+	RolloutPhase string `json:"rolloutPhase,omitempty"`
 }
 
 // +genclient
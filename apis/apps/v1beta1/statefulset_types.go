@@ -61,6 +61,10 @@ type VolumeClaimStatus struct {
 	// Compatibility is determined by whether the pvc spec storage requests are greater than or equal to the template spec storage requests
 	// The "ready" status is determined by whether the PVC status capacity is greater than or equal to the PVC spec storage requests.
 	CompatibleReadyReplicas int32 `json:"compatibleReadyReplicas"`
+	// MigratingReplicas is the number of replicas whose volume claims are being migrated to the new template,
+	// which happens when the storageClassName or accessModes of the template has been changed.
+	// +optional
+	MigratingReplicas int32 `json:"migratingReplicas,omitempty"`
 }
 
 // StatefulSetUpdateStrategy indicates the strategy that the StatefulSet
@@ -83,6 +87,28 @@ type VolumeClaimUpdateStrategy struct {
 	// OnPodRollingUpdateVolumeClaimUpdateStrategyType: Apply the update strategy during pod rolling updates.
 	// OnPVCDeleteVolumeClaimUpdateStrategyType: Apply the update strategy when a PersistentVolumeClaim is deleted.
 	Type VolumeClaimUpdateStrategyType `json:"type,omitempty"`
+	// Migration enables migrating the data of volume claims to new ones during the pod recreation,
+	// when the storageClassName or accessModes in volumeClaimTemplates changed, which can not be updated in place.
+	// The size can be changed together, but the other fields of volumeClaimTemplates can not.
+	// It only works with OnPodRollingUpdate type.
+	// +optional
+	Migration *VolumeClaimMigrationStrategy `json:"migration,omitempty"`
+}
+
+// VolumeClaimMigrationStrategy defines how to copy data from the old volume claim to the new one.
+// The controller creates a new claim from the template and a Job mounting the old claim at /source
+// and the new claim at /target, then swaps the pod onto the new claim after the Job succeeded.
+type VolumeClaimMigrationStrategy struct {
+	// Image is the image of the copy Job.
+	Image string `json:"image"`
+	// Command is the command of the copy Job.
+	// Defaults to ["sh", "-c", "cp -a /source/. /target/"].
+	// +optional
+	Command []string `json:"command,omitempty"`
+	// BackoffLimit is the number of retries of the copy Job before it is marked as failed.
+	// Defaults to 6.
+	// +optional
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
 }
 
 // RollingUpdateStatefulSetStrategy is used to communicate parameter for RollingUpdateStatefulSetStrategyType.
@@ -92,6 +118,13 @@ type RollingUpdateStatefulSetStrategy struct {
 	// Default value is 0.
 	// +optional
 	Partition *int32 `json:"partition,omitempty"`
+	// PartitionOrdinals indicates the ordinals of pods that should be kept at the current revision,
+	// and all the other pods will be updated. It takes precedence over partition if not empty.
+	// You can also use ranges along with numbers, such as [1, 3-5], which is a shortcut for [1, 3, 4, 5].
+	// For example, to update a shard group of ordinals 0-9 first, just set it to ["10-99"],
+	// and then remove the next shard group from it batch by batch.
+	// +optional
+	PartitionOrdinals []intstr.IntOrString `json:"partitionOrdinals,omitempty"`
 	// The maximum number of pods that can be unavailable during the update.
 	// Value can be an absolute number (ex: 5) or a percentage of desired pods (ex: 10%).
 	// Absolute number is calculated from percentage by rounding down.
@@ -122,8 +155,36 @@ type RollingUpdateStatefulSetStrategy struct {
 	// Default value is 0, max is 300.
 	// +optional
 	MinReadySeconds *int32 `json:"minReadySeconds,omitempty"`
+	// RoleAwareUpdate contains strategies to update pods by their roles.
+	// If it is not nil, the followers will be updated first and the leader will be updated last.
+	// +optional
+	RoleAwareUpdate *RoleAwareUpdateStrategy `json:"roleAwareUpdate,omitempty"`
+}
+
+// RoleAwareUpdateStrategy defines how to recognize the leader pod and how to update it.
+// Exactly one of leaderSelector and leaderConditionType should be set.
+type RoleAwareUpdateStrategy struct {
+	// LeaderSelector selects the leader pod by its labels,
+	// such as the labels patched by the markerPolicy of PodProbeMarker.
+	// +optional
+	LeaderSelector *metav1.LabelSelector `json:"leaderSelector,omitempty"`
+	// LeaderConditionType is the type of pod condition that records the result of a role probe,
+	// such as the podConditionType of PodProbeMarker. The pod with this condition True is the leader.
+	// +optional
+	LeaderConditionType v1.PodConditionType `json:"leaderConditionType,omitempty"`
+	// SwitchoverHook is the hook to switch the leader over before it is updated.
+	// When the leader is about to update, the controller annotates it with apps.kruise.io/switchover-requested,
+	// and waits until the pod is no longer hooked, which means the switchover has been acknowledged.
+	// +optional
+	SwitchoverHook *appspub.LifecycleHook `json:"switchoverHook,omitempty"`
 }
 
+const (
+	// SwitchoverRequestedAnnotationKey is added to the leader pod with the update revision as value,
+	// when it is waiting for switchover before updating.
+	SwitchoverRequestedAnnotationKey = "apps.kruise.io/switchover-requested"
+)
+
 // UnorderedUpdateStrategy defines strategies for non-ordered update.
 type UnorderedUpdateStrategy struct {
 	// Priorities are the rules for calculating the priority of updating pods.
@@ -184,6 +245,32 @@ type StatefulSetPersistentVolumeClaimRetentionPolicy struct {
 	WhenScaled PersistentVolumeClaimRetentionPolicyType `json:"whenScaled,omitempty"`
 }
 
+const (
+	// PVCRetentionPolicyWhenScaledAnnotationKey can be added to a pod with value Retain or Delete,
+	// which overrides the whenScaled policy of persistentVolumeClaimRetentionPolicy for the PVCs of this ordinal.
+	// For example, set it to Delete before scaling in to clean up the corrupted disk of this ordinal.
+	PVCRetentionPolicyWhenScaledAnnotationKey = "apps.kruise.io/pvc-retention-policy-when-scaled"
+	// PVCRetentionPolicyWhenDeletedAnnotationKey can be added to a pod with value Retain or Delete,
+	// which overrides the whenDeleted policy of persistentVolumeClaimRetentionPolicy for the PVCs of this ordinal.
+	PVCRetentionPolicyWhenDeletedAnnotationKey = "apps.kruise.io/pvc-retention-policy-when-deleted"
+)
+
+// StatefulSetStartupDependency describes the pods that wait for their dependencies before they are created.
+// At least one of dependsOnOrdinals and dependsOnSelector should be set.
+type StatefulSetStartupDependency struct {
+	// Ordinals are the ordinals of pods waiting for the dependencies, with ranges such as [1, 3-5].
+	// If empty, all the pods except the dependencies will wait.
+	// +optional
+	Ordinals []intstr.IntOrString `json:"ordinals,omitempty"`
+	// DependsOnOrdinals are the ordinals of pods that have to be running and available first,
+	// with ranges such as [1, 3-5].
+	// +optional
+	DependsOnOrdinals []intstr.IntOrString `json:"dependsOnOrdinals,omitempty"`
+	// DependsOnSelector selects the pods in this StatefulSet that have to be running and available first.
+	// +optional
+	DependsOnSelector *metav1.LabelSelector `json:"dependsOnSelector,omitempty"`
+}
+
 // StatefulSetOrdinals describes the policy used for replica ordinal assignment
 // in this StatefulSet.
 type StatefulSetOrdinals struct {
@@ -197,6 +284,23 @@ type StatefulSetOrdinals struct {
 	//   [0, .spec.replicas).
 	// +optional
 	Start int32 `json:"start" protobuf:"varint,1,opt,name=start"`
+	// coordination makes the StatefulSet share the ordinal space with other StatefulSets using the same ConfigMap,
+	// so that the ordinals occupied by one of them will be reserved by the others, and they never collide
+	// while replicas are moved from one StatefulSet to another, such as migrating to a new node pool.
+	// +optional
+	Coordination *StatefulSetOrdinalsCoordination `json:"coordination,omitempty"`
+}
+
+// StatefulSetOrdinalsCoordination describes how the ordinal space is shared between StatefulSets.
+type StatefulSetOrdinalsCoordination struct {
+	// configMapName is the name of the ConfigMap shared by the StatefulSets.
+	// The controller creates it if not exists, and records the ordinals occupied by each StatefulSet
+	// in its data with "<namespace>.<name>" of StatefulSet as key, such as "0-3,5".
+	ConfigMapName string `json:"configMapName"`
+	// namespace is the namespace of the ConfigMap, which defaults to the namespace of the StatefulSet.
+	// It allows the StatefulSets in different namespaces to share the ordinal space.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
 }
 
 // StatefulSetSpec defines the desired state of StatefulSet
@@ -257,6 +361,13 @@ type StatefulSetSpec struct {
 	// +optional
 	PodManagementPolicy apps.PodManagementPolicyType `json:"podManagementPolicy,omitempty"`
 
+	// startupDependencies declares the pods that have to be running and available before some other pods
+	// are created, which only works with `Parallel` podManagementPolicy.
+	// For example, to create the seed pods 0-2 before others and then create the others in parallel,
+	// just set it to [{dependsOnOrdinals: ["0-2"]}].
+	// +optional
+	StartupDependencies []StatefulSetStartupDependency `json:"startupDependencies,omitempty"`
+
 	// updateStrategy indicates the StatefulSetUpdateStrategy that will be
 	// employed to update Pods in the StatefulSet when a revision is made to
 	// Template.
@@ -375,6 +486,9 @@ type StatefulSetStatus struct {
 const (
 	FailedCreatePod apps.StatefulSetConditionType = "FailedCreatePod"
 	FailedUpdatePod apps.StatefulSetConditionType = "FailedUpdatePod"
+	// PodUnavailableBudgetThrottled means the rolling update is throttled by the PodUnavailableBudget
+	// protecting the pods, which allows fewer unavailable pods than maxUnavailable.
+	PodUnavailableBudgetThrottled apps.StatefulSetConditionType = "PodUnavailableBudgetThrottled"
 )
 
 // +genclient
@@ -397,6 +397,47 @@ func (in *DaemonSetList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DaemonSetNodeDrainPolicy) DeepCopyInto(out *DaemonSetNodeDrainPolicy) {
+	*out = *in
+	if in.Taints != nil {
+		in, out := &in.Taints, &out.Taints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DaemonSetNodeDrainPolicy.
+func (in *DaemonSetNodeDrainPolicy) DeepCopy() *DaemonSetNodeDrainPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(DaemonSetNodeDrainPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DaemonSetNodeOverride) DeepCopyInto(out *DaemonSetNodeOverride) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Patch.DeepCopyInto(&out.Patch)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DaemonSetNodeOverride.
+func (in *DaemonSetNodeOverride) DeepCopy() *DaemonSetNodeOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(DaemonSetNodeOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DaemonSetScaleStrategy) DeepCopyInto(out *DaemonSetScaleStrategy) {
 	*out = *in
@@ -442,6 +483,18 @@ func (in *DaemonSetSpec) DeepCopyInto(out *DaemonSetSpec) {
 		*out = new(DaemonSetScaleStrategy)
 		**out = **in
 	}
+	if in.NodeOverrides != nil {
+		in, out := &in.NodeOverrides, &out.NodeOverrides
+		*out = make([]DaemonSetNodeOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeDrainPolicy != nil {
+		in, out := &in.NodeDrainPolicy, &out.NodeDrainPolicy
+		*out = new(DaemonSetNodeDrainPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DaemonSetSpec.
@@ -1107,6 +1160,31 @@ func (in *ResourcesPolicy) DeepCopy() *ResourcesPolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoleAwareUpdateStrategy) DeepCopyInto(out *RoleAwareUpdateStrategy) {
+	*out = *in
+	if in.LeaderSelector != nil {
+		in, out := &in.LeaderSelector, &out.LeaderSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SwitchoverHook != nil {
+		in, out := &in.SwitchoverHook, &out.SwitchoverHook
+		*out = new(pub.LifecycleHook)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleAwareUpdateStrategy.
+func (in *RoleAwareUpdateStrategy) DeepCopy() *RoleAwareUpdateStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(RoleAwareUpdateStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RollingUpdateDaemonSet) DeepCopyInto(out *RollingUpdateDaemonSet) {
 	*out = *in
@@ -1135,6 +1213,13 @@ func (in *RollingUpdateDaemonSet) DeepCopyInto(out *RollingUpdateDaemonSet) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Waves != nil {
+		in, out := &in.Waves, &out.Waves
+		*out = make([]RollingUpdateWave, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollingUpdateDaemonSet.
@@ -1155,6 +1240,11 @@ func (in *RollingUpdateStatefulSetStrategy) DeepCopyInto(out *RollingUpdateState
 		*out = new(int32)
 		**out = **in
 	}
+	if in.PartitionOrdinals != nil {
+		in, out := &in.PartitionOrdinals, &out.PartitionOrdinals
+		*out = make([]intstr.IntOrString, len(*in))
+		copy(*out, *in)
+	}
 	if in.MaxUnavailable != nil {
 		in, out := &in.MaxUnavailable, &out.MaxUnavailable
 		*out = new(intstr.IntOrString)
@@ -1168,13 +1258,18 @@ func (in *RollingUpdateStatefulSetStrategy) DeepCopyInto(out *RollingUpdateState
 	if in.InPlaceUpdateStrategy != nil {
 		in, out := &in.InPlaceUpdateStrategy, &out.InPlaceUpdateStrategy
 		*out = new(pub.InPlaceUpdateStrategy)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.MinReadySeconds != nil {
 		in, out := &in.MinReadySeconds, &out.MinReadySeconds
 		*out = new(int32)
 		**out = **in
 	}
+	if in.RoleAwareUpdate != nil {
+		in, out := &in.RoleAwareUpdate, &out.RoleAwareUpdate
+		*out = new(RoleAwareUpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollingUpdateStatefulSetStrategy.
@@ -1187,6 +1282,31 @@ func (in *RollingUpdateStatefulSetStrategy) DeepCopy() *RollingUpdateStatefulSet
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollingUpdateWave) DeepCopyInto(out *RollingUpdateWave) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollingUpdateWave.
+func (in *RollingUpdateWave) DeepCopy() *RollingUpdateWave {
+	if in == nil {
+		return nil
+	}
+	out := new(RollingUpdateWave)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SandboxConfig) DeepCopyInto(out *SandboxConfig) {
 	*out = *in
@@ -1407,6 +1527,66 @@ func (in *SidecarSetPatchPodMetadata) DeepCopy() *SidecarSetPatchPodMetadata {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarSetRolloutHealthGate) DeepCopyInto(out *SidecarSetRolloutHealthGate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarSetRolloutHealthGate.
+func (in *SidecarSetRolloutHealthGate) DeepCopy() *SidecarSetRolloutHealthGate {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarSetRolloutHealthGate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarSetRolloutPhase) DeepCopyInto(out *SidecarSetRolloutPhase) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Workloads != nil {
+		in, out := &in.Workloads, &out.Workloads
+		*out = make([]SidecarSetRolloutWorkload, len(*in))
+		copy(*out, *in)
+	}
+	if in.HealthGate != nil {
+		in, out := &in.HealthGate, &out.HealthGate
+		*out = new(SidecarSetRolloutHealthGate)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarSetRolloutPhase.
+func (in *SidecarSetRolloutPhase) DeepCopy() *SidecarSetRolloutPhase {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarSetRolloutPhase)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarSetRolloutWorkload) DeepCopyInto(out *SidecarSetRolloutWorkload) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarSetRolloutWorkload.
+func (in *SidecarSetRolloutWorkload) DeepCopy() *SidecarSetRolloutWorkload {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarSetRolloutWorkload)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SidecarSetSpec) DeepCopyInto(out *SidecarSetSpec) {
 	*out = *in
@@ -1520,6 +1700,13 @@ func (in *SidecarSetUpdateStrategy) DeepCopyInto(out *SidecarSetUpdateStrategy)
 		*out = make(UpdateScatterStrategy, len(*in))
 		copy(*out, *in)
 	}
+	if in.RolloutPhases != nil {
+		in, out := &in.RolloutPhases, &out.RolloutPhases
+		*out = make([]SidecarSetRolloutPhase, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarSetUpdateStrategy.
@@ -1614,6 +1801,11 @@ func (in *StatefulSetList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StatefulSetOrdinals) DeepCopyInto(out *StatefulSetOrdinals) {
 	*out = *in
+	if in.Coordination != nil {
+		in, out := &in.Coordination, &out.Coordination
+		*out = new(StatefulSetOrdinalsCoordination)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatefulSetOrdinals.
@@ -1626,6 +1818,21 @@ func (in *StatefulSetOrdinals) DeepCopy() *StatefulSetOrdinals {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatefulSetOrdinalsCoordination) DeepCopyInto(out *StatefulSetOrdinalsCoordination) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatefulSetOrdinalsCoordination.
+func (in *StatefulSetOrdinalsCoordination) DeepCopy() *StatefulSetOrdinalsCoordination {
+	if in == nil {
+		return nil
+	}
+	out := new(StatefulSetOrdinalsCoordination)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StatefulSetPersistentVolumeClaimRetentionPolicy) DeepCopyInto(out *StatefulSetPersistentVolumeClaimRetentionPolicy) {
 	*out = *in
@@ -1682,7 +1889,14 @@ func (in *StatefulSetSpec) DeepCopyInto(out *StatefulSetSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	out.VolumeClaimUpdateStrategy = in.VolumeClaimUpdateStrategy
+	in.VolumeClaimUpdateStrategy.DeepCopyInto(&out.VolumeClaimUpdateStrategy)
+	if in.StartupDependencies != nil {
+		in, out := &in.StartupDependencies, &out.StartupDependencies
+		*out = make([]StatefulSetStartupDependency, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	in.UpdateStrategy.DeepCopyInto(&out.UpdateStrategy)
 	if in.RevisionHistoryLimit != nil {
 		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
@@ -1712,7 +1926,7 @@ func (in *StatefulSetSpec) DeepCopyInto(out *StatefulSetSpec) {
 	if in.Ordinals != nil {
 		in, out := &in.Ordinals, &out.Ordinals
 		*out = new(StatefulSetOrdinals)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -1726,6 +1940,36 @@ func (in *StatefulSetSpec) DeepCopy() *StatefulSetSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatefulSetStartupDependency) DeepCopyInto(out *StatefulSetStartupDependency) {
+	*out = *in
+	if in.Ordinals != nil {
+		in, out := &in.Ordinals, &out.Ordinals
+		*out = make([]intstr.IntOrString, len(*in))
+		copy(*out, *in)
+	}
+	if in.DependsOnOrdinals != nil {
+		in, out := &in.DependsOnOrdinals, &out.DependsOnOrdinals
+		*out = make([]intstr.IntOrString, len(*in))
+		copy(*out, *in)
+	}
+	if in.DependsOnSelector != nil {
+		in, out := &in.DependsOnSelector, &out.DependsOnSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatefulSetStartupDependency.
+func (in *StatefulSetStartupDependency) DeepCopy() *StatefulSetStartupDependency {
+	if in == nil {
+		return nil
+	}
+	out := new(StatefulSetStartupDependency)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StatefulSetStatus) DeepCopyInto(out *StatefulSetStatus) {
 	*out = *in
@@ -1873,6 +2117,31 @@ func (in *UpdateScatterTerm) DeepCopy() *UpdateScatterTerm {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeClaimMigrationStrategy) DeepCopyInto(out *VolumeClaimMigrationStrategy) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BackoffLimit != nil {
+		in, out := &in.BackoffLimit, &out.BackoffLimit
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeClaimMigrationStrategy.
+func (in *VolumeClaimMigrationStrategy) DeepCopy() *VolumeClaimMigrationStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeClaimMigrationStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VolumeClaimStatus) DeepCopyInto(out *VolumeClaimStatus) {
 	*out = *in
@@ -1891,6 +2160,11 @@ func (in *VolumeClaimStatus) DeepCopy() *VolumeClaimStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VolumeClaimUpdateStrategy) DeepCopyInto(out *VolumeClaimUpdateStrategy) {
 	*out = *in
+	if in.Migration != nil {
+		in, out := &in.Migration, &out.Migration
+		*out = new(VolumeClaimMigrationStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeClaimUpdateStrategy.
@@ -228,6 +228,41 @@ func checkAndDecrement(podName string, pub *policyv1alpha1.PodUnavailableBudget,
 	return nil
 }
 
+// GetPubsUnavailableAllowed returns the PodUnavailableBudgets protecting the pods from the operation, keyed by name.
+// The pods may be protected by different pubs, and the status.unavailableAllowed of each returned pub is how many more
+// of its pods can be unavailable. Workload controllers use it to check the budgets in advance instead of being rejected
+// by the webhook, and the returned pubs are copies whose quota can be consumed by the callers.
+func GetPubsUnavailableAllowed(pods []*corev1.Pod, operation policyv1alpha1.PubOperation) (map[string]*policyv1alpha1.PodUnavailableBudget, error) {
+	pubs := map[string]*policyv1alpha1.PodUnavailableBudget{}
+	checked := sets.New[string]()
+	for _, pod := range pods {
+		if pod == nil || pod.Annotations[PodRelatedPubAnnotation] == "" || checked.Has(pod.Annotations[PodRelatedPubAnnotation]) {
+			continue
+		}
+		checked.Insert(pod.Annotations[PodRelatedPubAnnotation])
+		pub, err := PubControl.GetPubForPod(pod)
+		if err != nil {
+			return nil, err
+		}
+		if pub == nil || pub.Status.DesiredAvailable == 0 || !isNeedPubProtection(pub, operation) {
+			continue
+		}
+		pubs[pub.Name] = pub.DeepCopy()
+	}
+	return pubs, nil
+}
+
+// IsPodConsumingPubQuota indicates whether making the pod unavailable consumes the quota of pub,
+// which is consistent with the check in PodUnavailableBudgetValidatePod.
+func IsPodConsumingPubQuota(pod *corev1.Pod, pub *policyv1alpha1.PodUnavailableBudget) bool {
+	if pod.Annotations[policyv1alpha1.PodPubNoProtectionAnnotation] == "true" {
+		return false
+	} else if !PubControl.IsPodReady(pod) || !PubControl.IsPodStateConsistent(pod) {
+		return false
+	}
+	return pod.Annotations[PodRelatedPubAnnotation] == pub.Name && !isPodRecordedInPub(pod.Name, pub)
+}
+
 func isPodRecordedInPub(podName string, pub *policyv1alpha1.PodUnavailableBudget) bool {
 	if _, ok := pub.Status.UnavailablePods[podName]; ok {
 		return true
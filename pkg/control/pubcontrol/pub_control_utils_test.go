@@ -607,3 +607,47 @@ func TestIsNeedPubProtection(t *testing.T) {
 		})
 	}
 }
+
+func TestGetPubsUnavailableAllowed(t *testing.T) {
+	pubIn := pubDemo.DeepCopy()
+	pubIn.Status.UnavailableAllowed = 2
+	pubIn.Status.UnavailablePods = map[string]metav1.Time{"recorded-pod": metav1.Now()}
+	otherPub := pubDemo.DeepCopy()
+	otherPub.Name = "pub-other"
+	otherPub.Status.UnavailableAllowed = 0
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pubIn, otherPub).Build()
+	InitPubControl(fakeClient, &controllerfinder.ControllerFinder{Client: fakeClient}, record.NewFakeRecorder(10))
+
+	unprotected := podDemo.DeepCopy()
+	unprotected.Annotations = map[string]string{}
+	otherPod := podDemo.DeepCopy()
+	otherPod.Name = "other-pod"
+	otherPod.Annotations[PodRelatedPubAnnotation] = otherPub.Name
+	pubs, err := GetPubsUnavailableAllowed([]*corev1.Pod{nil, unprotected, podDemo.DeepCopy(), otherPod, podDemo.DeepCopy()}, policyv1alpha1.PubUpdateOperation)
+	if err != nil || len(pubs) != 2 {
+		t.Fatalf("expected 2 pubs, got %v, %v", pubs, err)
+	}
+	if pubs[pubIn.Name].Status.UnavailableAllowed != 2 || pubs[otherPub.Name].Status.UnavailableAllowed != 0 {
+		t.Fatalf("expected unavailable allowed of each pub, got %d and %d",
+			pubs[pubIn.Name].Status.UnavailableAllowed, pubs[otherPub.Name].Status.UnavailableAllowed)
+	}
+	pub := pubs[pubIn.Name]
+	if none, err := GetPubsUnavailableAllowed([]*corev1.Pod{unprotected}, policyv1alpha1.PubUpdateOperation); err != nil || len(none) != 0 {
+		t.Fatalf("expected no pub, got %v, %v", none, err)
+	}
+
+	if !IsPodConsumingPubQuota(podDemo, pub) {
+		t.Fatalf("expected ready pod consuming pub quota")
+	}
+	recorded := podDemo.DeepCopy()
+	recorded.Name = "recorded-pod"
+	noProtect := podDemo.DeepCopy()
+	noProtect.Annotations[policyv1alpha1.PodPubNoProtectionAnnotation] = "true"
+	notReady := podDemo.DeepCopy()
+	notReady.Status.Conditions[0].Status = corev1.ConditionFalse
+	for _, pod := range []*corev1.Pod{recorded, noProtect, notReady} {
+		if IsPodConsumingPubQuota(pod, pub) {
+			t.Fatalf("expected pod %s not consuming pub quota", pod.Name)
+		}
+	}
+}
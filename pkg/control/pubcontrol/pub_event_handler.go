@@ -0,0 +1,95 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubcontrol
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	policyv1alpha1 "github.com/openkruise/kruise/apis/policy/v1alpha1"
+)
+
+// EnqueueWorkloadsForPub enqueues the workloads of GroupKind protected by a PodUnavailableBudget,
+// once the pub allows more unavailable pods or is deleted, so that the updates throttled by it can go on.
+type EnqueueWorkloadsForPub struct {
+	client.Reader
+	GroupKind schema.GroupKind
+}
+
+var _ handler.TypedEventHandler[*policyv1alpha1.PodUnavailableBudget, reconcile.Request] = &EnqueueWorkloadsForPub{}
+
+func (e *EnqueueWorkloadsForPub) Create(ctx context.Context, evt event.TypedCreateEvent[*policyv1alpha1.PodUnavailableBudget], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+}
+
+func (e *EnqueueWorkloadsForPub) Update(ctx context.Context, evt event.TypedUpdateEvent[*policyv1alpha1.PodUnavailableBudget], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	if evt.ObjectNew.Status.UnavailableAllowed <= evt.ObjectOld.Status.UnavailableAllowed {
+		return
+	}
+	e.enqueueWorkloads(ctx, evt.ObjectNew, q)
+}
+
+func (e *EnqueueWorkloadsForPub) Delete(ctx context.Context, evt event.TypedDeleteEvent[*policyv1alpha1.PodUnavailableBudget], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	e.enqueueWorkloads(ctx, evt.Object, q)
+}
+
+func (e *EnqueueWorkloadsForPub) Generic(ctx context.Context, evt event.TypedGenericEvent[*policyv1alpha1.PodUnavailableBudget], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+}
+
+func (e *EnqueueWorkloadsForPub) enqueueWorkloads(ctx context.Context, pub *policyv1alpha1.PodUnavailableBudget, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	if ref := pub.Spec.TargetReference; ref != nil {
+		if gv, err := schema.ParseGroupVersion(ref.APIVersion); err == nil && gv.Group == e.GroupKind.Group && ref.Kind == e.GroupKind.Kind {
+			q.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: pub.Namespace, Name: ref.Name}})
+		}
+		return
+	}
+	if pub.Spec.Selector == nil {
+		return
+	}
+	selector, err := metav1.LabelSelectorAsSelector(pub.Spec.Selector)
+	if err != nil {
+		return
+	}
+	podList := &corev1.PodList{}
+	if err = e.List(ctx, podList, &client.ListOptions{Namespace: pub.Namespace, LabelSelector: selector}); err != nil {
+		klog.ErrorS(err, "Failed to list pods for PodUnavailableBudget", "pub", klog.KObj(pub))
+		return
+	}
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.Annotations[PodRelatedPubAnnotation] != pub.Name {
+			continue
+		}
+		ref := metav1.GetControllerOf(pod)
+		if ref == nil || ref.Kind != e.GroupKind.Kind {
+			continue
+		}
+		if gv, err := schema.ParseGroupVersion(ref.APIVersion); err != nil || gv.Group != e.GroupKind.Group {
+			continue
+		}
+		q.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: pod.Namespace, Name: ref.Name}})
+	}
+}
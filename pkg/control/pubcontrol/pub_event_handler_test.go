@@ -0,0 +1,120 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubcontrol
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	policyv1alpha1 "github.com/openkruise/kruise/apis/policy/v1alpha1"
+)
+
+func TestEnqueueWorkloadsForPub(t *testing.T) {
+	otherPod := podDemo.DeepCopy()
+	otherPod.Name = "other-pod"
+	otherPod.Annotations[PodRelatedPubAnnotation] = "pub-other"
+	otherPod.OwnerReferences[0].Name = "other"
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(podDemo.DeepCopy(), otherPod).Build()
+	enqueueHandler := &EnqueueWorkloadsForPub{Reader: fakeClient, GroupKind: schema.GroupKind{Group: "apps", Kind: "ReplicaSet"}}
+	nginx := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "nginx"}}
+
+	cases := []struct {
+		name     string
+		getOld   func() *policyv1alpha1.PodUnavailableBudget
+		getNew   func() *policyv1alpha1.PodUnavailableBudget
+		expected []reconcile.Request
+	}{
+		{
+			name: "quota released, enqueue the owners of pods related to pub",
+			getOld: func() *policyv1alpha1.PodUnavailableBudget {
+				return pubDemo.DeepCopy()
+			},
+			getNew: func() *policyv1alpha1.PodUnavailableBudget {
+				pub := pubDemo.DeepCopy()
+				pub.Status.UnavailableAllowed = 1
+				return pub
+			},
+			expected: []reconcile.Request{nginx},
+		},
+		{
+			name: "quota not released",
+			getOld: func() *policyv1alpha1.PodUnavailableBudget {
+				pub := pubDemo.DeepCopy()
+				pub.Status.UnavailableAllowed = 1
+				return pub
+			},
+			getNew: func() *policyv1alpha1.PodUnavailableBudget {
+				return pubDemo.DeepCopy()
+			},
+		},
+		{
+			name: "quota released, enqueue the target reference",
+			getOld: func() *policyv1alpha1.PodUnavailableBudget {
+				return pubDemo.DeepCopy()
+			},
+			getNew: func() *policyv1alpha1.PodUnavailableBudget {
+				pub := pubDemo.DeepCopy()
+				pub.Spec.Selector = nil
+				pub.Spec.TargetReference = &policyv1alpha1.TargetReference{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web"}
+				pub.Status.UnavailableAllowed = 1
+				return pub
+			},
+			expected: []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "default", Name: "web"}}},
+		},
+		{
+			name: "quota released, target reference of another kind",
+			getOld: func() *policyv1alpha1.PodUnavailableBudget {
+				return pubDemo.DeepCopy()
+			},
+			getNew: func() *policyv1alpha1.PodUnavailableBudget {
+				pub := pubDemo.DeepCopy()
+				pub.Spec.Selector = nil
+				pub.Spec.TargetReference = &policyv1alpha1.TargetReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"}
+				pub.Status.UnavailableAllowed = 1
+				return pub
+			},
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+			enqueueHandler.Update(context.TODO(), event.TypedUpdateEvent[*policyv1alpha1.PodUnavailableBudget]{ObjectOld: cs.getOld(), ObjectNew: cs.getNew()}, q)
+			if q.Len() != len(cs.expected) {
+				t.Fatalf("expected %d requests, got %d", len(cs.expected), q.Len())
+			}
+			for _, expected := range cs.expected {
+				if req, _ := q.Get(); req != expected {
+					t.Fatalf("expected request %v, got %v", expected, req)
+				}
+			}
+		})
+	}
+
+	q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	enqueueHandler.Delete(context.TODO(), event.TypedDeleteEvent[*policyv1alpha1.PodUnavailableBudget]{Object: pubDemo.DeepCopy()}, q)
+	if req, _ := q.Get(); q.Len() != 0 || req != nginx {
+		t.Fatalf("expected request %v on pub deleted, got %v", nginx, req)
+	}
+}
@@ -76,13 +76,13 @@ func (c *commonControl) IsPodReady(pod *v1.Pod) bool {
 	// check whether hot upgrade is complete
 	// map[string]string: {empty container name}->{sidecarSet.spec.containers[x].upgradeStrategy.HotUpgradeEmptyImage}
 	emptyContainers := map[string]string{}
-	for _, sidecarContainer := range sidecarSet.Spec.Containers {
+	for _, sidecarContainer := range GetSidecarSetContainersWithNativeSidecars(sidecarSet) {
 		if IsHotUpgradeContainer(&sidecarContainer) {
 			_, emptyContainer := GetPodHotUpgradeContainers(sidecarContainer.Name, pod)
 			emptyContainers[emptyContainer] = sidecarContainer.UpgradeStrategy.HotUpgradeEmptyImage
 		}
 	}
-	for _, container := range pod.Spec.Containers {
+	for _, container := range GetPodContainersWithNativeSidecars(pod) {
 		// If container is empty container, then its image must be empty image
 		if emptyImage := emptyContainers[container.Name]; emptyImage != "" && container.Image != emptyImage {
 			klog.V(5).InfoS("Pod sidecar empty container image wasn't empty image", "pod", klog.KObj(pod),
@@ -120,9 +120,10 @@ func (c *commonControl) UpdatePodAnnotationsInUpgrade(changedContainers []string
 		inPlaceUpdateState.LastContainerStatuses = make(map[string]pub.InPlaceUpdateContainerStatus)
 	}
 
-	cStatus := make(map[string]string, len(pod.Status.ContainerStatuses))
-	for i := range pod.Status.ContainerStatuses {
-		c := &pod.Status.ContainerStatuses[i]
+	containerStatuses := GetPodContainerStatusesWithNativeSidecars(pod)
+	cStatus := make(map[string]string, len(containerStatuses))
+	for i := range containerStatuses {
+		c := &containerStatuses[i]
 		cStatus[c.Name] = c.ImageID
 	}
 	for _, cName := range changedContainers {
@@ -152,7 +153,7 @@ func (c *commonControl) IsPodStateConsistent(pod *v1.Pod, sidecarContainers sets
 
 	allDigestImage := true
 	cImageIDs := util.GetPodContainerImageIDs(pod)
-	for _, container := range pod.Spec.Containers {
+	for _, container := range GetPodContainersWithNativeSidecars(pod) {
 		// only check whether sidecar container is consistent
 		if !sidecarContainers.Has(container.Name) {
 			continue
@@ -192,7 +193,7 @@ func (c *commonControl) IsSidecarSetUpgradable(pod *v1.Pod) (canUpgrade, consist
 
 	// cStatus: container.name -> containerStatus.Ready
 	cStatus := map[string]bool{}
-	for _, status := range pod.Status.ContainerStatuses {
+	for _, status := range GetPodContainerStatusesWithNativeSidecars(pod) {
 		cStatus[status.Name] = status.Ready
 	}
 	sidecarContainerList := GetSidecarContainersInPod(sidecarSet)
@@ -241,12 +242,11 @@ func IsSidecarContainerUpdateCompleted(pod *v1.Pod, sidecarSets, containers sets
 	}
 
 	containerImages := make(map[string]string, len(pod.Spec.Containers))
-	for i := range pod.Spec.Containers {
-		c := &pod.Spec.Containers[i]
+	for _, c := range GetPodContainersWithNativeSidecars(pod) {
 		containerImages[c.Name] = c.Image
 	}
 
-	for _, cs := range pod.Status.ContainerStatuses {
+	for _, cs := range GetPodContainerStatusesWithNativeSidecars(pod) {
 		// only check containers set
 		if !containers.Has(cs.Name) {
 			continue
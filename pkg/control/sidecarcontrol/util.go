@@ -263,7 +263,7 @@ func UpdatePodSidecarSetHash(pod *corev1.Pod, sidecarSet *appsv1beta1.SidecarSet
 
 func GetSidecarContainersInPod(sidecarSet *appsv1beta1.SidecarSet) sets.String {
 	names := sets.NewString()
-	for _, sidecarContainer := range sidecarSet.Spec.Containers {
+	for _, sidecarContainer := range GetSidecarSetContainersWithNativeSidecars(sidecarSet) {
 		if IsHotUpgradeContainer(&sidecarContainer) {
 			name1, name2 := GetHotUpgradeContainerName(sidecarContainer.Name)
 			names.Insert(name2)
@@ -292,8 +292,9 @@ func IsPodInjectedSidecarSet(pod *corev1.Pod, sidecarSet *appsv1beta1.SidecarSet
 }
 
 func IsPodConsistentWithSidecarSet(pod *corev1.Pod, sidecarSet *appsv1beta1.SidecarSet) bool {
-	for i := range sidecarSet.Spec.Containers {
-		container := &sidecarSet.Spec.Containers[i]
+	containers := GetSidecarSetContainersWithNativeSidecars(sidecarSet)
+	for i := range containers {
+		container := &containers[i]
 		switch container.UpgradeStrategy.UpgradeType {
 		case appsv1beta1.SidecarContainerHotUpgrade:
 			_, exist := GetPodHotUpgradeInfoInAnnotations(pod)[container.Name]
@@ -610,6 +611,50 @@ func IsSidecarContainer(container corev1.Container) bool {
 	return false
 }
 
+// GetPodContainersWithNativeSidecars returns the containers and the native sidecar initContainers of pod,
+// since the sidecar containers can be injected as native sidecar initContainers.
+func GetPodContainersWithNativeSidecars(pod *corev1.Pod) []*corev1.Container {
+	containers := make([]*corev1.Container, 0, len(pod.Spec.InitContainers)+len(pod.Spec.Containers))
+	for i := range pod.Spec.InitContainers {
+		if IsSidecarContainer(pod.Spec.InitContainers[i]) {
+			containers = append(containers, &pod.Spec.InitContainers[i])
+		}
+	}
+	for i := range pod.Spec.Containers {
+		containers = append(containers, &pod.Spec.Containers[i])
+	}
+	return containers
+}
+
+// GetSidecarSetContainersWithNativeSidecars returns the native sidecar initContainers and the containers of sidecarSet,
+// which are both upgraded in-place and hot-upgraded.
+func GetSidecarSetContainersWithNativeSidecars(sidecarSet *appsv1beta1.SidecarSet) []appsv1beta1.SidecarContainer {
+	containers := make([]appsv1beta1.SidecarContainer, 0, len(sidecarSet.Spec.InitContainers)+len(sidecarSet.Spec.Containers))
+	for i := range sidecarSet.Spec.InitContainers {
+		if IsSidecarContainer(sidecarSet.Spec.InitContainers[i].Container) {
+			containers = append(containers, sidecarSet.Spec.InitContainers[i])
+		}
+	}
+	return append(containers, sidecarSet.Spec.Containers...)
+}
+
+// GetPodContainerStatusesWithNativeSidecars returns the statuses of the containers and the native sidecar initContainers of pod.
+func GetPodContainerStatusesWithNativeSidecars(pod *corev1.Pod) []corev1.ContainerStatus {
+	nativeSidecars := sets.NewString()
+	for i := range pod.Spec.InitContainers {
+		if IsSidecarContainer(pod.Spec.InitContainers[i]) {
+			nativeSidecars.Insert(pod.Spec.InitContainers[i].Name)
+		}
+	}
+	statuses := make([]corev1.ContainerStatus, 0, nativeSidecars.Len()+len(pod.Status.ContainerStatuses))
+	for i := range pod.Status.InitContainerStatuses {
+		if nativeSidecars.Has(pod.Status.InitContainerStatuses[i].Name) {
+			statuses = append(statuses, pod.Status.InitContainerStatuses[i])
+		}
+	}
+	return append(statuses, pod.Status.ContainerStatuses...)
+}
+
 // listSidecarNameInSidecarSet list always init containers and sidecar containers
 func listSidecarNameInSidecarSet(sidecarSet *appsv1beta1.SidecarSet) sets.String {
 	sidecarList := sets.NewString()
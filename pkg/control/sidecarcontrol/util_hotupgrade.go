@@ -103,8 +103,8 @@ func GetPodHotUpgradeContainers(sidecarName string, pod *corev1.Pod) (workContai
 // para1: nameToUpgrade, para2: otherContainer
 func findContainerToHotUpgrade(sidecarContainer *appsv1beta1.SidecarContainer, pod *corev1.Pod, control SidecarControl) (string, string) {
 	containerInPods := make(map[string]corev1.Container)
-	for _, containerInPod := range pod.Spec.Containers {
-		containerInPods[containerInPod.Name] = containerInPod
+	for _, containerInPod := range GetPodContainersWithNativeSidecars(pod) {
+		containerInPods[containerInPod.Name] = *containerInPod
 	}
 	name1, name2 := GetHotUpgradeContainerName(sidecarContainer.Name)
 	c1, c2 := containerInPods[name1], containerInPods[name2]
@@ -118,8 +118,9 @@ func findContainerToHotUpgrade(sidecarContainer *appsv1beta1.SidecarContainer, p
 	}
 
 	// Second, Not ready sidecar container will be upgraded
-	c1Ready := podutil.GetExistingContainerStatus(pod.Status.ContainerStatuses, c1.Name).Ready && control.IsPodStateConsistent(pod, sets.NewString(c1.Name))
-	c2Ready := podutil.GetExistingContainerStatus(pod.Status.ContainerStatuses, c2.Name).Ready && control.IsPodStateConsistent(pod, sets.NewString(c2.Name))
+	containerStatuses := GetPodContainerStatusesWithNativeSidecars(pod)
+	c1Ready := podutil.GetExistingContainerStatus(containerStatuses, c1.Name).Ready && control.IsPodStateConsistent(pod, sets.NewString(c1.Name))
+	c2Ready := podutil.GetExistingContainerStatus(containerStatuses, c2.Name).Ready && control.IsPodStateConsistent(pod, sets.NewString(c2.Name))
 	klog.V(3).InfoS("Pod container ready", "pod", klog.KObj(pod), "container1Name", c1.Name, "container1Ready",
 		c1Ready, "container2Name", c2.Name, "container2Ready", c2Ready)
 	if c1Ready && !c2Ready {
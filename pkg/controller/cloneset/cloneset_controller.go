@@ -47,7 +47,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	policyv1alpha1 "github.com/openkruise/kruise/apis/policy/v1alpha1"
 	kruiseclient "github.com/openkruise/kruise/pkg/client"
+	"github.com/openkruise/kruise/pkg/control/pubcontrol"
 	clonesetcore "github.com/openkruise/kruise/pkg/controller/cloneset/core"
 	revisioncontrol "github.com/openkruise/kruise/pkg/controller/cloneset/revision"
 	synccontrol "github.com/openkruise/kruise/pkg/controller/cloneset/sync"
@@ -61,6 +63,8 @@ import (
 	"github.com/openkruise/kruise/pkg/util/fieldindex"
 	historyutil "github.com/openkruise/kruise/pkg/util/history"
 	imagejobutilfunc "github.com/openkruise/kruise/pkg/util/imagejob/utilfunction"
+	"github.com/openkruise/kruise/pkg/util/podadapter"
+	utilpodreadiness "github.com/openkruise/kruise/pkg/util/podreadiness"
 	"github.com/openkruise/kruise/pkg/util/ratelimiter"
 	"github.com/openkruise/kruise/pkg/util/refmanager"
 	"github.com/openkruise/kruise/pkg/util/volumeclaimtemplate"
@@ -120,6 +124,8 @@ func newReconciler(mgr manager.Manager) reconcile.Reconciler {
 		statusUpdater:     newStatusUpdater(cli),
 		controllerHistory: historyutil.NewHistory(cli),
 		revisionControl:   revisioncontrol.NewRevisionControl(),
+
+		podReadinessControl: utilpodreadiness.NewForAdapter(&podadapter.AdapterRuntimeClient{Client: cli}),
 	}
 	reconciler.syncControl = synccontrol.New(cli, reconciler.recorder)
 	reconciler.reconcileFunc = reconciler.doReconcile
@@ -165,6 +171,15 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// Watch for changes to PodUnavailableBudget, to continue the updates throttled by it
+	if utilfeature.DefaultFeatureGate.Enabled(features.PodUnavailableBudgetUpdateGate) {
+		err = c.Watch(source.Kind(mgr.GetCache(), &policyv1alpha1.PodUnavailableBudget{},
+			&pubcontrol.EnqueueWorkloadsForPub{Reader: mgr.GetCache(), GroupKind: clonesetutils.ControllerKind.GroupKind()}))
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -181,6 +196,8 @@ type ReconcileCloneSet struct {
 	statusUpdater     StatusUpdater
 	revisionControl   revisioncontrol.Interface
 	syncControl       synccontrol.Interface
+
+	podReadinessControl utilpodreadiness.Interface
 }
 
 // +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;create;update;patch;delete
@@ -188,10 +205,12 @@ type ReconcileCloneSet struct {
 // +kubebuilder:rbac:groups=core,resources=pods/resize,verbs=get;patch;update
 // +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;list;watch;create;delete
 // +kubebuilder:rbac:groups=apps,resources=controllerrevisions,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps.kruise.io,resources=clonesets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps.kruise.io,resources=clonesets/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=apps.kruise.io,resources=clonesets/finalizers,verbs=update
+// +kubebuilder:rbac:groups=policy.kruise.io,resources=podunavailablebudgets,verbs=get;list;watch
 
 // Reconcile reads that state of the cluster for a CloneSet object and makes changes based on the state read
 // and what is in the CloneSet.Spec
@@ -302,6 +321,9 @@ func (r *ReconcileCloneSet) doReconcile(request reconcile.Request) (res reconcil
 		return reconcile.Result{}, err
 	}
 
+	// separate the standby pods from the active ones
+	activePods, standbyPods, activePVCs, standbyPVCs := splitStandbyPods(filteredPods, filteredPVCs)
+
 	// list all revisions and sort them
 	revisions, err := r.controllerHistory.ListControllerRevisions(instance, selector)
 	if err != nil {
@@ -344,8 +366,13 @@ func (r *ReconcileCloneSet) doReconcile(request reconcile.Request) (res reconcil
 		CollisionCount:     new(int32),
 		LabelSelector:      selector.String(),
 		Conditions:         instance.Status.Conditions,
+		StandbyReplicas:    int32(len(standbyPods)),
 	}
 	*newStatus.CollisionCount = collisionCount
+	newStatus.UpdateRevisionDiff = getUpdateRevisionDiff(instance, currentRevision, updateRevision)
+	if duration := synccontrol.SyncUpdateSteps(instance, &newStatus, activePods, r.recorder); duration > 0 {
+		clonesetutils.DurationStore.Push(request.String(), duration)
+	}
 	if !isPreDownloadDisabled {
 		if currentRevision.Name != updateRevision.Name {
 			// get clone pre-download annotation
@@ -375,10 +402,29 @@ func (r *ReconcileCloneSet) doReconcile(request reconcile.Request) (res reconcil
 		}
 	}
 
+	// roll back the template if the update revision failed to progress
+	if rolledBack, err := r.rollbackOnFailure(instance, &newStatus, currentRevision, updateRevision); err != nil {
+		return reconcile.Result{}, err
+	} else if rolledBack {
+		// pods will be synced to the rolled back revision in the next reconcile
+		return reconcile.Result{}, r.statusUpdater.UpdateCloneSetStatus(instance, &newStatus, activePods)
+	}
+
+	// promote standby pods instead of creating new ones when scaling out
+	if promoted, err := r.promoteStandbyPods(instance, updateRevision.Name, activePods, standbyPods); err != nil {
+		return reconcile.Result{}, err
+	} else if promoted {
+		// pods will be synced with the promoted ones in the next reconcile
+		return reconcile.Result{}, nil
+	}
+
 	// scale and update pods
-	syncErr := r.syncCloneSet(instance, &newStatus, currentRevision, updateRevision, revisions, filteredPods, filteredPVCs)
+	syncErr := r.syncCloneSet(instance, &newStatus, currentRevision, updateRevision, revisions, activePods, activePVCs)
+	if syncErr == nil {
+		syncErr = r.syncStandbyPods(instance, currentRevision, updateRevision, revisions, standbyPods, standbyPVCs)
+	}
 	// update new status
-	if err = r.statusUpdater.UpdateCloneSetStatus(instance, &newStatus, filteredPods); err != nil {
+	if err = r.statusUpdater.UpdateCloneSetStatus(instance, &newStatus, activePods); err != nil {
 		return reconcile.Result{}, err
 	}
 
@@ -414,6 +460,12 @@ func (r *ReconcileCloneSet) syncCloneSet(
 	if err != nil {
 		return err
 	}
+	// the partition of the current update step takes the place of updateStrategy.partition
+	if len(instance.Spec.UpdateStrategy.Steps) > 0 {
+		partition := clonesetutils.GetUpdatePartition(instance, newStatus)
+		currentSet.Spec.UpdateStrategy.Partition = partition
+		updateSet.Spec.UpdateStrategy.Partition = partition
+	}
 
 	var scaling bool
 	var podsScaleErr error
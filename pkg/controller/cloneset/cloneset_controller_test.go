@@ -61,6 +61,8 @@ import (
 	utilfeature "github.com/openkruise/kruise/pkg/util/feature"
 	"github.com/openkruise/kruise/pkg/util/fieldindex"
 	historyutil "github.com/openkruise/kruise/pkg/util/history"
+	"github.com/openkruise/kruise/pkg/util/podadapter"
+	utilpodreadiness "github.com/openkruise/kruise/pkg/util/podreadiness"
 	"github.com/openkruise/kruise/pkg/util/volumeclaimtemplate"
 )
 
@@ -1301,6 +1303,8 @@ func newMockCloneSetReconciler() *ReconcileCloneSet {
 		statusUpdater:     newStatusUpdater(fakeClient),
 		controllerHistory: historyutil.NewHistory(fakeClient),
 		revisionControl:   revisioncontrol.NewRevisionControl(),
+
+		podReadinessControl: utilpodreadiness.NewForAdapter(&podadapter.AdapterRuntimeClient{Client: fakeClient}),
 	}
 	reconciler.syncControl = synccontrol.New(fakeClient, reconciler.recorder)
 	reconciler.reconcileFunc = reconciler.doReconcile
@@ -60,8 +60,8 @@ func (r *ReconcileCloneSet) createImagePullJobsForInPlaceUpdate(cs *appsv1alpha1
 
 	// ignore if all Pods update in one batch
 	var partition, maxUnavailable int
-	if cs.Spec.UpdateStrategy.Partition != nil {
-		pValue, err := util.CalculatePartitionReplicas(cs.Spec.UpdateStrategy.Partition, cs.Spec.Replicas)
+	if p := clonesetutils.GetUpdatePartition(cs, &cs.Status); p != nil {
+		pValue, err := util.CalculatePartitionReplicas(p, cs.Spec.Replicas)
 		if err != nil {
 			klog.ErrorS(err, "CloneSet partition value was illegal", "cloneSet", klog.KObj(cs))
 			return err
@@ -0,0 +1,74 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloneset
+
+import (
+	"context"
+	"fmt"
+
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	clonesetutils "github.com/openkruise/kruise/pkg/controller/cloneset/utils"
+)
+
+// rollbackOnFailure switches the template of CloneSet back to the current revision, if rollbackOnFailure is enabled
+// and the update revision has exceeded the progress deadline, even if no pod has been updated yet.
+// It returns true if the template has been rolled back.
+func (r *ReconcileCloneSet) rollbackOnFailure(cs *appsv1alpha1.CloneSet, newStatus *appsv1alpha1.CloneSetStatus,
+	currentRevision, updateRevision *apps.ControllerRevision) (bool, error) {
+	if !shouldRollbackOnFailure(cs, currentRevision.Name, updateRevision.Name) {
+		return false, nil
+	}
+
+	rollbackSet, err := r.revisionControl.ApplyRevision(cs, currentRevision)
+	if err != nil {
+		return false, err
+	}
+	newCS := cs.DeepCopy()
+	newCS.Spec.Template = rollbackSet.Spec.Template
+	if err = r.Update(context.TODO(), newCS); err != nil {
+		r.recorder.Eventf(cs, v1.EventTypeWarning, "FailedRollback",
+			"failed to roll back template from revision %s to %s: %v", updateRevision.Name, currentRevision.Name, err)
+		return false, err
+	}
+
+	msg := fmt.Sprintf("Rolled back template from revision %s to %s, for the update exceeded progressDeadlineSeconds with %d/%d updated pods ready",
+		updateRevision.Name, currentRevision.Name, cs.Status.UpdatedReadyReplicas, cs.Status.UpdatedReplicas)
+	klog.InfoS("CloneSet rolled back on failure", "cloneSet", klog.KObj(cs), "updateRevision", updateRevision.Name, "currentRevision", currentRevision.Name)
+	r.recorder.Event(cs, v1.EventTypeWarning, "RollbackOnFailure", msg)
+
+	condition := clonesetutils.NewCloneSetCondition(appsv1alpha1.CloneSetConditionTypeRolledBack,
+		v1.ConditionTrue, appsv1alpha1.CloneSetProgressDeadlineExceeded, msg, timer.Now())
+	clonesetutils.RemoveCloneSetCondition(newStatus, appsv1alpha1.CloneSetConditionTypeRolledBack)
+	clonesetutils.SetCloneSetCondition(newStatus, *condition)
+	return true, nil
+}
+
+func shouldRollbackOnFailure(cs *appsv1alpha1.CloneSet, currentRevision, updateRevision string) bool {
+	if !cs.Spec.RollbackOnFailure || !clonesetutils.HasProgressDeadline(cs) || currentRevision == updateRevision {
+		return false
+	}
+	// the status should have been calculated for this update revision
+	if cs.Status.UpdateRevision != updateRevision {
+		return false
+	}
+	condition := clonesetutils.GetCloneSetCondition(cs.Status, appsv1alpha1.CloneSetConditionTypeProgressing)
+	return condition != nil && condition.Reason == string(appsv1alpha1.CloneSetProgressDeadlineExceeded)
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloneset
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	clonesetutils "github.com/openkruise/kruise/pkg/controller/cloneset/utils"
+)
+
+func TestShouldRollbackOnFailure(t *testing.T) {
+	exceeded := appsv1alpha1.CloneSetCondition{
+		Type:   appsv1alpha1.CloneSetConditionTypeProgressing,
+		Status: v1.ConditionFalse,
+		Reason: string(appsv1alpha1.CloneSetProgressDeadlineExceeded),
+	}
+	progressing := appsv1alpha1.CloneSetCondition{
+		Type:   appsv1alpha1.CloneSetConditionTypeProgressing,
+		Status: v1.ConditionTrue,
+		Reason: string(appsv1alpha1.CloneSetProgressUpdated),
+	}
+	newCloneSet := func(rollback bool, pds *int32, updateRevision string, updated, updatedReady int32, conds ...appsv1alpha1.CloneSetCondition) *appsv1alpha1.CloneSet {
+		return &appsv1alpha1.CloneSet{
+			Spec: appsv1alpha1.CloneSetSpec{RollbackOnFailure: rollback, ProgressDeadlineSeconds: pds},
+			Status: appsv1alpha1.CloneSetStatus{
+				UpdateRevision:       updateRevision,
+				UpdatedReplicas:      updated,
+				UpdatedReadyReplicas: updatedReady,
+				Conditions:           conds,
+			},
+		}
+	}
+
+	cases := []struct {
+		name            string
+		cs              *appsv1alpha1.CloneSet
+		currentRevision string
+		expected        bool
+	}{
+		{
+			name:            "rollbackOnFailure disabled",
+			cs:              newCloneSet(false, ptr.To(int32(10)), "2", 3, 1, exceeded),
+			currentRevision: "1",
+		},
+		{
+			name:            "no progress deadline",
+			cs:              newCloneSet(true, nil, "2", 3, 1, exceeded),
+			currentRevision: "1",
+		},
+		{
+			name:            "no revision to roll back",
+			cs:              newCloneSet(true, ptr.To(int32(10)), "2", 3, 1, exceeded),
+			currentRevision: "2",
+		},
+		{
+			name:            "status not calculated for the update revision",
+			cs:              newCloneSet(true, ptr.To(int32(10)), "3", 3, 1, exceeded),
+			currentRevision: "1",
+		},
+		{
+			name:            "still progressing",
+			cs:              newCloneSet(true, ptr.To(int32(10)), "2", 3, 1, progressing),
+			currentRevision: "1",
+		},
+		{
+			name:            "progress deadline exceeded",
+			cs:              newCloneSet(true, ptr.To(int32(10)), "2", 3, 1, exceeded),
+			currentRevision: "1",
+			expected:        true,
+		},
+		{
+			name:            "progress deadline exceeded before any pod updated",
+			cs:              newCloneSet(true, ptr.To(int32(10)), "2", 0, 0, exceeded),
+			currentRevision: "1",
+			expected:        true,
+		},
+		{
+			name:            "progress deadline exceeded with updated pods all ready",
+			cs:              newCloneSet(true, ptr.To(int32(10)), "2", 3, 3, exceeded),
+			currentRevision: "1",
+			expected:        true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := shouldRollbackOnFailure(tc.cs, tc.currentRevision, "2"); got != tc.expected {
+				t.Fatalf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestRollbackOnFailure(t *testing.T) {
+	r := newMockCloneSetReconciler()
+	fakeClient := fake.NewClientBuilder().WithScheme(testscheme).WithStatusSubresource(&appsv1alpha1.CloneSet{}).Build()
+	r.Client = fakeClient
+	r.statusUpdater = newStatusUpdater(fakeClient)
+
+	cs := &appsv1alpha1.CloneSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"},
+		Spec: appsv1alpha1.CloneSetSpec{
+			Replicas:                ptr.To(int32(2)),
+			RollbackOnFailure:       true,
+			ProgressDeadlineSeconds: ptr.To(int32(10)),
+			Selector:                &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}},
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "foo"}},
+				Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "main", Image: "nginx:v1"}}},
+			},
+		},
+	}
+	currentRevision, err := r.revisionControl.NewRevision(cs, 1, nil)
+	if err != nil {
+		t.Fatalf("failed to new revision: %v", err)
+	}
+
+	cs.Spec.Template.Spec.Containers[0].Image = "nginx:v2"
+	updateRevision, err := r.revisionControl.NewRevision(cs, 2, nil)
+	if err != nil {
+		t.Fatalf("failed to new revision: %v", err)
+	}
+	lastUpdateTime := metav1.NewTime(time.Now().Add(-time.Minute))
+	cs.Status = appsv1alpha1.CloneSetStatus{
+		CurrentRevision:         currentRevision.Name,
+		UpdateRevision:          updateRevision.Name,
+		Replicas:                2,
+		UpdatedReplicas:         1,
+		UpdatedReadyReplicas:    0,
+		ExpectedUpdatedReplicas: 2,
+		Conditions: []appsv1alpha1.CloneSetCondition{{
+			Type:               appsv1alpha1.CloneSetConditionTypeProgressing,
+			Status:             v1.ConditionFalse,
+			Reason:             string(appsv1alpha1.CloneSetProgressDeadlineExceeded),
+			LastUpdateTime:     lastUpdateTime,
+			LastTransitionTime: lastUpdateTime,
+		}},
+	}
+	if err = r.Create(context.TODO(), cs); err != nil {
+		t.Fatalf("failed to create cloneset: %v", err)
+	}
+	pods := []*v1.Pod{{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "foo-0",
+			Labels:    map[string]string{"app": "foo", apps.ControllerRevisionHashLabelKey: currentRevision.Name},
+		},
+	}, {
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "foo-1",
+			Labels:    map[string]string{"app": "foo", apps.ControllerRevisionHashLabelKey: updateRevision.Name},
+		},
+	}}
+
+	// the same new status as the controller prepares before rolling back
+	newStatus := &appsv1alpha1.CloneSetStatus{
+		CurrentRevision: currentRevision.Name,
+		UpdateRevision:  updateRevision.Name,
+		Conditions:      cs.Status.Conditions,
+	}
+	rolledBack, err := r.rollbackOnFailure(cs, newStatus, currentRevision, updateRevision)
+	if err != nil || !rolledBack {
+		t.Fatalf("expected rolled back, got %v, %v", rolledBack, err)
+	}
+	if err = r.statusUpdater.UpdateCloneSetStatus(cs, newStatus, pods); err != nil {
+		t.Fatalf("failed to update status: %v", err)
+	}
+
+	got := &appsv1alpha1.CloneSet{}
+	if err = r.Get(context.TODO(), client.ObjectKeyFromObject(cs), got); err != nil {
+		t.Fatalf("failed to get cloneset: %v", err)
+	}
+	if image := got.Spec.Template.Spec.Containers[0].Image; image != "nginx:v1" {
+		t.Fatalf("expected template rolled back to nginx:v1, got %s", image)
+	}
+	if cond := clonesetutils.GetCloneSetCondition(got.Status, appsv1alpha1.CloneSetConditionTypeRolledBack); cond == nil || cond.Status != v1.ConditionTrue {
+		t.Fatalf("expected RolledBack condition persisted, got %v", cond)
+	}
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloneset
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appspub "github.com/openkruise/kruise/apis/apps/pub"
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	clonesetutils "github.com/openkruise/kruise/pkg/controller/cloneset/utils"
+	"github.com/openkruise/kruise/pkg/util"
+	utilpodreadiness "github.com/openkruise/kruise/pkg/util/podreadiness"
+)
+
+func isStandbyPod(pod *v1.Pod) bool {
+	return pod.Labels[appsv1alpha1.CloneSetStandbyPodLabelKey] == "true"
+}
+
+// splitStandbyPods separates the standby pods and their pvcs from the active ones.
+// The pvcs that do not belong to any standby pod are regarded as active, so they can be reused by active pods.
+func splitStandbyPods(pods []*v1.Pod, pvcs []*v1.PersistentVolumeClaim) (
+	activePods, standbyPods []*v1.Pod, activePVCs, standbyPVCs []*v1.PersistentVolumeClaim) {
+	standbyIDs := sets.NewString()
+	for _, pod := range pods {
+		if isStandbyPod(pod) {
+			standbyPods = append(standbyPods, pod)
+			if id := pod.Labels[appsv1alpha1.CloneSetInstanceID]; id != "" {
+				standbyIDs.Insert(id)
+			}
+		} else {
+			activePods = append(activePods, pod)
+		}
+	}
+	for _, pvc := range pvcs {
+		if standbyIDs.Has(pvc.Labels[appsv1alpha1.CloneSetInstanceID]) {
+			standbyPVCs = append(standbyPVCs, pvc)
+		} else {
+			activePVCs = append(activePVCs, pvc)
+		}
+	}
+	return
+}
+
+// promoteStandbyPods promotes standby pods to active, if the active pods are fewer than replicas.
+// It returns true if any pod has been modified.
+func (r *ReconcileCloneSet) promoteStandbyPods(cs *appsv1alpha1.CloneSet, updateRevision string, activePods, standbyPods []*v1.Pod) (bool, error) {
+	if cs.DeletionTimestamp != nil {
+		return false, nil
+	}
+
+	var modified bool
+	// the standby key may be left if the pod has been promoted but failed to remove the key
+	for _, pod := range activePods {
+		if utilpodreadiness.HasNotReadyKey(pod, clonesetutils.StandbyNotReadyMessage) {
+			if err := r.podReadinessControl.RemoveNotReadyKey(pod, clonesetutils.StandbyNotReadyMessage); err != nil {
+				return modified, fmt.Errorf("failed to remove standby key from pod %s: %v", pod.Name, err)
+			}
+			modified = true
+		}
+	}
+
+	diff := int(*cs.Spec.Replicas) - len(activePods)
+	if diff <= 0 || len(standbyPods) == 0 {
+		return modified, nil
+	}
+
+	candidates := make([]*v1.Pod, len(standbyPods))
+	copy(candidates, standbyPods)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return isBetterStandbyPod(candidates[i], candidates[j], updateRevision)
+	})
+	if diff < len(candidates) {
+		candidates = candidates[:diff]
+	}
+
+	patch := fmt.Sprintf(`{"metadata":{"labels":{"%s":null}}}`, appsv1alpha1.CloneSetStandbyPodLabelKey)
+	for _, pod := range candidates {
+		clone := pod.DeepCopy()
+		if err := r.Patch(context.TODO(), clone, client.RawPatch(types.MergePatchType, []byte(patch))); err != nil {
+			return modified, fmt.Errorf("failed to promote standby pod %s: %v", pod.Name, err)
+		}
+		modified = true
+		clonesetutils.ResourceVersionExpectations.Expect(clone)
+		if err := r.podReadinessControl.RemoveNotReadyKey(clone, clonesetutils.StandbyNotReadyMessage); err != nil {
+			return modified, fmt.Errorf("failed to remove standby key from pod %s: %v", pod.Name, err)
+		}
+		klog.InfoS("CloneSet promoted standby pod", "cloneSet", klog.KObj(cs), "pod", klog.KObj(pod))
+		r.recorder.Eventf(cs, v1.EventTypeNormal, "PromoteStandby", "promote standby pod %s", pod.Name)
+	}
+	return modified, nil
+}
+
+// isBetterStandbyPod prefers the pods in update revision, then the pods with containers ready, then the older ones.
+func isBetterStandbyPod(a, b *v1.Pod, updateRevision string) bool {
+	aUpdated := clonesetutils.EqualToRevisionHash("", a, updateRevision)
+	bUpdated := clonesetutils.EqualToRevisionHash("", b, updateRevision)
+	if aUpdated != bUpdated {
+		return aUpdated
+	}
+	aReady := podutil.IsContainersReadyConditionTrue(a.Status)
+	bReady := podutil.IsContainersReadyConditionTrue(b.Status)
+	if aReady != bReady {
+		return aReady
+	}
+	return a.CreationTimestamp.Before(&b.CreationTimestamp)
+}
+
+// syncStandbyPods scales and updates the standby pods to match standbyReplicas.
+func (r *ReconcileCloneSet) syncStandbyPods(
+	instance *appsv1alpha1.CloneSet,
+	currentRevision, updateRevision *apps.ControllerRevision, revisions []*apps.ControllerRevision,
+	standbyPods []*v1.Pod, standbyPVCs []*v1.PersistentVolumeClaim,
+) error {
+	if instance.DeletionTimestamp != nil || (instance.Spec.StandbyReplicas == nil && len(standbyPods) == 0) {
+		return nil
+	}
+
+	for _, pod := range standbyPods {
+		if !utilpodreadiness.HasNotReadyKey(pod, clonesetutils.StandbyNotReadyMessage) {
+			if err := r.podReadinessControl.AddNotReadyKey(pod, clonesetutils.StandbyNotReadyMessage); err != nil {
+				return fmt.Errorf("failed to add standby key to pod %s: %v", pod.Name, err)
+			}
+		}
+	}
+
+	currentSet, err := r.revisionControl.ApplyRevision(instance, currentRevision)
+	if err != nil {
+		return err
+	}
+	updateSet, err := r.revisionControl.ApplyRevision(instance, updateRevision)
+	if err != nil {
+		return err
+	}
+	setStandbySpec(currentSet)
+	setStandbySpec(updateSet)
+
+	scaling, err := r.syncControl.Scale(currentSet, updateSet, currentRevision.Name, updateRevision.Name, standbyPods, standbyPVCs)
+	if err != nil || scaling {
+		return err
+	}
+	return r.syncControl.Update(updateSet, currentRevision, updateRevision, revisions, standbyPods, standbyPVCs)
+}
+
+// setStandbySpec converts the spec of CloneSet to manage the standby pods.
+// The standby pods are not serving, so they are all updated to the update revision without limitation or update windows.
+func setStandbySpec(cs *appsv1alpha1.CloneSet) {
+	cs.Spec.Replicas = ptr.To(ptr.Deref(cs.Spec.StandbyReplicas, 0))
+	if cs.Spec.Template.Labels == nil {
+		cs.Spec.Template.Labels = map[string]string{}
+	}
+	cs.Spec.Template.Labels[appsv1alpha1.CloneSetStandbyPodLabelKey] = "true"
+	// the readiness gate keeps the new standby pods not ready until the KruisePodReady condition
+	// is initialized with the standby key on creation
+	pod := &v1.Pod{Spec: cs.Spec.Template.Spec}
+	util.InjectReadinessGateToPod(pod, appspub.KruisePodReadyConditionType)
+	cs.Spec.Template.Spec.ReadinessGates = pod.Spec.ReadinessGates
+	cs.Spec.ScaleStrategy.MaxUnavailable = nil
+	cs.Spec.UpdateStrategy.Partition = nil
+	cs.Spec.UpdateStrategy.MaxSurge = nil
+	cs.Spec.UpdateStrategy.InPlaceUpdateSurge = false
+	maxUnavailable := intstr.FromString("100%")
+	cs.Spec.UpdateStrategy.MaxUnavailable = &maxUnavailable
+	cs.Spec.UpdateStrategy.Steps = nil
+	cs.Spec.UpdateStrategy.UpdateWindows = nil
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloneset
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appspub "github.com/openkruise/kruise/apis/apps/pub"
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	clonesetutils "github.com/openkruise/kruise/pkg/controller/cloneset/utils"
+	utilpodreadiness "github.com/openkruise/kruise/pkg/util/podreadiness"
+)
+
+func newStandbyTestPod(name, revision string, standby, containersReady bool, age time.Duration) *v1.Pod {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "default",
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+			Labels: map[string]string{
+				"app":                               "foo",
+				apps.ControllerRevisionHashLabelKey: revision,
+				appsv1alpha1.CloneSetInstanceID:     name,
+			},
+		},
+		Spec: v1.PodSpec{
+			ReadinessGates: []v1.PodReadinessGate{{ConditionType: appspub.KruisePodReadyConditionType}},
+			Containers:     []v1.Container{{Name: "main", Image: "nginx"}},
+		},
+		Status: v1.PodStatus{Phase: v1.PodRunning},
+	}
+	if standby {
+		pod.Labels[appsv1alpha1.CloneSetStandbyPodLabelKey] = "true"
+		pod.Status.Conditions = append(pod.Status.Conditions, v1.PodCondition{
+			Type:    appspub.KruisePodReadyConditionType,
+			Status:  v1.ConditionFalse,
+			Message: `[{"userAgent":"CloneSet","key":"Standby"}]`,
+		})
+	}
+	if containersReady {
+		pod.Status.Conditions = append(pod.Status.Conditions, v1.PodCondition{Type: v1.ContainersReady, Status: v1.ConditionTrue})
+	}
+	return pod
+}
+
+func TestSplitStandbyPods(t *testing.T) {
+	pods := []*v1.Pod{
+		newStandbyTestPod("pod-0", "rev", false, true, 0),
+		newStandbyTestPod("pod-1", "rev", true, true, 0),
+	}
+	pvcs := []*v1.PersistentVolumeClaim{
+		{ObjectMeta: metav1.ObjectMeta{Name: "data-pod-0", Labels: map[string]string{appsv1alpha1.CloneSetInstanceID: "pod-0"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "data-pod-1", Labels: map[string]string{appsv1alpha1.CloneSetInstanceID: "pod-1"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "data-pod-2", Labels: map[string]string{appsv1alpha1.CloneSetInstanceID: "pod-2"}}},
+	}
+
+	activePods, standbyPods, activePVCs, standbyPVCs := splitStandbyPods(pods, pvcs)
+	if len(activePods) != 1 || activePods[0].Name != "pod-0" {
+		t.Fatalf("unexpected active pods %v", activePods)
+	}
+	if len(standbyPods) != 1 || standbyPods[0].Name != "pod-1" {
+		t.Fatalf("unexpected standby pods %v", standbyPods)
+	}
+	if len(activePVCs) != 2 || activePVCs[0].Name != "data-pod-0" || activePVCs[1].Name != "data-pod-2" {
+		t.Fatalf("unexpected active pvcs %v", activePVCs)
+	}
+	if len(standbyPVCs) != 1 || standbyPVCs[0].Name != "data-pod-1" {
+		t.Fatalf("unexpected standby pvcs %v", standbyPVCs)
+	}
+}
+
+func TestPromoteStandbyPods(t *testing.T) {
+	cs := &appsv1alpha1.CloneSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"},
+		Spec:       appsv1alpha1.CloneSetSpec{Replicas: ptr.To(int32(2)), StandbyReplicas: ptr.To(int32(3))},
+	}
+	activePods := []*v1.Pod{newStandbyTestPod("active-0", "new", false, true, time.Hour)}
+	standbyPods := []*v1.Pod{
+		newStandbyTestPod("standby-old-revision", "old", true, true, time.Hour),
+		newStandbyTestPod("standby-not-ready", "new", true, false, time.Hour),
+		newStandbyTestPod("standby-ready", "new", true, true, time.Minute),
+	}
+
+	r := newMockCloneSetReconciler()
+	for _, pod := range append(activePods, standbyPods...) {
+		if err := r.Create(context.TODO(), pod.DeepCopy()); err != nil {
+			t.Fatalf("failed to create pod: %v", err)
+		}
+	}
+
+	promoted, err := r.promoteStandbyPods(cs, "new", activePods, standbyPods)
+	if err != nil || !promoted {
+		t.Fatalf("expected promoted, got %v, %v", promoted, err)
+	}
+
+	for _, pod := range standbyPods {
+		got := &v1.Pod{}
+		if err := r.Get(context.TODO(), client.ObjectKeyFromObject(pod), got); err != nil {
+			t.Fatalf("failed to get pod: %v", err)
+		}
+		expectPromoted := pod.Name == "standby-ready"
+		if isStandbyPod(got) == expectPromoted {
+			t.Fatalf("expected pod %s promoted %v, got labels %v", pod.Name, expectPromoted, got.Labels)
+		}
+		if utilpodreadiness.HasNotReadyKey(got, clonesetutils.StandbyNotReadyMessage) == expectPromoted {
+			t.Fatalf("expected pod %s promoted %v, got condition %v", pod.Name, expectPromoted, utilpodreadiness.GetReadinessCondition(got))
+		}
+	}
+
+	// no more pods to promote when the active pods are enough
+	promotedPod := &v1.Pod{}
+	if err := r.Get(context.TODO(), client.ObjectKeyFromObject(standbyPods[2]), promotedPod); err != nil {
+		t.Fatalf("failed to get pod: %v", err)
+	}
+	activePods = append(activePods, promotedPod)
+	promoted, err = r.promoteStandbyPods(cs, "new", activePods, standbyPods[:2])
+	if err != nil || promoted {
+		t.Fatalf("expected not promoted, got %v, %v", promoted, err)
+	}
+}
+
+func TestSetStandbySpec(t *testing.T) {
+	cs := &appsv1alpha1.CloneSet{
+		Spec: appsv1alpha1.CloneSetSpec{
+			Replicas:        ptr.To(int32(5)),
+			StandbyReplicas: ptr.To(int32(2)),
+			UpdateStrategy: appsv1alpha1.CloneSetUpdateStrategy{
+				Partition: ptr.To(intstr.FromInt32(3)),
+				MaxSurge:  ptr.To(intstr.FromInt32(1)),
+			},
+		},
+	}
+	setStandbySpec(cs)
+	if *cs.Spec.Replicas != 2 {
+		t.Fatalf("expected replicas 2, got %v", *cs.Spec.Replicas)
+	}
+	if cs.Spec.Template.Labels[appsv1alpha1.CloneSetStandbyPodLabelKey] != "true" {
+		t.Fatalf("expected standby label in template, got %v", cs.Spec.Template.Labels)
+	}
+	if gates := cs.Spec.Template.Spec.ReadinessGates; len(gates) != 1 || gates[0].ConditionType != appspub.KruisePodReadyConditionType {
+		t.Fatalf("expected KruisePodReady readiness gate in template, got %v", gates)
+	}
+	if cs.Spec.UpdateStrategy.Partition != nil || cs.Spec.UpdateStrategy.MaxSurge != nil || cs.Spec.UpdateStrategy.MaxUnavailable.String() != "100%" {
+		t.Fatalf("unexpected update strategy %+v", cs.Spec.UpdateStrategy)
+	}
+}
@@ -19,8 +19,10 @@ package cloneset
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"time"
 
+	apps "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/retry"
@@ -30,6 +32,7 @@ import (
 
 	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
 	clonesetcore "github.com/openkruise/kruise/pkg/controller/cloneset/core"
+	revisioncontrol "github.com/openkruise/kruise/pkg/controller/cloneset/revision"
 	"github.com/openkruise/kruise/pkg/controller/cloneset/sync"
 	clonesetutils "github.com/openkruise/kruise/pkg/controller/cloneset/utils"
 	"github.com/openkruise/kruise/pkg/util"
@@ -85,11 +88,15 @@ func (r *realStatusUpdater) inconsistentStatus(cs *appsv1alpha1.CloneSet, newSta
 		newStatus.UpdatedReadyReplicas != oldStatus.UpdatedReadyReplicas ||
 		newStatus.UpdatedReplicas != oldStatus.UpdatedReplicas ||
 		newStatus.UpdatedAvailableReplicas != oldStatus.UpdatedAvailableReplicas ||
+		newStatus.StandbyReplicas != oldStatus.StandbyReplicas ||
 		newStatus.ExpectedUpdatedReplicas != oldStatus.ExpectedUpdatedReplicas ||
 		newStatus.UpdateRevision != oldStatus.UpdateRevision ||
 		newStatus.CurrentRevision != oldStatus.CurrentRevision ||
 		newStatus.LabelSelector != oldStatus.LabelSelector ||
-		hasProgressingConditionChanged(cs.Status, *newStatus)
+		!reflect.DeepEqual(newStatus.UpdateStepStatus, oldStatus.UpdateStepStatus) ||
+		!reflect.DeepEqual(newStatus.UpdateRevisionDiff, oldStatus.UpdateRevisionDiff) ||
+		hasProgressingConditionChanged(cs.Status, *newStatus) ||
+		hasConditionChanged(cs.Status, *newStatus, appsv1alpha1.CloneSetConditionTypeRolledBack)
 }
 
 func (r *realStatusUpdater) calculateStatus(cs *appsv1alpha1.CloneSet, newStatus *appsv1alpha1.CloneSetStatus, pods []*v1.Pod) {
@@ -115,9 +122,10 @@ func (r *realStatusUpdater) calculateStatus(cs *appsv1alpha1.CloneSet, newStatus
 	// Consider the update revision as stable if revisions of all pods are consistent to it and have the expected number of replicas, no need to wait all of them ready
 	if newStatus.UpdatedReplicas == newStatus.Replicas && newStatus.Replicas == *cs.Spec.Replicas {
 		newStatus.CurrentRevision = newStatus.UpdateRevision
+		newStatus.UpdateRevisionDiff = nil
 	}
 
-	if partition, err := util.CalculatePartitionReplicas(cs.Spec.UpdateStrategy.Partition, cs.Spec.Replicas); err == nil {
+	if partition, err := util.CalculatePartitionReplicas(clonesetutils.GetUpdatePartition(cs, newStatus), cs.Spec.Replicas); err == nil {
 		newStatus.ExpectedUpdatedReplicas = *cs.Spec.Replicas - int32(partition)
 	}
 
@@ -125,12 +133,39 @@ func (r *realStatusUpdater) calculateStatus(cs *appsv1alpha1.CloneSet, newStatus
 	clonesetutils.DurationStore.Push(clonesetutils.GetControllerKey(cs), duration)
 }
 
+// getUpdateRevisionDiff returns the diff between current and update revisions, which is reused from the old status
+// if neither the revisions nor the update strategy type changed.
+func getUpdateRevisionDiff(cs *appsv1alpha1.CloneSet, currentRevision, updateRevision *apps.ControllerRevision) *appsv1alpha1.CloneSetRevisionDiff {
+	if currentRevision.Name == updateRevision.Name {
+		return nil
+	}
+	if diff := cs.Status.UpdateRevisionDiff; diff != nil && diff.CurrentRevision == currentRevision.Name && diff.UpdateRevision == updateRevision.Name &&
+		diff.UpdateStrategyType == cs.Spec.UpdateStrategy.Type {
+		return diff
+	}
+	diff, err := revisioncontrol.DiffRevisions(cs, currentRevision, updateRevision)
+	if err != nil {
+		klog.ErrorS(err, "Failed to diff revisions for CloneSet", "cloneSet", klog.KObj(cs),
+			"currentRevision", currentRevision.Name, "updateRevision", updateRevision.Name)
+		return nil
+	}
+	return diff
+}
+
 func (r *realStatusUpdater) calculateProgressingStatus(cs *appsv1alpha1.CloneSet, newStatus *appsv1alpha1.CloneSetStatus) time.Duration {
 	if !clonesetutils.HasProgressDeadline(cs) {
 		clonesetutils.RemoveCloneSetCondition(newStatus, appsv1alpha1.CloneSetConditionTypeProgressing)
 		return time.Duration(-1)
 	}
 
+	// a new update revision restarts the progress deadline
+	if newStatus.UpdateRevision != cs.Status.UpdateRevision {
+		if condition := clonesetutils.GetCloneSetCondition(*newStatus, appsv1alpha1.CloneSetConditionTypeProgressing); condition != nil &&
+			condition.Reason == string(appsv1alpha1.CloneSetProgressDeadlineExceeded) {
+			clonesetutils.RemoveCloneSetCondition(newStatus, appsv1alpha1.CloneSetConditionTypeProgressing)
+		}
+	}
+
 	timeNow := time.Now()
 	switch {
 	case clonesetutils.CloneSetAvailable(cs, newStatus):
@@ -173,8 +208,12 @@ func (r *realStatusUpdater) calculateProgressingStatus(cs *appsv1alpha1.CloneSet
 }
 
 func hasProgressingConditionChanged(oldStatus appsv1alpha1.CloneSetStatus, newStatus appsv1alpha1.CloneSetStatus) bool {
-	oldCond := clonesetutils.GetCloneSetCondition(oldStatus, appsv1alpha1.CloneSetConditionTypeProgressing)
-	newCond := clonesetutils.GetCloneSetCondition(newStatus, appsv1alpha1.CloneSetConditionTypeProgressing)
+	return hasConditionChanged(oldStatus, newStatus, appsv1alpha1.CloneSetConditionTypeProgressing)
+}
+
+func hasConditionChanged(oldStatus appsv1alpha1.CloneSetStatus, newStatus appsv1alpha1.CloneSetStatus, condType appsv1alpha1.CloneSetConditionType) bool {
+	oldCond := clonesetutils.GetCloneSetCondition(oldStatus, condType)
+	newCond := clonesetutils.GetCloneSetCondition(newStatus, condType)
 
 	if oldCond == nil && newCond == nil {
 		return false
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	revisioncontrol "github.com/openkruise/kruise/pkg/controller/cloneset/revision"
+	clonesettest "github.com/openkruise/kruise/pkg/controller/cloneset/test"
 	clonesetutils "github.com/openkruise/kruise/pkg/controller/cloneset/utils"
 	"github.com/openkruise/kruise/pkg/util"
 	v1 "k8s.io/api/core/v1"
@@ -1584,3 +1586,35 @@ func TestGetRequeueSecondsFromCondition(t *testing.T) {
 		})
 	}
 }
+
+func TestGetUpdateRevisionDiff(t *testing.T) {
+	control := revisioncontrol.NewRevisionControl()
+	cs := clonesettest.NewCloneSet(1)
+	cs.Spec.UpdateStrategy.Type = appsv1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType
+	currentRevision, err := control.NewRevision(cs, 1, new(int32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs.Spec.Template.Spec.Containers[0].Image = "nginx:new"
+	updateRevision, err := control.NewRevision(cs, 2, new(int32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff := getUpdateRevisionDiff(cs, currentRevision, updateRevision)
+	if diff == nil || diff.UpdateType != appsv1alpha1.InPlaceCloneSetRevisionUpdateType {
+		t.Fatalf("expected in-place update diff, got %+v", diff)
+	}
+	cs.Status.UpdateRevisionDiff = diff
+	if got := getUpdateRevisionDiff(cs, currentRevision, updateRevision); got != diff {
+		t.Fatalf("expected diff reused from status, got %+v", got)
+	}
+
+	// the diff should be recalculated once the update strategy type changed
+	cs.Spec.UpdateStrategy.Type = appsv1alpha1.RecreateCloneSetUpdateStrategyType
+	got := getUpdateRevisionDiff(cs, currentRevision, updateRevision)
+	if got == nil || got.UpdateType != appsv1alpha1.RecreateCloneSetRevisionUpdateType ||
+		got.UpdateStrategyType != appsv1alpha1.RecreateCloneSetUpdateStrategyType {
+		t.Fatalf("expected recreate update diff, got %+v", got)
+	}
+}
@@ -144,6 +144,7 @@ func (c *commonControl) GetUpdateOptions() *inplaceupdate.UpdateOptions {
 	opts := &inplaceupdate.UpdateOptions{}
 	if c.Spec.UpdateStrategy.InPlaceUpdateStrategy != nil {
 		opts.GracePeriodSeconds = c.Spec.UpdateStrategy.InPlaceUpdateStrategy.GracePeriodSeconds
+		opts.ContainerUpdateOrder = c.Spec.UpdateStrategy.InPlaceUpdateStrategy.ContainerUpdateOrder
 	}
 	// For the InPlaceOnly strategy, ignore the hash comparison of VolumeClaimTemplates.
 	// Consider making changes through a feature gate.
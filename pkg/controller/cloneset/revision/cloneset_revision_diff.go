@@ -0,0 +1,144 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	clonesetcore "github.com/openkruise/kruise/pkg/controller/cloneset/core"
+	"github.com/openkruise/kruise/pkg/util/inplaceupdate"
+	"github.com/openkruise/kruise/pkg/util/volumeclaimtemplate"
+)
+
+// DiffRevisions calculates what changed from currentRevision to updateRevision,
+// and the way pods of CloneSet will be updated between them.
+func DiffRevisions(cs *appsv1alpha1.CloneSet, currentRevision, updateRevision *apps.ControllerRevision) (*appsv1alpha1.CloneSetRevisionDiff, error) {
+	oldTemp, err := inplaceupdate.GetTemplateFromRevision(currentRevision)
+	if err != nil {
+		return nil, err
+	}
+	newTemp, err := inplaceupdate.GetTemplateFromRevision(updateRevision)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &appsv1alpha1.CloneSetRevisionDiff{
+		CurrentRevision:    currentRevision.Name,
+		UpdateRevision:     updateRevision.Name,
+		UpdateStrategyType: cs.Spec.UpdateStrategy.Type,
+		UpdateType:         appsv1alpha1.RecreateCloneSetRevisionUpdateType,
+	}
+
+	if !reflect.DeepEqual(oldTemp.Labels, newTemp.Labels) {
+		diff.Fields = append(diff.Fields, "metadata.labels")
+	}
+	if !reflect.DeepEqual(oldTemp.Annotations, newTemp.Annotations) {
+		diff.Fields = append(diff.Fields, "metadata.annotations")
+	}
+
+	oldContainers := make(map[string]*v1.Container, len(oldTemp.Spec.Containers))
+	for i := range oldTemp.Spec.Containers {
+		oldContainers[oldTemp.Spec.Containers[i].Name] = &oldTemp.Spec.Containers[i]
+	}
+	containersChanged := len(oldTemp.Spec.Containers) != len(newTemp.Spec.Containers)
+	for i := range newTemp.Spec.Containers {
+		newContainer := &newTemp.Spec.Containers[i]
+		oldContainer, ok := oldContainers[newContainer.Name]
+		if !ok {
+			containersChanged = true
+			continue
+		}
+		fields, err := diffFields(oldContainer, newContainer)
+		if err != nil {
+			return nil, err
+		}
+		if len(fields) > 0 {
+			diff.Containers = append(diff.Containers, appsv1alpha1.CloneSetContainerDiff{Name: newContainer.Name, Fields: fields})
+		}
+	}
+	if containersChanged {
+		diff.Fields = append(diff.Fields, "spec.containers")
+	}
+
+	// compare the other fields in pod spec
+	oldSpec, newSpec := oldTemp.Spec.DeepCopy(), newTemp.Spec.DeepCopy()
+	oldSpec.Containers, newSpec.Containers = nil, nil
+	specFields, err := diffFields(oldSpec, newSpec)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range specFields {
+		diff.Fields = append(diff.Fields, "spec."+f)
+	}
+
+	oldVCTHash, oldExist := volumeclaimtemplate.GetVCTemplatesHash(currentRevision)
+	newVCTHash, newExist := volumeclaimtemplate.GetVCTemplatesHash(updateRevision)
+	diff.VolumeClaimTemplatesChanged = oldExist && newExist && oldVCTHash != newVCTHash
+
+	if cs.Spec.UpdateStrategy.Type == appsv1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType ||
+		cs.Spec.UpdateStrategy.Type == appsv1alpha1.InPlaceOnlyCloneSetUpdateStrategyType {
+		opts := inplaceupdate.SetOptionsDefaults(clonesetcore.New(cs).GetUpdateOptions())
+		if opts.CalculateSpec(currentRevision, updateRevision, opts) != nil {
+			diff.UpdateType = appsv1alpha1.InPlaceCloneSetRevisionUpdateType
+		}
+	}
+	return diff, nil
+}
+
+// diffFields returns the sorted top-level json fields that are different between the two objects.
+func diffFields(oldObj, newObj interface{}) ([]string, error) {
+	oldFields, err := toFieldMap(oldObj)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := toFieldMap(newObj)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []string
+	for key, oldValue := range oldFields {
+		if newValue, ok := newFields[key]; !ok || !reflect.DeepEqual(oldValue, newValue) {
+			fields = append(fields, key)
+		}
+	}
+	for key := range newFields {
+		if _, ok := oldFields[key]; !ok {
+			fields = append(fields, key)
+		}
+	}
+	sort.Strings(fields)
+	return fields, nil
+}
+
+func toFieldMap(obj interface{}) (map[string]interface{}, error) {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]interface{})
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	clonesettest "github.com/openkruise/kruise/pkg/controller/cloneset/test"
+	"github.com/openkruise/kruise/pkg/features"
+	utilfeature "github.com/openkruise/kruise/pkg/util/feature"
+)
+
+func TestDiffRevisions(t *testing.T) {
+	cases := []struct {
+		name         string
+		strategyType appsv1alpha1.CloneSetUpdateStrategyType
+		recreateVCT  bool
+		modify       func(cs *appsv1alpha1.CloneSet)
+		expected     *appsv1alpha1.CloneSetRevisionDiff
+	}{
+		{
+			name:         "image changed with in-place update",
+			strategyType: appsv1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType,
+			modify: func(cs *appsv1alpha1.CloneSet) {
+				cs.Spec.Template.Spec.Containers[0].Image = "nginx:new"
+			},
+			expected: &appsv1alpha1.CloneSetRevisionDiff{
+				UpdateType: appsv1alpha1.InPlaceCloneSetRevisionUpdateType,
+				Containers: []appsv1alpha1.CloneSetContainerDiff{{Name: "nginx", Fields: []string{"image"}}},
+			},
+		},
+		{
+			name:         "image changed with recreate update",
+			strategyType: appsv1alpha1.RecreateCloneSetUpdateStrategyType,
+			modify: func(cs *appsv1alpha1.CloneSet) {
+				cs.Spec.Template.Spec.Containers[0].Image = "nginx:new"
+			},
+			expected: &appsv1alpha1.CloneSetRevisionDiff{
+				UpdateType: appsv1alpha1.RecreateCloneSetRevisionUpdateType,
+				Containers: []appsv1alpha1.CloneSetContainerDiff{{Name: "nginx", Fields: []string{"image"}}},
+			},
+		},
+		{
+			name:         "env and labels changed with in-place update",
+			strategyType: appsv1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType,
+			modify: func(cs *appsv1alpha1.CloneSet) {
+				cs.Spec.Template.Labels["version"] = "v2"
+				cs.Spec.Template.Spec.Containers[0].Env = []v1.EnvVar{{Name: "foo", Value: "bar"}}
+				cs.Spec.Template.Spec.Hostname = "foo"
+			},
+			expected: &appsv1alpha1.CloneSetRevisionDiff{
+				UpdateType: appsv1alpha1.RecreateCloneSetRevisionUpdateType,
+				Containers: []appsv1alpha1.CloneSetContainerDiff{{Name: "nginx", Fields: []string{"env"}}},
+				Fields:     []string{"metadata.labels", "spec.hostname"},
+			},
+		},
+		{
+			name:         "container added",
+			strategyType: appsv1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType,
+			modify: func(cs *appsv1alpha1.CloneSet) {
+				cs.Spec.Template.Spec.Containers = append(cs.Spec.Template.Spec.Containers, v1.Container{Name: "sidecar", Image: "busybox"})
+			},
+			expected: &appsv1alpha1.CloneSetRevisionDiff{
+				UpdateType: appsv1alpha1.RecreateCloneSetRevisionUpdateType,
+				Fields:     []string{"spec.containers"},
+			},
+		},
+		{
+			name:         "volumeClaimTemplates changed",
+			strategyType: appsv1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType,
+			recreateVCT:  true,
+			modify: func(cs *appsv1alpha1.CloneSet) {
+				cs.Spec.VolumeClaimTemplates[0].Spec.Resources.Requests[v1.ResourceStorage] = resource.MustParse("20Gi")
+			},
+			expected: &appsv1alpha1.CloneSetRevisionDiff{
+				UpdateType:                  appsv1alpha1.RecreateCloneSetRevisionUpdateType,
+				VolumeClaimTemplatesChanged: true,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer utilfeature.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.RecreatePodWhenChangeVCTInCloneSetGate, tc.recreateVCT)()
+			control := NewRevisionControl()
+			cs := clonesettest.NewCloneSet(1)
+			cs.Spec.UpdateStrategy.Type = tc.strategyType
+			currentRevision, err := control.NewRevision(cs, 1, new(int32))
+			if err != nil {
+				t.Fatal(err)
+			}
+			tc.modify(cs)
+			updateRevision, err := control.NewRevision(cs, 2, new(int32))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := DiffRevisions(cs, currentRevision, updateRevision)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tc.expected.CurrentRevision = currentRevision.Name
+			tc.expected.UpdateRevision = updateRevision.Name
+			tc.expected.UpdateStrategyType = tc.strategyType
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Fatalf("expected %+v, got %+v", tc.expected, got)
+			}
+		})
+	}
+}
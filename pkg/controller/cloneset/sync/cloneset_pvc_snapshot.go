@@ -0,0 +1,256 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	clonesetutils "github.com/openkruise/kruise/pkg/controller/cloneset/utils"
+	"github.com/openkruise/kruise/pkg/util/lifecycle"
+)
+
+// The VolumeSnapshot objects are operated as unstructured, so that CloneSet does not depend on the snapshot client.
+var volumeSnapshotGVK = schema.GroupVersionKind{Group: "snapshot.storage.k8s.io", Version: "v1", Kind: "VolumeSnapshot"}
+
+// snapshotCheckInterval is the interval to check if the snapshots are ready to use.
+const snapshotCheckInterval = 5 * time.Second
+
+func newVolumeSnapshot() *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(volumeSnapshotGVK)
+	return obj
+}
+
+func isVolumeSnapshotReady(snapshot *unstructured.Unstructured) bool {
+	ready, _, _ := unstructured.NestedBool(snapshot.Object, "status", "readyToUse")
+	return ready && snapshot.GetDeletionTimestamp() == nil
+}
+
+// snapshotPVCsBeforeDelete makes sure that all PVCs of the Pods to delete have been snapshotted,
+// and returns the Pods whose snapshots are all ready to use, so that they can be deleted now.
+// The Pods hooked by preDelete are returned without snapshots, for they will only be marked as PreparingDelete
+// and may still write to the PVCs. Their PVCs will be snapshotted once the hook has been removed.
+func (r *realControl) snapshotPVCsBeforeDelete(cs *appsv1alpha1.CloneSet, pods []*v1.Pod, pvcs []*v1.PersistentVolumeClaim) ([]*v1.Pod, error) {
+	if cs.Spec.ScaleStrategy.PVCSnapshotPolicy == nil {
+		return pods, nil
+	}
+
+	podsCanDelete := make([]*v1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if cs.Spec.Lifecycle != nil && lifecycle.IsPodHooked(cs.Spec.Lifecycle.PreDelete, pod) {
+			podsCanDelete = append(podsCanDelete, pod)
+			continue
+		}
+
+		allReady := true
+		for _, pvc := range pvcs {
+			if pvc.Labels[appsv1alpha1.CloneSetInstanceID] != pod.Labels[appsv1alpha1.CloneSetInstanceID] {
+				continue
+			}
+			ready, err := r.ensurePVCSnapshot(cs, pvc)
+			if err != nil {
+				return nil, err
+			}
+			allReady = allReady && ready
+		}
+		if allReady {
+			podsCanDelete = append(podsCanDelete, pod)
+		} else {
+			klog.V(3).InfoS("CloneSet waited for snapshots of pod before deleting", "cloneSet", klog.KObj(cs), "pod", klog.KObj(pod))
+		}
+	}
+	if len(podsCanDelete) < len(pods) {
+		clonesetutils.DurationStore.Push(clonesetutils.GetControllerKey(cs), snapshotCheckInterval)
+	}
+	return podsCanDelete, nil
+}
+
+// ensurePVCSnapshot creates the snapshot for the PVC if not exists, and returns whether it is ready to use.
+// The snapshot has the same name as the PVC.
+func (r *realControl) ensurePVCSnapshot(cs *appsv1alpha1.CloneSet, pvc *v1.PersistentVolumeClaim) (bool, error) {
+	snapshot := newVolumeSnapshot()
+	err := r.Get(context.TODO(), types.NamespacedName{Namespace: pvc.Namespace, Name: pvc.Name}, snapshot)
+	if err == nil {
+		if snapshot.GetDeletionTimestamp() != nil {
+			return false, nil
+		}
+		// the snapshot created before the pvc is out of date, such as the one this pvc has been restored from
+		if isSnapshotCreatedBefore(snapshot, pvc) {
+			klog.V(3).InfoS("CloneSet deleting out-of-date snapshot of pvc", "cloneSet", klog.KObj(cs), "pvc", klog.KObj(pvc))
+			if err := r.Delete(context.TODO(), snapshot); err != nil && !errors.IsNotFound(err) {
+				return false, err
+			}
+			return false, nil
+		}
+		return isVolumeSnapshotReady(snapshot), nil
+	} else if !errors.IsNotFound(err) {
+		return false, err
+	}
+
+	snapshot.SetNamespace(pvc.Namespace)
+	snapshot.SetName(pvc.Name)
+	snapshot.SetLabels(pvc.Labels)
+	snapshot.SetOwnerReferences([]metav1.OwnerReference{*metav1.NewControllerRef(cs, clonesetutils.ControllerKind)})
+	spec := map[string]interface{}{
+		"source": map[string]interface{}{"persistentVolumeClaimName": pvc.Name},
+	}
+	if className := cs.Spec.ScaleStrategy.PVCSnapshotPolicy.VolumeSnapshotClassName; className != nil {
+		spec["volumeSnapshotClassName"] = *className
+	}
+	if err := unstructured.SetNestedMap(snapshot.Object, spec, "spec"); err != nil {
+		return false, err
+	}
+	if err := r.Create(context.TODO(), snapshot); err != nil && !errors.IsAlreadyExists(err) {
+		r.recorder.Eventf(cs, v1.EventTypeWarning, "FailedCreate", "failed to create snapshot for pvc %s: %v", pvc.Name, err)
+		return false, err
+	}
+	r.recorder.Eventf(cs, v1.EventTypeNormal, "SuccessfulCreate", "succeed to create snapshot for pvc %s", pvc.Name)
+	return false, nil
+}
+
+// getPVCSnapshotIDs returns the instance-ids of the ready snapshots owned by the CloneSet,
+// which can be reused to restore the PVCs when scaling out.
+func (r *realControl) getPVCSnapshotIDs(cs *appsv1alpha1.CloneSet) (sets.String, error) {
+	ids := sets.NewString()
+	if cs.Spec.ScaleStrategy.PVCSnapshotPolicy == nil {
+		return ids, nil
+	}
+
+	snapshots, err := r.listPVCSnapshots(cs)
+	if err != nil {
+		return nil, err
+	}
+	for _, snapshot := range snapshots {
+		if id := snapshot.GetLabels()[appsv1alpha1.CloneSetInstanceID]; id != "" && isVolumeSnapshotReady(snapshot) {
+			ids.Insert(id)
+		}
+	}
+	return ids, nil
+}
+
+// listPVCSnapshots returns the snapshots owned by the CloneSet, keyed by their names.
+func (r *realControl) listPVCSnapshots(cs *appsv1alpha1.CloneSet) (map[string]*unstructured.Unstructured, error) {
+	snapshotList := &unstructured.UnstructuredList{}
+	snapshotList.SetGroupVersionKind(volumeSnapshotGVK.GroupVersion().WithKind(volumeSnapshotGVK.Kind + "List"))
+	opts := []client.ListOption{client.InNamespace(cs.Namespace)}
+	if cs.Spec.Selector != nil {
+		opts = append(opts, client.MatchingLabels(cs.Spec.Selector.MatchLabels))
+	}
+	if err := r.List(context.TODO(), snapshotList, opts...); err != nil {
+		return nil, err
+	}
+	snapshots := make(map[string]*unstructured.Unstructured, len(snapshotList.Items))
+	for i := range snapshotList.Items {
+		snapshot := &snapshotList.Items[i]
+		if owner := metav1.GetControllerOf(snapshot); owner == nil || owner.UID != cs.UID {
+			continue
+		}
+		snapshots[snapshot.GetName()] = snapshot
+	}
+	return snapshots, nil
+}
+
+// restorePVCFromSnapshot sets the data source of the PVC to be created, if there is a ready snapshot with the same name.
+func (r *realControl) restorePVCFromSnapshot(cs *appsv1alpha1.CloneSet, pvc *v1.PersistentVolumeClaim) error {
+	if cs.Spec.ScaleStrategy.PVCSnapshotPolicy == nil || pvc.Spec.DataSource != nil || pvc.Spec.DataSourceRef != nil {
+		return nil
+	}
+
+	snapshot := newVolumeSnapshot()
+	if err := r.Get(context.TODO(), types.NamespacedName{Namespace: pvc.Namespace, Name: pvc.Name}, snapshot); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !isVolumeSnapshotReady(snapshot) {
+		return nil
+	}
+	pvc.Spec.DataSource = &v1.TypedLocalObjectReference{
+		APIGroup: ptr.To(volumeSnapshotGVK.Group),
+		Kind:     volumeSnapshotGVK.Kind,
+		Name:     snapshot.GetName(),
+	}
+	klog.V(3).InfoS("CloneSet restoring pvc from snapshot", "cloneSet", klog.KObj(cs), "pvc", klog.KObj(pvc))
+	return nil
+}
+
+// deleteRestoredPVCSnapshots deletes the snapshots owned by the CloneSet, which the bound PVCs have been restored from.
+// They are no longer needed, and the PVCs will be snapshotted again before deleted.
+// The PVCs are annotated once handled, so that the snapshots are only listed when there are new restored PVCs.
+func (r *realControl) deleteRestoredPVCSnapshots(cs *appsv1alpha1.CloneSet, pvcs []*v1.PersistentVolumeClaim) error {
+	if cs.Spec.ScaleStrategy.PVCSnapshotPolicy == nil {
+		return nil
+	}
+
+	var restoredPVCs []*v1.PersistentVolumeClaim
+	for _, pvc := range pvcs {
+		if pvc.Status.Phase == v1.ClaimBound && isPVCRestoredFromSnapshot(pvc) &&
+			pvc.Annotations[appsv1alpha1.CloneSetRestoredSnapshotDeletedAnnotation] != "true" {
+			restoredPVCs = append(restoredPVCs, pvc)
+		}
+	}
+	if len(restoredPVCs) == 0 {
+		return nil
+	}
+
+	snapshots, err := r.listPVCSnapshots(cs)
+	if err != nil {
+		return err
+	}
+	for _, pvc := range restoredPVCs {
+		// the snapshot created after the pvc is not the one it has been restored from
+		if snapshot, ok := snapshots[pvc.Name]; ok && snapshot.GetDeletionTimestamp() == nil && isSnapshotCreatedBefore(snapshot, pvc) {
+			klog.V(3).InfoS("CloneSet deleting snapshot that pvc has been restored from", "cloneSet", klog.KObj(cs), "pvc", klog.KObj(pvc))
+			if err := r.Delete(context.TODO(), snapshot); err != nil && !errors.IsNotFound(err) {
+				r.recorder.Eventf(cs, v1.EventTypeWarning, "FailedDelete", "failed to delete snapshot %s: %v", snapshot.GetName(), err)
+				return err
+			}
+			r.recorder.Eventf(cs, v1.EventTypeNormal, "SuccessfulDelete", "succeed to delete snapshot %s restored to pvc", snapshot.GetName())
+		}
+
+		body := fmt.Sprintf(`{"metadata":{"annotations":{"%s":"true"}}}`, appsv1alpha1.CloneSetRestoredSnapshotDeletedAnnotation)
+		if err := r.Patch(context.TODO(), pvc.DeepCopy(), client.RawPatch(types.MergePatchType, []byte(body))); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func isSnapshotCreatedBefore(snapshot *unstructured.Unstructured, pvc *v1.PersistentVolumeClaim) bool {
+	creationTimestamp := snapshot.GetCreationTimestamp()
+	return creationTimestamp.Before(&pvc.CreationTimestamp)
+}
+
+func isPVCRestoredFromSnapshot(pvc *v1.PersistentVolumeClaim) bool {
+	ds := pvc.Spec.DataSource
+	return ds != nil && ptr.Deref(ds.APIGroup, "") == volumeSnapshotGVK.Group && ds.Kind == volumeSnapshotGVK.Kind && ds.Name == pvc.Name
+}
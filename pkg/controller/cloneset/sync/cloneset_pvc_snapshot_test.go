@@ -0,0 +1,320 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appspub "github.com/openkruise/kruise/apis/apps/pub"
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	clonesetutils "github.com/openkruise/kruise/pkg/controller/cloneset/utils"
+)
+
+func newPVCSnapshotTestControl(objects ...client.Object) *realControl {
+	snapshotScheme := runtime.NewScheme()
+	utilruntime.Must(v1.AddToScheme(snapshotScheme))
+	snapshotScheme.AddKnownTypeWithName(volumeSnapshotGVK, &unstructured.Unstructured{})
+	snapshotScheme.AddKnownTypeWithName(volumeSnapshotGVK.GroupVersion().WithKind(volumeSnapshotGVK.Kind+"List"), &unstructured.UnstructuredList{})
+	return &realControl{
+		Client:   fake.NewClientBuilder().WithScheme(snapshotScheme).WithObjects(objects...).Build(),
+		recorder: record.NewFakeRecorder(10),
+	}
+}
+
+func newPVCSnapshotTestCloneSet() *appsv1alpha1.CloneSet {
+	return &appsv1alpha1.CloneSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo", UID: "uid-foo"},
+		Spec: appsv1alpha1.CloneSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}},
+			VolumeClaimTemplates: []v1.PersistentVolumeClaim{
+				{ObjectMeta: metav1.ObjectMeta{Name: "data"}},
+			},
+			ScaleStrategy: appsv1alpha1.CloneSetScaleStrategy{
+				PVCSnapshotPolicy: &appsv1alpha1.CloneSetPVCSnapshotPolicy{VolumeSnapshotClassName: ptr.To("csi-snapclass")},
+			},
+		},
+	}
+}
+
+func setVolumeSnapshotReady(t *testing.T, c client.Client, name string) {
+	snapshot := newVolumeSnapshot()
+	if err := c.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: name}, snapshot); err != nil {
+		t.Fatalf("failed to get snapshot: %v", err)
+	}
+	if err := unstructured.SetNestedField(snapshot.Object, true, "status", "readyToUse"); err != nil {
+		t.Fatalf("failed to set snapshot status: %v", err)
+	}
+	if err := c.Update(context.TODO(), snapshot); err != nil {
+		t.Fatalf("failed to update snapshot: %v", err)
+	}
+}
+
+func TestSnapshotPVCsBeforeDelete(t *testing.T) {
+	cs := newPVCSnapshotTestCloneSet()
+	labels := map[string]string{"app": "foo", appsv1alpha1.CloneSetInstanceID: "id1"}
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo-id1", Labels: labels}}
+	pvc := &v1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "data-foo-id1", Labels: labels}}
+	ctrl := newPVCSnapshotTestControl()
+
+	// the snapshot should be created, and the pod can not be deleted until it is ready
+	pods, err := ctrl.snapshotPVCsBeforeDelete(cs, []*v1.Pod{pod}, []*v1.PersistentVolumeClaim{pvc})
+	if err != nil || len(pods) != 0 {
+		t.Fatalf("expected no pods to delete, got %v, %v", pods, err)
+	}
+	snapshot := newVolumeSnapshot()
+	if err := ctrl.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: pvc.Name}, snapshot); err != nil {
+		t.Fatalf("failed to get snapshot: %v", err)
+	}
+	if source, _, _ := unstructured.NestedString(snapshot.Object, "spec", "source", "persistentVolumeClaimName"); source != pvc.Name {
+		t.Fatalf("expected snapshot source %s, got %s", pvc.Name, source)
+	}
+	if className, _, _ := unstructured.NestedString(snapshot.Object, "spec", "volumeSnapshotClassName"); className != "csi-snapclass" {
+		t.Fatalf("expected snapshot class csi-snapclass, got %s", className)
+	}
+	if owner := metav1.GetControllerOf(snapshot); owner == nil || owner.UID != cs.UID {
+		t.Fatalf("expected snapshot owned by cloneset, got %v", snapshot.GetOwnerReferences())
+	}
+
+	setVolumeSnapshotReady(t, ctrl.Client, pvc.Name)
+	pods, err = ctrl.snapshotPVCsBeforeDelete(cs, []*v1.Pod{pod}, []*v1.PersistentVolumeClaim{pvc})
+	if err != nil || len(pods) != 1 {
+		t.Fatalf("expected pod to delete, got %v, %v", pods, err)
+	}
+}
+
+func TestSnapshotPVCsBeforeDeleteWithPreDeleteHook(t *testing.T) {
+	cs := newPVCSnapshotTestCloneSet()
+	cs.Spec.Lifecycle = &appspub.Lifecycle{PreDelete: &appspub.LifecycleHook{LabelsHandler: map[string]string{"pre-delete": "true"}}}
+	labels := map[string]string{"app": "foo", appsv1alpha1.CloneSetInstanceID: "id1", "pre-delete": "true"}
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo-id1", Labels: labels}}
+	pvc := &v1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "data-foo-id1", Labels: labels}}
+	ctrl := newPVCSnapshotTestControl()
+
+	// the pod still hooked by preDelete may write to the pvc, so it is not snapshotted yet
+	pods, err := ctrl.snapshotPVCsBeforeDelete(cs, []*v1.Pod{pod}, []*v1.PersistentVolumeClaim{pvc})
+	if err != nil || len(pods) != 1 {
+		t.Fatalf("expected pod to enter preDelete, got %v, %v", pods, err)
+	}
+	if err := ctrl.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: pvc.Name}, newVolumeSnapshot()); !errors.IsNotFound(err) {
+		t.Fatalf("expected no snapshot for pod in preDelete, got %v", err)
+	}
+
+	// the snapshot is taken after the hook removed
+	delete(pod.Labels, "pre-delete")
+	pods, err = ctrl.snapshotPVCsBeforeDelete(cs, []*v1.Pod{pod}, []*v1.PersistentVolumeClaim{pvc})
+	if err != nil || len(pods) != 0 {
+		t.Fatalf("expected no pods to delete, got %v, %v", pods, err)
+	}
+	if err := ctrl.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: pvc.Name}, newVolumeSnapshot()); err != nil {
+		t.Fatalf("expected snapshot created after preDelete, got %v", err)
+	}
+}
+
+func TestSnapshotPVCsBeforeDeleteWithOutOfDateSnapshot(t *testing.T) {
+	cs := newPVCSnapshotTestCloneSet()
+	labels := map[string]string{"app": "foo", appsv1alpha1.CloneSetInstanceID: "id1"}
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo-id1", Labels: labels}}
+	pvc := &v1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "default", Name: "data-foo-id1", Labels: labels, CreationTimestamp: metav1.Now(),
+	}}
+	snapshot := newVolumeSnapshot()
+	snapshot.SetNamespace("default")
+	snapshot.SetName(pvc.Name)
+	snapshot.SetCreationTimestamp(metav1.NewTime(time.Now().Add(-time.Hour)))
+	ctrl := newPVCSnapshotTestControl(snapshot)
+
+	pods, err := ctrl.snapshotPVCsBeforeDelete(cs, []*v1.Pod{pod}, []*v1.PersistentVolumeClaim{pvc})
+	if err != nil || len(pods) != 0 {
+		t.Fatalf("expected no pods to delete, got %v, %v", pods, err)
+	}
+	if err := ctrl.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: pvc.Name}, newVolumeSnapshot()); !errors.IsNotFound(err) {
+		t.Fatalf("expected out-of-date snapshot deleted, got %v", err)
+	}
+}
+
+func TestRestorePVCFromSnapshot(t *testing.T) {
+	cs := newPVCSnapshotTestCloneSet()
+	labels := map[string]string{"app": "foo", appsv1alpha1.CloneSetInstanceID: "id1"}
+	pvc := &v1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "data-foo-id1", Labels: labels}}
+	ctrl := newPVCSnapshotTestControl()
+	if _, err := ctrl.snapshotPVCsBeforeDelete(cs, []*v1.Pod{{ObjectMeta: metav1.ObjectMeta{Labels: labels}}}, []*v1.PersistentVolumeClaim{pvc}); err != nil {
+		t.Fatalf("failed to snapshot: %v", err)
+	}
+
+	// the snapshot is not ready to restore
+	if ids, err := ctrl.getPVCSnapshotIDs(cs); err != nil || ids.Len() != 0 {
+		t.Fatalf("expected no snapshot ids, got %v, %v", ids, err)
+	}
+	newPVC := &v1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "data-foo-id1"}}
+	if err := ctrl.restorePVCFromSnapshot(cs, newPVC); err != nil || newPVC.Spec.DataSource != nil {
+		t.Fatalf("expected no data source, got %v, %v", newPVC.Spec.DataSource, err)
+	}
+
+	setVolumeSnapshotReady(t, ctrl.Client, pvc.Name)
+	if ids, err := ctrl.getPVCSnapshotIDs(cs); err != nil || !ids.Has("id1") || ids.Len() != 1 {
+		t.Fatalf("expected snapshot ids [id1], got %v, %v", ids, err)
+	}
+	if err := ctrl.restorePVCFromSnapshot(cs, newPVC); err != nil {
+		t.Fatalf("failed to restore pvc: %v", err)
+	}
+	if ds := newPVC.Spec.DataSource; ds == nil || ds.Kind != "VolumeSnapshot" || ds.Name != pvc.Name || ptr.Deref(ds.APIGroup, "") != "snapshot.storage.k8s.io" {
+		t.Fatalf("unexpected data source %v", ds)
+	}
+
+	otherPVC := &v1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "data-foo-id2"}}
+	if err := ctrl.restorePVCFromSnapshot(cs, otherPVC); err != nil || otherPVC.Spec.DataSource != nil {
+		t.Fatalf("expected no data source, got %v, %v", otherPVC.Spec.DataSource, err)
+	}
+}
+
+func TestDeleteRestoredPVCSnapshots(t *testing.T) {
+	cs := newPVCSnapshotTestCloneSet()
+	newSnapshot := func(name string, age time.Duration) *unstructured.Unstructured {
+		snapshot := newVolumeSnapshot()
+		snapshot.SetNamespace("default")
+		snapshot.SetName(name)
+		snapshot.SetLabels(map[string]string{"app": "foo"})
+		snapshot.SetCreationTimestamp(metav1.NewTime(time.Now().Add(-age)))
+		snapshot.SetOwnerReferences([]metav1.OwnerReference{*metav1.NewControllerRef(cs, appsv1alpha1.SchemeGroupVersion.WithKind("CloneSet"))})
+		return snapshot
+	}
+	newPVC := func(name string, phase v1.PersistentVolumeClaimPhase, restored bool) *v1.PersistentVolumeClaim {
+		pvc := &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Minute))},
+			Status:     v1.PersistentVolumeClaimStatus{Phase: phase},
+		}
+		if restored {
+			pvc.Spec.DataSource = &v1.TypedLocalObjectReference{APIGroup: ptr.To(volumeSnapshotGVK.Group), Kind: volumeSnapshotGVK.Kind, Name: name}
+		}
+		return pvc
+	}
+
+	pvcs := []*v1.PersistentVolumeClaim{
+		// restored and bound
+		newPVC("data-foo-id1", v1.ClaimBound, true),
+		// restoring
+		newPVC("data-foo-id2", v1.ClaimPending, true),
+		// not restored from snapshot
+		newPVC("data-foo-id3", v1.ClaimBound, false),
+		// snapshotted again after restored
+		newPVC("data-foo-id4", v1.ClaimBound, true),
+	}
+	ctrl := newPVCSnapshotTestControl(
+		newSnapshot("data-foo-id1", time.Hour),
+		newSnapshot("data-foo-id2", time.Hour),
+		newSnapshot("data-foo-id3", time.Hour),
+		newSnapshot("data-foo-id4", time.Second),
+		pvcs[0], pvcs[1], pvcs[2], pvcs[3],
+	)
+
+	// nothing to do without pvcSnapshotPolicy
+	noPolicyCS := cs.DeepCopy()
+	noPolicyCS.Spec.ScaleStrategy.PVCSnapshotPolicy = nil
+	if err := ctrl.deleteRestoredPVCSnapshots(noPolicyCS, pvcs); err != nil {
+		t.Fatalf("failed to delete restored snapshots: %v", err)
+	}
+	if err := ctrl.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: "data-foo-id1"}, newVolumeSnapshot()); err != nil {
+		t.Fatalf("expected snapshot kept without pvcSnapshotPolicy, got %v", err)
+	}
+
+	if err := ctrl.deleteRestoredPVCSnapshots(cs, pvcs); err != nil {
+		t.Fatalf("failed to delete restored snapshots: %v", err)
+	}
+	for _, pvc := range pvcs {
+		err := ctrl.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: pvc.Name}, newVolumeSnapshot())
+		if pvc.Name == "data-foo-id1" {
+			if !errors.IsNotFound(err) {
+				t.Fatalf("expected snapshot %s deleted, got %v", pvc.Name, err)
+			}
+		} else if err != nil {
+			t.Fatalf("expected snapshot %s kept, got %v", pvc.Name, err)
+		}
+
+		// the restored and bound pvcs are marked as handled
+		got := &v1.PersistentVolumeClaim{}
+		if err := ctrl.Get(context.TODO(), client.ObjectKeyFromObject(pvc), got); err != nil {
+			t.Fatalf("failed to get pvc: %v", err)
+		}
+		expectMarked := pvc.Name == "data-foo-id1" || pvc.Name == "data-foo-id4"
+		if marked := got.Annotations[appsv1alpha1.CloneSetRestoredSnapshotDeletedAnnotation] == "true"; marked != expectMarked {
+			t.Fatalf("expected pvc %s marked %v, got annotations %v", pvc.Name, expectMarked, got.Annotations)
+		}
+	}
+
+	// the marked pvcs are skipped, even if a snapshot with the same name comes back
+	marked := newPVC("data-foo-id5", v1.ClaimBound, true)
+	marked.Annotations = map[string]string{appsv1alpha1.CloneSetRestoredSnapshotDeletedAnnotation: "true"}
+	if err := ctrl.Create(context.TODO(), newSnapshot("data-foo-id5", time.Hour)); err != nil {
+		t.Fatalf("failed to create snapshot: %v", err)
+	}
+	if err := ctrl.deleteRestoredPVCSnapshots(cs, []*v1.PersistentVolumeClaim{marked}); err != nil {
+		t.Fatalf("failed to delete restored snapshots: %v", err)
+	}
+	if err := ctrl.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: "data-foo-id5"}, newVolumeSnapshot()); err != nil {
+		t.Fatalf("expected snapshot of marked pvc kept, got %v", err)
+	}
+}
+
+func TestDeleteReplacedPodsWithSnapshot(t *testing.T) {
+	cs := newPVCSnapshotTestCloneSet()
+	replacedLabels := map[string]string{"app": "foo", appsv1alpha1.CloneSetInstanceID: "id1", appsv1alpha1.SpecifiedReplaceKey: "true"}
+	replaced := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo-id1", Labels: replacedLabels}}
+	replacement := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo-id2", Labels: map[string]string{
+			"app": "foo", appsv1alpha1.CloneSetInstanceID: "id2", appsv1alpha1.CloneSetReplacementForLabelKey: "id1",
+		}},
+		Status: v1.PodStatus{
+			Phase:      v1.PodRunning,
+			Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}},
+		},
+	}
+	pvc := &v1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "default", Name: "data-foo-id1", Labels: map[string]string{"app": "foo", appsv1alpha1.CloneSetInstanceID: "id1"},
+	}}
+	ctrl := newPVCSnapshotTestControl(replaced, replacement, pvc)
+	defer clonesetutils.ScaleExpectations.DeleteExpectations(clonesetutils.GetControllerKey(cs))
+	replacedPods, replacementPods := getReplacePods(cs, []*v1.Pod{replaced, replacement})
+
+	// the replaced pod should not be deleted until the snapshot of its pvc is ready
+	if modified, err := ctrl.deleteReplacedPods(cs, replacedPods, replacementPods, []*v1.PersistentVolumeClaim{pvc}); err != nil || modified {
+		t.Fatalf("expected replaced pod not deleted, got %v, %v", modified, err)
+	}
+	if err := ctrl.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: pvc.Name}, newVolumeSnapshot()); err != nil {
+		t.Fatalf("expected snapshot created, got %v", err)
+	}
+
+	setVolumeSnapshotReady(t, ctrl.Client, pvc.Name)
+	if modified, err := ctrl.deleteReplacedPods(cs, replacedPods, replacementPods, []*v1.PersistentVolumeClaim{pvc}); err != nil || !modified {
+		t.Fatalf("expected replaced pod deleted, got %v, %v", modified, err)
+	}
+	if err := ctrl.Get(context.TODO(), client.ObjectKeyFromObject(replaced), &v1.Pod{}); !errors.IsNotFound(err) {
+		t.Fatalf("expected replaced pod deleted, got %v", err)
+	}
+}
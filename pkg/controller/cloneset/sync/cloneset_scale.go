@@ -24,9 +24,12 @@ import (
 	"sync/atomic"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/rand"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/integer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appspub "github.com/openkruise/kruise/apis/apps/pub"
 	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
@@ -35,6 +38,7 @@ import (
 	"github.com/openkruise/kruise/pkg/util"
 	"github.com/openkruise/kruise/pkg/util/expectations"
 	"github.com/openkruise/kruise/pkg/util/lifecycle"
+	utilpodreadiness "github.com/openkruise/kruise/pkg/util/podreadiness"
 	"github.com/openkruise/kruise/pkg/util/revision"
 )
 
@@ -67,45 +71,71 @@ func (r *realControl) Scale(
 		return modified, err
 	}
 
-	// 2. calculate scale numbers
+	// 2. delete pods specified to replace, whose replacement pods have been available
+	replacedPods, replacementPods := getReplacePods(updateCS, pods)
+	if modified, err := r.deleteReplacedPods(updateCS, replacedPods, replacementPods, pvcs); err != nil || modified {
+		return modified, err
+	}
+
+	// 3. delete the snapshots that have been restored to pvcs
+	if err := r.deleteRestoredPVCSnapshots(updateCS, pvcs); err != nil {
+		return false, err
+	}
+
+	// 4. calculate scale numbers
 	diffRes := calculateDiffsWithExpectation(updateCS, pods, currentRevision, updateRevision, revision.IsPodUpdate)
-	updatedPods, notUpdatedPods := clonesetutils.GroupUpdateAndNotUpdatePods(pods, updateRevision)
+	// replacement pods should not be chosen to scale in
+	updatedPods, notUpdatedPods := clonesetutils.GroupUpdateAndNotUpdatePods(excludeReplacementPods(pods, replacementPods), updateRevision)
 
 	if diffRes.scaleUpNum > diffRes.scaleUpLimit {
 		r.recorder.Event(updateCS, v1.EventTypeWarning, "ScaleUpLimited", fmt.Sprintf("scaleUp is limited because of scaleStrategy.maxUnavailable, limit: %d", diffRes.scaleUpLimit))
 	}
 
-	// 3. scale out
+	// 5. scale out
 	if diffRes.scaleUpNum > 0 {
 		// total number of this creation
 		expectedCreations := diffRes.scaleUpLimit
 		// lack number of current version
 		expectedCurrentCreations := diffRes.scaleUpNumOldRevision
+		// number of in-place update surge pods
+		expectedSurgeCreations := diffRes.scaleUpNumInPlaceSurge
+		// instance-ids of the pods waiting for replacement
+		replacedIDs := getPendingReplacedIDs(replacedPods, replacementPods, diffRes.scaleUpNumReplace)
 
 		klog.V(3).InfoS("CloneSet began to scale out pods, including current revision",
-			"cloneSet", klog.KObj(updateCS), "expectedCreations", expectedCreations, "expectedCurrentCreations", expectedCurrentCreations)
+			"cloneSet", klog.KObj(updateCS), "expectedCreations", expectedCreations, "expectedCurrentCreations", expectedCurrentCreations,
+			"expectedSurgeCreations", expectedSurgeCreations, "replacedIDs", replacedIDs)
 
-		// available instance-id come from free pvc
-		availableIDs := getOrGenAvailableIDs(expectedCreations, pods, pvcs)
+		// instance-id of the snapshots to restore pvcs
+		snapshotIDs, err := r.getPVCSnapshotIDs(updateCS)
+		if err != nil {
+			return false, err
+		}
+		// available instance-id come from free pvc and snapshots
+		availableIDs := getOrGenAvailableIDs(expectedCreations, pods, pvcs, snapshotIDs)
 		// existing pvc names
 		existingPVCNames := sets.NewString()
 		for _, pvc := range pvcs {
 			existingPVCNames.Insert(pvc.Name)
 		}
 
-		return r.createPods(expectedCreations, expectedCurrentCreations,
+		return r.createPods(expectedCreations, expectedCurrentCreations, expectedSurgeCreations, replacedIDs,
 			currentCS, updateCS, currentRevision, updateRevision, availableIDs.List(), existingPVCNames)
 	}
 
-	// 4. try to delete pods already in pre-delete
+	// 6. try to delete pods already in pre-delete
 	if len(podsInPreDelete) > 0 {
 		klog.V(3).InfoS("CloneSet tried to delete pods in preDelete", "cloneSet", klog.KObj(updateCS), "pods", util.GetPodNames(podsInPreDelete).List())
-		if modified, err := r.deletePods(updateCS, podsInPreDelete, pvcs); err != nil || modified {
+		podsCanDelete, err := r.snapshotPVCsBeforeDelete(updateCS, podsInPreDelete, pvcs)
+		if err != nil {
+			return false, err
+		}
+		if modified, err := r.deletePods(updateCS, podsCanDelete, pvcs); err != nil || modified {
 			return modified, err
 		}
 	}
 
-	// 5. specified delete
+	// 7. specified delete
 	if podsToDelete := util.DiffPods(podsSpecifiedToDelete, podsInPreDelete); len(podsToDelete) > 0 {
 		newPodsToDelete, oldPodsToDelete := clonesetutils.GroupUpdateAndNotUpdatePods(podsToDelete, updateRevision)
 		klog.V(3).InfoS("CloneSet tried to delete pods specified", "cloneSet", klog.KObj(updateCS), "deleteReadyLimit", diffRes.deleteReadyLimit,
@@ -121,12 +151,17 @@ func (r *realControl) Scale(
 			}
 		}
 
+		// take snapshots of pvcs before deleting pods specified
+		podsCanDelete, err := r.snapshotPVCsBeforeDelete(updateCS, podsCanDelete, pvcs)
+		if err != nil {
+			return false, err
+		}
 		if modified, err := r.deletePods(updateCS, podsCanDelete, pvcs); err != nil || modified {
 			return modified, err
 		}
 	}
 
-	// 6. scale in
+	// 8. scale in
 	if diffRes.scaleDownNum > 0 {
 		if numToDelete > 0 {
 			klog.V(3).InfoS("CloneSet skipped to scale in for deletion", "cloneSet", klog.KObj(updateCS), "scaleDownNum", diffRes.scaleDownNum,
@@ -135,9 +170,21 @@ func (r *realControl) Scale(
 		}
 
 		klog.V(3).InfoS("CloneSet began to scale in", "cloneSet", klog.KObj(updateCS), "scaleDownNum", diffRes.scaleDownNum,
-			"oldRevision", diffRes.scaleDownNumOldRevision, "deleteReadyLimit", diffRes.deleteReadyLimit)
-
-		podsPreparingToDelete := r.choosePodsToDelete(updateCS, diffRes.scaleDownNum, diffRes.scaleDownNumOldRevision, notUpdatedPods, updatedPods)
+			"oldRevision", diffRes.scaleDownNumOldRevision, "inPlaceSurge", diffRes.scaleDownNumInPlaceSurge, "deleteReadyLimit", diffRes.deleteReadyLimit)
+
+		var podsPreparingToDelete []*v1.Pod
+		// in-place update surge pods are preferred to be deleted
+		if diffRes.scaleDownNumInPlaceSurge > 0 {
+			var surgePods, updatedSurgePods []*v1.Pod
+			surgePods, notUpdatedPods = splitInPlaceUpdateSurgePods(notUpdatedPods)
+			updatedSurgePods, updatedPods = splitInPlaceUpdateSurgePods(updatedPods)
+			surgePods = append(surgePods, updatedSurgePods...)
+			podsPreparingToDelete = r.choosePodsToDelete(updateCS, integer.IntMin(diffRes.scaleDownNumInPlaceSurge, len(surgePods)), 0, nil, surgePods)
+		}
+		if num := diffRes.scaleDownNum - len(podsPreparingToDelete); num > 0 {
+			podsPreparingToDelete = append(podsPreparingToDelete,
+				r.choosePodsToDelete(updateCS, num, diffRes.scaleDownNumOldRevision, notUpdatedPods, updatedPods)...)
+		}
 		podsToDelete := make([]*v1.Pod, 0, len(podsPreparingToDelete))
 		for _, pod := range podsPreparingToDelete {
 			if !isPodReady(coreControl, pod) {
@@ -148,6 +195,12 @@ func (r *realControl) Scale(
 			}
 		}
 
+		// take snapshots of pvcs before deleting pods for scaling in
+		podsToDelete, err := r.snapshotPVCsBeforeDelete(updateCS, podsToDelete, pvcs)
+		if err != nil {
+			return false, err
+		}
+
 		return r.deletePods(updateCS, podsToDelete, pvcs)
 	}
 
@@ -187,7 +240,7 @@ func (r *realControl) managePreparingDelete(cs *appsv1alpha1.CloneSet, pods, pod
 }
 
 func (r *realControl) createPods(
-	expectedCreations, expectedCurrentCreations int,
+	expectedCreations, expectedCurrentCreations, expectedSurgeCreations int, replacedIDs []string,
 	currentCS, updateCS *appsv1alpha1.CloneSet,
 	currentRevision, updateRevision string,
 	availableIDs []string, existingPVCNames sets.String,
@@ -199,6 +252,25 @@ func (r *realControl) createPods(
 	if err != nil {
 		return false, err
 	}
+	for _, p := range newPods {
+		if expectedSurgeCreations <= 0 {
+			break
+		}
+		if clonesetutils.EqualToRevisionHash("", p, updateRevision) {
+			p.Labels[appsv1alpha1.CloneSetInPlaceUpdateSurgePodLabelKey] = "true"
+			expectedSurgeCreations--
+		}
+	}
+	for _, p := range newPods {
+		if len(replacedIDs) == 0 {
+			break
+		}
+		if isInPlaceUpdateSurgePod(p) {
+			continue
+		}
+		p.Labels[appsv1alpha1.CloneSetReplacementForLabelKey] = replacedIDs[0]
+		replacedIDs = replacedIDs[1:]
+	}
 
 	podsCreationChan := make(chan *v1.Pod, len(newPods))
 	for _, p := range newPods {
@@ -247,6 +319,10 @@ func (r *realControl) createOnePod(cs *appsv1alpha1.CloneSet, pod *v1.Pod, exist
 		if existingPVCNames.Has(c.Name) {
 			continue
 		}
+		if err := r.restorePVCFromSnapshot(cs, &c); err != nil {
+			r.recorder.Eventf(cs, v1.EventTypeWarning, "FailedCreate", "failed to restore pvc %s from snapshot: %v", c.Name, err)
+			return err
+		}
 		clonesetutils.ScaleExpectations.ExpectScale(clonesetutils.GetControllerKey(cs), expectations.Create, c.Name)
 		if err := r.Create(context.TODO(), &c); err != nil {
 			clonesetutils.ScaleExpectations.ObserveScale(clonesetutils.GetControllerKey(cs), expectations.Create, c.Name)
@@ -261,6 +337,16 @@ func (r *realControl) createOnePod(cs *appsv1alpha1.CloneSet, pod *v1.Pod, exist
 	}
 
 	r.recorder.Eventf(cs, v1.EventTypeNormal, "SuccessfulCreate", "succeed to create pod %s", pod.Name)
+
+	// initialize the standby pod as not ready before pod-readiness-controller marks it ready,
+	// otherwise the standby key will be added in the next reconcile
+	if pod.Labels[appsv1alpha1.CloneSetStandbyPodLabelKey] == "true" {
+		status := v1.PodStatus{Conditions: []v1.PodCondition{utilpodreadiness.NewNotReadyCondition(clonesetutils.StandbyNotReadyMessage)}}
+		patchBody := fmt.Sprintf(`{"status":%s}`, util.DumpJSON(status))
+		if err := r.Status().Patch(context.TODO(), pod, client.RawPatch(types.StrategicMergePatchType, []byte(patchBody))); err != nil {
+			klog.ErrorS(err, "Failed to initialize readiness of standby pod", "cloneSet", klog.KObj(cs), "pod", klog.KObj(pod))
+		}
+	}
 	return nil
 }
 
@@ -307,6 +393,68 @@ func (r *realControl) deletePods(cs *appsv1alpha1.CloneSet, podsToDelete []*v1.P
 	return modified, nil
 }
 
+// deleteReplacedPods deletes the pods specified to replace, once their replacement pods have been available.
+func (r *realControl) deleteReplacedPods(cs *appsv1alpha1.CloneSet, replacedPods, replacementPods map[string]*v1.Pod, pvcs []*v1.PersistentVolumeClaim) (bool, error) {
+	coreControl := clonesetcore.New(cs)
+	var podsToDelete []*v1.Pod
+	for id, pod := range replacedPods {
+		replacement, ok := replacementPods[id]
+		if !ok || !IsPodAvailable(coreControl, replacement, cs.Spec.MinReadySeconds) {
+			continue
+		}
+		klog.V(3).InfoS("CloneSet found pod replaced by available pod", "cloneSet", klog.KObj(cs), "pod", klog.KObj(pod), "replacement", klog.KObj(replacement))
+		podsToDelete = append(podsToDelete, pod)
+	}
+	if len(podsToDelete) == 0 {
+		return false, nil
+	}
+	sort.Slice(podsToDelete, func(i, j int) bool { return podsToDelete[i].Name < podsToDelete[j].Name })
+	podsToDelete, err := r.snapshotPVCsBeforeDelete(cs, podsToDelete, pvcs)
+	if err != nil {
+		return false, err
+	}
+	return r.deletePods(cs, podsToDelete, pvcs)
+}
+
+// getPendingReplacedIDs returns at most limit instance-ids of the pods specified to replace that have no replacement pods yet.
+func getPendingReplacedIDs(replacedPods, replacementPods map[string]*v1.Pod, limit int) []string {
+	var ids []string
+	for id := range replacedPods {
+		if _, ok := replacementPods[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+	return ids
+}
+
+func excludeReplacementPods(pods []*v1.Pod, replacementPods map[string]*v1.Pod) []*v1.Pod {
+	if len(replacementPods) == 0 {
+		return pods
+	}
+	var otherPods []*v1.Pod
+	for _, pod := range pods {
+		if !isReplacementPod(pod, replacementPods) {
+			otherPods = append(otherPods, pod)
+		}
+	}
+	return otherPods
+}
+
+func splitInPlaceUpdateSurgePods(pods []*v1.Pod) (surgePods, otherPods []*v1.Pod) {
+	for _, pod := range pods {
+		if isInPlaceUpdateSurgePod(pod) {
+			surgePods = append(surgePods, pod)
+		} else {
+			otherPods = append(otherPods, pod)
+		}
+	}
+	return
+}
+
 func getPlannedDeletedPods(cs *appsv1alpha1.CloneSet, pods []*v1.Pod) ([]*v1.Pod, []*v1.Pod, int) {
 	var podsSpecifiedToDelete []*v1.Pod
 	var podsInPreDelete []*v1.Pod
@@ -325,10 +473,11 @@ func getPlannedDeletedPods(cs *appsv1alpha1.CloneSet, pods []*v1.Pod) ([]*v1.Pod
 }
 
 // Get available IDs, if the a PVC exists but the corresponding pod does not exist, then reusing the ID, i.e., reuse the pvc.
+// The IDs of snapshots are also available, so that the pvcs can be restored from the snapshots.
 // If there is not enough existing available IDs, then generate ID using rand utility.
 // More details: if template changes more than container image, controller will delete pod during update, and
 // it will keep the pvc to reuse.
-func getOrGenAvailableIDs(num int, pods []*v1.Pod, pvcs []*v1.PersistentVolumeClaim) sets.String {
+func getOrGenAvailableIDs(num int, pods []*v1.Pod, pvcs []*v1.PersistentVolumeClaim, snapshotIDs sets.String) sets.String {
 	existingIDs := sets.NewString()
 	availableIDs := sets.NewString()
 	for _, pvc := range pvcs {
@@ -337,6 +486,10 @@ func getOrGenAvailableIDs(num int, pods []*v1.Pod, pvcs []*v1.PersistentVolumeCl
 			availableIDs.Insert(id)
 		}
 	}
+	for id := range snapshotIDs {
+		existingIDs.Insert(id)
+		availableIDs.Insert(id)
+	}
 
 	for _, pod := range pods {
 		if id := pod.Labels[appsv1alpha1.CloneSetInstanceID]; len(id) > 0 {
@@ -378,13 +531,25 @@ func (r *realControl) choosePodsToDelete(cs *appsv1alpha1.CloneSet, totalDiff in
 			} else {
 				ranker = clonesetutils.NewSameNodeRanker(pods)
 			}
-			sort.Sort(clonesetutils.ActivePodsWithRanks{
+			var victimRanker *clonesetutils.WeightedVictimRanker
+			sorter := clonesetutils.ActivePodsWithRanks{
 				Pods:   pods,
 				Ranker: ranker,
 				AvailableFunc: func(pod *v1.Pod) bool {
 					return IsPodAvailable(coreControl, pod, cs.Spec.MinReadySeconds)
 				},
-			})
+			}
+			if policy := cs.Spec.ScaleStrategy.ScaleDownPolicy; policy != nil && len(policy.Scorers) > 0 {
+				victimRanker = clonesetutils.NewWeightedVictimRanker(pods, policy, r.Client)
+				sorter.VictimRanker = victimRanker
+			}
+			sort.Sort(sorter)
+			if victimRanker != nil {
+				for _, pod := range pods[:diff] {
+					r.recorder.Eventf(cs, v1.EventTypeNormal, "ScaleDownVictimScored",
+						"Chose pod %s to delete for scaling down with score %s", pod.Name, victimRanker.Explain(pod))
+				}
+			}
 		} else if diff > len(pods) {
 			klog.InfoS("Diff > len(pods) in choosePodsToDelete func which is not expected")
 			return pods
@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -29,6 +31,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -46,6 +49,7 @@ import (
 	clonesetutils "github.com/openkruise/kruise/pkg/controller/cloneset/utils"
 	"github.com/openkruise/kruise/pkg/util"
 	"github.com/openkruise/kruise/pkg/util/expectations"
+	utilpodreadiness "github.com/openkruise/kruise/pkg/util/podreadiness"
 )
 
 var (
@@ -76,6 +80,8 @@ func TestCreatePods(t *testing.T) {
 	created, err := ctrl.createPods(
 		3,
 		1,
+		0,
+		nil,
 		currentCS,
 		updateCS,
 		currentRevision,
@@ -364,6 +370,27 @@ func TestCreatePods(t *testing.T) {
 	}
 }
 
+func TestCreateStandbyPods(t *testing.T) {
+	cs := clonesettest.NewCloneSet(1)
+	cs.Name = "standby"
+	cs.Spec.Template.Labels[appsv1alpha1.CloneSetStandbyPodLabelKey] = "true"
+	defer clonesetutils.ScaleExpectations.DeleteExpectations("default/standby")
+
+	ctrl := newFakeControl()
+	if _, err := ctrl.createPods(1, 0, 0, nil, cs, cs, "revision_abc", "revision_abc", []string{"id1"}, sets.NewString()); err != nil {
+		t.Fatalf("got unexpected error: %v", err)
+	}
+
+	pod := &v1.Pod{}
+	if err := ctrl.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "standby-id1"}, pod); err != nil {
+		t.Fatalf("failed to get pod: %v", err)
+	}
+	if condition := utilpodreadiness.GetReadinessCondition(pod); condition == nil || condition.Status != v1.ConditionFalse ||
+		!utilpodreadiness.HasNotReadyKey(pod, clonesetutils.StandbyNotReadyMessage) {
+		t.Fatalf("expected standby pod initialized not ready, got %v", condition)
+	}
+}
+
 func TestDeletePods(t *testing.T) {
 	cs := &appsv1alpha1.CloneSet{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"}}
 	podsToDelete := []*v1.Pod{
@@ -533,7 +560,7 @@ func TestGetOrGenAvailableIDs(t *testing.T) {
 		},
 	}
 
-	gotIDs := getOrGenAvailableIDs(2, pods, pvcs)
+	gotIDs := getOrGenAvailableIDs(2, pods, pvcs, nil)
 	if gotIDs.Len() != 2 {
 		t.Fatalf("expected got 2")
 	}
@@ -546,6 +573,12 @@ func TestGetOrGenAvailableIDs(t *testing.T) {
 	if id, _ := gotIDs.PopAny(); len(id) != 5 {
 		t.Fatalf("expected got random id, but actually %v", id)
 	}
+
+	// the ids of snapshots are available, but the one used by pod is not
+	gotIDs = getOrGenAvailableIDs(2, pods, pvcs, sets.NewString("a", "d"))
+	if !gotIDs.Equal(sets.NewString("c", "d")) {
+		t.Fatalf("expected got [c d], but actually %v", gotIDs.List())
+	}
 }
 
 func TestScale(t *testing.T) {
@@ -766,6 +799,96 @@ func TestScale(t *testing.T) {
 	}
 }
 
+func TestChoosePodsToDeleteWithScaleDownPolicy(t *testing.T) {
+	cs := &appsv1alpha1.CloneSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "sample"},
+		Spec: appsv1alpha1.CloneSetSpec{
+			Replicas: utilpointer.Int32(2),
+			ScaleStrategy: appsv1alpha1.CloneSetScaleStrategy{
+				ScaleDownPolicy: &appsv1alpha1.CloneSetScaleDownPolicy{
+					Scorers: []appsv1alpha1.CloneSetScaleDownScorer{{Type: appsv1alpha1.DeletionCostScaleDownScorer, Weight: 10}},
+				},
+			},
+		},
+	}
+	pods := generatePods(&v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "sample"},
+		Status:     v1.PodStatus{Phase: v1.PodRunning},
+	}, 3)
+	for i, pod := range pods {
+		pod.UID = types.UID(pod.Name)
+		pod.Annotations = map[string]string{clonesetutils.PodDeletionCost: strconv.Itoa(10 - i)}
+	}
+
+	recorder := record.NewFakeRecorder(10)
+	rControl := &realControl{Client: fake.NewClientBuilder().Build(), recorder: recorder}
+	podsToDelete := rControl.choosePodsToDelete(cs, 1, 0, nil, pods)
+	if len(podsToDelete) != 1 || podsToDelete[0].Name != "sample-2" {
+		t.Fatalf("expected to delete sample-2, got %v", util.GetPodNames(podsToDelete).List())
+	}
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, "ScaleDownVictimScored") || !strings.Contains(event, "sample-2") || !strings.Contains(event, "10.00") {
+			t.Fatalf("unexpected event %s", event)
+		}
+	default:
+		t.Fatalf("expected an event for the victim")
+	}
+}
+
+func TestScaleWithInPlaceUpdateSurge(t *testing.T) {
+	cs := &appsv1alpha1.CloneSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "sample"},
+		Spec: appsv1alpha1.CloneSetSpec{
+			Replicas: utilpointer.Int32(3),
+			UpdateStrategy: appsv1alpha1.CloneSetUpdateStrategy{
+				Type:               appsv1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType,
+				MaxUnavailable:     &intstr.IntOrString{Type: intstr.Int, IntVal: 0},
+				MaxSurge:           &intstr.IntOrString{Type: intstr.Int, IntVal: 1},
+				InPlaceUpdateSurge: true,
+			},
+		},
+	}
+	pods := generatePods(&v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "sample",
+			Labels:    map[string]string{apps.ControllerRevisionHashLabelKey: "sample-b976d4544"},
+		},
+		Status: v1.PodStatus{
+			Phase:      v1.PodRunning,
+			Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}},
+		},
+	}, 4)
+	// the surge pod is not the last one to be sorted for deletion by default
+	pods[1].Labels[appsv1alpha1.CloneSetInPlaceUpdateSurgePodLabelKey] = "true"
+
+	fClient := fake.NewClientBuilder().WithScheme(kscheme).Build()
+	for _, pod := range pods {
+		if err := fClient.Create(context.TODO(), pod); err != nil {
+			t.Fatalf("failed to create pod: %v", err)
+		}
+	}
+	rControl := &realControl{Client: fClient, recorder: record.NewFakeRecorder(10)}
+	modified, err := rControl.Scale(cs, cs, "sample-b976d4544", "sample-b976d4544", pods, nil)
+	if err != nil || !modified {
+		t.Fatalf("expected modified, got %v, %v", modified, err)
+	}
+
+	podList := &v1.PodList{}
+	if err := fClient.List(context.TODO(), podList); err != nil {
+		t.Fatalf("failed to list pods: %v", err)
+	}
+	for _, pod := range podList.Items {
+		if pod.Name == pods[1].Name {
+			t.Fatalf("expected surge pod %s to be deleted", pod.Name)
+		}
+	}
+	if len(podList.Items) != 3 {
+		t.Fatalf("expected 3 pods left, got %d", len(podList.Items))
+	}
+}
+
 func generatePods(base *v1.Pod, replicas int) []*v1.Pod {
 	objs := make([]*v1.Pod, 0, replicas)
 	for i := 0; i < replicas; i++ {
@@ -775,3 +898,89 @@ func generatePods(base *v1.Pod, replicas int) []*v1.Pod {
 	}
 	return objs
 }
+
+func TestScaleWithSpecifiedReplace(t *testing.T) {
+	cs := &appsv1alpha1.CloneSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "sample"},
+		Spec: appsv1alpha1.CloneSetSpec{
+			Replicas: utilpointer.Int32(3),
+			UpdateStrategy: appsv1alpha1.CloneSetUpdateStrategy{
+				MaxUnavailable: &intstr.IntOrString{Type: intstr.Int, IntVal: 0},
+				MaxSurge:       &intstr.IntOrString{Type: intstr.Int, IntVal: 1},
+			},
+		},
+	}
+	pods := generatePods(&v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "sample",
+			Labels:    map[string]string{apps.ControllerRevisionHashLabelKey: "sample-b976d4544"},
+		},
+		Status: v1.PodStatus{
+			Phase:      v1.PodRunning,
+			Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}},
+		},
+	}, 3)
+	for i, pod := range pods {
+		pod.Labels[appsv1alpha1.CloneSetInstanceID] = fmt.Sprintf("id-%d", i)
+	}
+	pods[1].Labels[appsv1alpha1.SpecifiedReplaceKey] = "true"
+
+	fClient := fake.NewClientBuilder().WithScheme(kscheme).Build()
+	for _, pod := range pods {
+		if err := fClient.Create(context.TODO(), pod); err != nil {
+			t.Fatalf("failed to create pod: %v", err)
+		}
+	}
+	rControl := &realControl{Client: fClient, recorder: record.NewFakeRecorder(10)}
+
+	// the replacement pod should be created first
+	modified, err := rControl.Scale(cs, cs, "sample-b976d4544", "sample-b976d4544", pods, nil)
+	if err != nil || !modified {
+		t.Fatalf("expected modified, got %v, %v", modified, err)
+	}
+	podList := &v1.PodList{}
+	if err := fClient.List(context.TODO(), podList); err != nil {
+		t.Fatalf("failed to list pods: %v", err)
+	}
+	if len(podList.Items) != 4 {
+		t.Fatalf("expected 4 pods, got %d", len(podList.Items))
+	}
+	var replacement *v1.Pod
+	for i := range podList.Items {
+		if podList.Items[i].Labels[appsv1alpha1.CloneSetReplacementForLabelKey] == "id-1" {
+			replacement = &podList.Items[i]
+		}
+	}
+	if replacement == nil {
+		t.Fatalf("expected replacement pod for id-1, got %v", podList.Items)
+	}
+
+	// the replaced pod should not be deleted until the replacement pod is available
+	pods = append(pods, replacement)
+	modified, err = rControl.Scale(cs, cs, "sample-b976d4544", "sample-b976d4544", pods, nil)
+	if err != nil || modified {
+		t.Fatalf("expected not modified, got %v, %v", modified, err)
+	}
+
+	replacement.Status = v1.PodStatus{
+		Phase:      v1.PodRunning,
+		Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}},
+	}
+	replacement.Labels[appspub.LifecycleStateKey] = string(appspub.LifecycleStateNormal)
+	modified, err = rControl.Scale(cs, cs, "sample-b976d4544", "sample-b976d4544", pods, nil)
+	if err != nil || !modified {
+		t.Fatalf("expected modified, got %v, %v", modified, err)
+	}
+	if err := fClient.List(context.TODO(), podList); err != nil {
+		t.Fatalf("failed to list pods: %v", err)
+	}
+	if len(podList.Items) != 3 {
+		t.Fatalf("expected 3 pods left, got %d", len(podList.Items))
+	}
+	for _, pod := range podList.Items {
+		if pod.Name == pods[1].Name {
+			t.Fatalf("expected replaced pod %s to be deleted", pod.Name)
+		}
+	}
+}
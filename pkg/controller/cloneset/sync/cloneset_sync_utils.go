@@ -75,6 +75,17 @@ type expectationDiffs struct {
 	// it indicates the above number of old revision Pods
 	useSurgeOldRevision int
 
+	// scaleUpNumInPlaceSurge is part of the scaleUpNum number
+	// it indicates the number of Pods that should be created as in-place update surge Pods
+	scaleUpNumInPlaceSurge int
+	// scaleDownNumInPlaceSurge is part of the scaleDownNum number
+	// it indicates the number of in-place update surge Pods that should be deleted
+	scaleDownNumInPlaceSurge int
+
+	// scaleUpNumReplace is part of the scaleUpNum number
+	// it indicates the number of Pods that should be created to replace the Pods specified to replace
+	scaleUpNumReplace int
+
 	// updateNum is the diff number that should update
 	// '0' means no need to update
 	// positive number means need to update more Pods to updateRevision
@@ -122,8 +133,15 @@ func calculateDiffsWithExpectation(cs *appsv1alpha1.CloneSet, pods []*v1.Pod, cu
 	scaleMaxUnavailable, _ = intstrutil.GetValueFromIntOrPercent(
 		intstrutil.ValueOrDefault(cs.Spec.ScaleStrategy.MaxUnavailable, intstrutil.FromInt(math.MaxInt32)), replicas, true)
 
+	inPlaceSurge := isInPlaceUpdateSurge(cs)
+	// surge should not be used for updating outside the update windows
+	inUpdateWindow, _ := clonesetutils.IsInUpdateWindows(cs.Spec.UpdateStrategy.UpdateWindows, timer.Now())
+	replacedPods, replacementPods := getReplacePods(cs, pods)
+
 	var newRevisionCount, newRevisionActiveCount, oldRevisionCount, oldRevisionActiveCount int
 	var unavailableNewRevisionCount, unavailableOldRevisionCount int
+	var inPlaceSurgeCount, unavailableInPlaceSurgeCount int
+	var replacementCount int
 	var toDeleteNewRevisionCount, toDeleteOldRevisionCount, preDeletingNewRevisionCount, preDeletingOldRevisionCount int
 	defer func() {
 		if res.isEmpty() {
@@ -135,6 +153,8 @@ func calculateDiffsWithExpectation(cs *appsv1alpha1.CloneSet, pods []*v1.Pod, cu
 			"unavailableNewRevisionCount", unavailableNewRevisionCount, "unavailableOldRevisionCount", unavailableOldRevisionCount,
 			"preDeletingNewRevisionCount", preDeletingNewRevisionCount, "preDeletingOldRevisionCount", preDeletingOldRevisionCount,
 			"toDeleteNewRevisionCount", toDeleteNewRevisionCount, "toDeleteOldRevisionCount", toDeleteOldRevisionCount,
+			"inPlaceSurge", inPlaceSurge, "inPlaceSurgeCount", inPlaceSurgeCount, "unavailableInPlaceSurgeCount", unavailableInPlaceSurgeCount,
+			"replacedCount", len(replacedPods), "replacementCount", replacementCount, "inUpdateWindow", inUpdateWindow,
 			"enabledPreparingUpdateAsUpdate", utilfeature.DefaultFeatureGate.Enabled(features.PreparingUpdateAsUpdate), "useDefaultIsPodUpdate", isPodUpdate == nil,
 			"result", res)
 	}()
@@ -151,6 +171,28 @@ func calculateDiffsWithExpectation(cs *appsv1alpha1.CloneSet, pods []*v1.Pod, cu
 	}
 
 	for _, p := range pods {
+		// replacement pods do not serve as replicas until the replaced pods have been deleted
+		if isReplacementPod(p, replacementPods) {
+			replacementCount++
+			continue
+		}
+
+		// in-place update surge pods are temporary, so they are excluded from the revision counts
+		if inPlaceSurge && isInPlaceUpdateSurgePod(p) {
+			switch {
+			case lifecycle.GetPodLifecycleState(p) == appspub.LifecycleStatePreparingDelete:
+				preDeletingNewRevisionCount++
+			case isSpecifiedDelete(cs, p):
+				toDeleteNewRevisionCount++
+			default:
+				inPlaceSurgeCount++
+				if !IsPodAvailable(coreControl, p, cs.Spec.MinReadySeconds) {
+					unavailableInPlaceSurgeCount++
+				}
+			}
+			continue
+		}
+
 		if isPodUpdate(p, updateRevision) {
 
 			newRevisionCount++
@@ -188,7 +230,7 @@ func calculateDiffsWithExpectation(cs *appsv1alpha1.CloneSet, pods []*v1.Pod, cu
 
 	updateOldDiff := oldRevisionActiveCount - partition
 	updateNewDiff := newRevisionActiveCount - (replicas - partition)
-	totalUnavailable := preDeletingNewRevisionCount + preDeletingOldRevisionCount + unavailableNewRevisionCount + unavailableOldRevisionCount
+	totalUnavailable := preDeletingNewRevisionCount + preDeletingOldRevisionCount + unavailableNewRevisionCount + unavailableOldRevisionCount + unavailableInPlaceSurgeCount
 	// If the currentRevision and updateRevision are consistent, Pods can only update to this revision
 	// If the CloneSetPartitionRollback is not enabled, Pods can only update to the new revision
 	if updateRevision == currentRevision || !utilfeature.DefaultFeatureGate.Enabled(features.CloneSetPartitionRollback) {
@@ -197,7 +239,14 @@ func calculateDiffsWithExpectation(cs *appsv1alpha1.CloneSet, pods []*v1.Pod, cu
 	}
 
 	// calculate the number of surge to use
-	if maxSurge > 0 {
+	if maxSurge > 0 && inPlaceSurge {
+
+		// Keep surge Pods until the old revision Pods have been updated in-place and become available
+		if updateRevision != currentRevision && inUpdateWindow {
+			res.useSurge = integer.IntMin(maxSurge, integer.IntMax(updateOldDiff, 0)+unavailableNewRevisionCount)
+		}
+
+	} else if maxSurge > 0 {
 
 		// Use surge for maxUnavailable not satisfied before scaling
 		var scaleSurge, scaleOldRevisionSurge int
@@ -210,7 +259,7 @@ func calculateDiffsWithExpectation(cs *appsv1alpha1.CloneSet, pods []*v1.Pod, cu
 
 		// Use surge for old and new revision updating
 		var updateSurge, updateOldRevisionSurge int
-		if util.IsIntPlusAndMinus(updateOldDiff, updateNewDiff) {
+		if inUpdateWindow && util.IsIntPlusAndMinus(updateOldDiff, updateNewDiff) {
 			if util.IntAbs(updateOldDiff) <= util.IntAbs(updateNewDiff) {
 				updateSurge = util.IntAbs(updateOldDiff)
 				if updateOldDiff < 0 {
@@ -241,8 +290,21 @@ func calculateDiffsWithExpectation(cs *appsv1alpha1.CloneSet, pods []*v1.Pod, cu
 		currentTotalCount = currentTotalCount - preDeletingOldRevisionCount - preDeletingNewRevisionCount
 		currentTotalOldCount = currentTotalOldCount - preDeletingOldRevisionCount
 	}
-	expectedTotalCount := replicas + res.useSurge
+	// each pod specified to replace temporarily expects one more pod as its replacement,
+	// which shares the maxSurge left by scaling and updating, and the existing replacement pods should be kept.
+	// Without maxSurge, such as InPlaceOnly, the replacements are limited by maxUnavailable instead,
+	// just like the replaced pods were deleted and recreated.
+	replaceLimit := maxSurge - res.useSurge
+	if maxSurge == 0 {
+		replaceLimit = integer.IntMax(maxUnavailable-totalUnavailable, 0)
+	}
+	replaceSurge := integer.IntMax(integer.IntMin(len(replacedPods), replaceLimit), len(replacementPods))
+	expectedTotalCount := replicas + res.useSurge + replaceSurge
 	expectedTotalOldCount := partition + res.useSurgeOldRevision
+	// old revision Pods should not be preferred to scale in outside the update windows, which makes the update go on
+	if !inUpdateWindow {
+		expectedTotalOldCount = integer.IntMax(expectedTotalOldCount, integer.IntMin(currentTotalOldCount-toDeleteOldRevisionCount, expectedTotalCount))
+	}
 
 	// scale up
 	if num := expectedTotalCount - currentTotalCount; num > 0 {
@@ -250,6 +312,10 @@ func calculateDiffsWithExpectation(cs *appsv1alpha1.CloneSet, pods []*v1.Pod, cu
 		res.scaleUpNumOldRevision = integer.IntMax(expectedTotalOldCount-currentTotalOldCount, 0)
 
 		res.scaleUpLimit = integer.IntMin(res.scaleUpNum, integer.IntMax(scaleMaxUnavailable-totalUnavailable, 0))
+		if inPlaceSurge {
+			res.scaleUpNumInPlaceSurge = integer.IntMin(integer.IntMax(res.useSurge-inPlaceSurgeCount, 0), res.scaleUpNum)
+		}
+		res.scaleUpNumReplace = integer.IntMin(replaceSurge-len(replacementPods), res.scaleUpNum)
 	}
 
 	// scale down
@@ -257,9 +323,12 @@ func calculateDiffsWithExpectation(cs *appsv1alpha1.CloneSet, pods []*v1.Pod, cu
 	if num := currentTotalCount - toDeleteOldRevisionCount - toDeleteNewRevisionCount - expectedTotalCount; num > 0 {
 		res.scaleDownNum = num
 		res.scaleDownNumOldRevision = integer.IntMax(currentTotalOldCount-toDeleteOldRevisionCount-expectedTotalOldCount, 0)
+		if inPlaceSurge {
+			res.scaleDownNumInPlaceSurge = integer.IntMin(integer.IntMax(inPlaceSurgeCount-res.useSurge, 0), res.scaleDownNum)
+		}
 	}
 	if toDeleteNewRevisionCount > 0 || toDeleteOldRevisionCount > 0 || res.scaleDownNum > 0 {
-		res.deleteReadyLimit = integer.IntMax(maxUnavailable+(len(pods)-replicas)-totalUnavailable, 0)
+		res.deleteReadyLimit = integer.IntMax(maxUnavailable+(len(pods)-replacementCount-replicas)-totalUnavailable, 0)
 	}
 
 	// The consistency between scale and update will be guaranteed by syncCloneSet and expectations
@@ -269,12 +338,55 @@ func calculateDiffsWithExpectation(cs *appsv1alpha1.CloneSet, pods []*v1.Pod, cu
 		res.updateNum = 0 - updateNewDiff
 	}
 	if res.updateNum != 0 {
-		res.updateMaxUnavailable = maxUnavailable + len(pods) - replicas
+		res.updateMaxUnavailable = maxUnavailable + len(pods) - replacementCount - replicas
 	}
 
 	return
 }
 
+func isInPlaceUpdateSurge(cs *appsv1alpha1.CloneSet) bool {
+	return cs.Spec.UpdateStrategy.InPlaceUpdateSurge && cs.Spec.UpdateStrategy.Type != appsv1alpha1.RecreateCloneSetUpdateStrategyType
+}
+
+func isInPlaceUpdateSurgePod(pod *v1.Pod) bool {
+	return pod.Labels[appsv1alpha1.CloneSetInPlaceUpdateSurgePodLabelKey] == "true"
+}
+
+// getReplacePods returns the Pods specified to replace and their replacement Pods, both keyed by the instance-id of the replaced Pod.
+// The Pods that have been specified to delete or in PreparingDelete state are no longer regarded as to be replaced,
+// so their replacement Pods will serve as normal Pods.
+func getReplacePods(cs *appsv1alpha1.CloneSet, pods []*v1.Pod) (replacedPods, replacementPods map[string]*v1.Pod) {
+	replacedPods = make(map[string]*v1.Pod)
+	replacementPods = make(map[string]*v1.Pod)
+	for _, pod := range pods {
+		id := pod.Labels[appsv1alpha1.CloneSetInstanceID]
+		if id == "" || !specifieddelete.IsSpecifiedReplace(pod) || isSpecifiedDelete(cs, pod) ||
+			lifecycle.GetPodLifecycleState(pod) == appspub.LifecycleStatePreparingDelete {
+			continue
+		}
+		replacedPods[id] = pod
+	}
+	for _, pod := range pods {
+		id := pod.Labels[appsv1alpha1.CloneSetReplacementForLabelKey]
+		if _, ok := replacedPods[id]; !ok {
+			continue
+		}
+		if _, ok := replacementPods[id]; ok || replacedPods[pod.Labels[appsv1alpha1.CloneSetInstanceID]] != nil {
+			continue
+		}
+		if isSpecifiedDelete(cs, pod) || lifecycle.GetPodLifecycleState(pod) == appspub.LifecycleStatePreparingDelete {
+			continue
+		}
+		replacementPods[id] = pod
+	}
+	return
+}
+
+func isReplacementPod(pod *v1.Pod, replacementPods map[string]*v1.Pod) bool {
+	replacement, ok := replacementPods[pod.Labels[appsv1alpha1.CloneSetReplacementForLabelKey]]
+	return ok && replacement == pod
+}
+
 func isSpecifiedDelete(cs *appsv1alpha1.CloneSet, pod *v1.Pod) bool {
 	if specifieddelete.IsSpecifiedDelete(pod) {
 		return true
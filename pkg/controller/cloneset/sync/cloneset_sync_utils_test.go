@@ -20,11 +20,14 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	apps "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/clock"
+	testingclock "k8s.io/utils/clock/testing"
 
 	appspub "github.com/openkruise/kruise/apis/apps/pub"
 	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
@@ -983,9 +986,190 @@ func TestCalculateDiffsWithExpectation(t *testing.T) {
 			},
 			expectResult: expectationDiffs{scaleDownNum: 3, scaleDownNumOldRevision: 3},
 		},
+		{
+			name: "[in-place update surge] create surge pod before update",
+			set:  setInPlaceUpdateSurge(createTestCloneSet(3, intstr.FromInt(0), intstr.FromInt(0), intstr.FromInt(1))),
+			pods: []*v1.Pod{
+				createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false),
+				createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false),
+				createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false),
+			},
+			expectResult: expectationDiffs{scaleUpNum: 1, scaleUpLimit: 1, useSurge: 1, scaleUpNumInPlaceSurge: 1, updateNum: 3},
+		},
+		{
+			name: "[in-place update surge] wait for surge pod ready",
+			set:  setInPlaceUpdateSurge(createTestCloneSet(3, intstr.FromInt(0), intstr.FromInt(0), intstr.FromInt(1))),
+			pods: []*v1.Pod{
+				createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false),
+				createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false),
+				createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false),
+				setInPlaceUpdateSurgePod(createTestPod(newRevision, appspub.LifecycleStateNormal, false, false)),
+			},
+			expectResult: expectationDiffs{useSurge: 1, updateNum: 3, updateMaxUnavailable: 1},
+		},
+		{
+			name: "[in-place update surge] update pods in-place with surge pod",
+			set:  setInPlaceUpdateSurge(createTestCloneSet(3, intstr.FromInt(0), intstr.FromInt(0), intstr.FromInt(1))),
+			pods: []*v1.Pod{
+				createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false),
+				createTestPod(newRevision, appspub.LifecycleStateNormal, true, false),
+				createTestPod(newRevision, appspub.LifecycleStateNormal, true, false),
+				setInPlaceUpdateSurgePod(createTestPod(newRevision, appspub.LifecycleStateNormal, true, false)),
+			},
+			expectResult: expectationDiffs{useSurge: 1, updateNum: 1, updateMaxUnavailable: 1},
+		},
+		{
+			name: "[in-place update surge] keep surge pod until updated pods ready",
+			set:  setInPlaceUpdateSurge(createTestCloneSet(3, intstr.FromInt(0), intstr.FromInt(0), intstr.FromInt(1))),
+			pods: []*v1.Pod{
+				createTestPod(newRevision, appspub.LifecycleStateNormal, false, false),
+				createTestPod(newRevision, appspub.LifecycleStateNormal, true, false),
+				createTestPod(newRevision, appspub.LifecycleStateNormal, true, false),
+				setInPlaceUpdateSurgePod(createTestPod(newRevision, appspub.LifecycleStateNormal, true, false)),
+			},
+			expectResult: expectationDiffs{useSurge: 1},
+		},
+		{
+			name: "[in-place update surge] delete surge pod after update",
+			set:  setInPlaceUpdateSurge(createTestCloneSet(3, intstr.FromInt(0), intstr.FromInt(0), intstr.FromInt(1))),
+			pods: []*v1.Pod{
+				createTestPod(newRevision, appspub.LifecycleStateNormal, true, false),
+				createTestPod(newRevision, appspub.LifecycleStateNormal, true, false),
+				createTestPod(newRevision, appspub.LifecycleStateNormal, true, false),
+				setInPlaceUpdateSurgePod(createTestPod(newRevision, appspub.LifecycleStateNormal, true, false)),
+			},
+			expectResult: expectationDiffs{scaleDownNum: 1, scaleDownNumInPlaceSurge: 1, deleteReadyLimit: 1},
+		},
+		{
+			name: "[specified replace] create replacement pod",
+			set:  createTestCloneSet(3, intstr.FromInt(0), intstr.FromInt(1), intstr.FromInt(1)),
+			pods: []*v1.Pod{
+				createTestPod(newRevision, appspub.LifecycleStateNormal, true, false),
+				setReplacedPod(createTestPod(newRevision, appspub.LifecycleStateNormal, true, false), "id-1"),
+				createTestPod(newRevision, appspub.LifecycleStateNormal, true, false),
+			},
+			expectResult: expectationDiffs{scaleUpNum: 1, scaleUpLimit: 1, scaleUpNumReplace: 1},
+		},
+		{
+			name: "[specified replace] replacement pod limited by maxUnavailable without maxSurge",
+			set:  createTestCloneSet(3, intstr.FromInt(0), intstr.FromInt(1), intstr.FromInt(0)),
+			pods: []*v1.Pod{
+				setReplacedPod(createTestPod(newRevision, appspub.LifecycleStateNormal, true, false), "id-0"),
+				setReplacedPod(createTestPod(newRevision, appspub.LifecycleStateNormal, true, false), "id-1"),
+				createTestPod(newRevision, appspub.LifecycleStateNormal, true, false),
+			},
+			expectResult: expectationDiffs{scaleUpNum: 1, scaleUpLimit: 1, scaleUpNumReplace: 1},
+		},
+		{
+			name: "[specified replace] no replacement pod without maxSurge and maxUnavailable left",
+			set:  createTestCloneSet(3, intstr.FromInt(0), intstr.FromInt(1), intstr.FromInt(0)),
+			pods: []*v1.Pod{
+				createTestPod(newRevision, appspub.LifecycleStateNormal, false, false),
+				setReplacedPod(createTestPod(newRevision, appspub.LifecycleStateNormal, true, false), "id-1"),
+				createTestPod(newRevision, appspub.LifecycleStateNormal, true, false),
+			},
+			expectResult: expectationDiffs{},
+		},
+		{
+			name: "[specified replace] replacement pods limited by maxSurge",
+			set:  createTestCloneSet(3, intstr.FromInt(0), intstr.FromInt(1), intstr.FromInt(1)),
+			pods: []*v1.Pod{
+				setReplacedPod(createTestPod(newRevision, appspub.LifecycleStateNormal, true, false), "id-0"),
+				setReplacedPod(createTestPod(newRevision, appspub.LifecycleStateNormal, true, false), "id-1"),
+				createTestPod(newRevision, appspub.LifecycleStateNormal, true, false),
+			},
+			expectResult: expectationDiffs{scaleUpNum: 1, scaleUpLimit: 1, scaleUpNumReplace: 1},
+		},
+		{
+			name: "[specified replace] maxSurge has been used for update",
+			set:  createTestCloneSet(3, intstr.FromInt(0), intstr.FromInt(1), intstr.FromInt(1)),
+			pods: []*v1.Pod{
+				createTestPod(newRevision, appspub.LifecycleStateNormal, true, false),
+				setReplacedPod(createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false), "id-1"),
+				createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false),
+				createTestPod(newRevision, appspub.LifecycleStateNormal, false, false),
+			},
+			expectResult: expectationDiffs{useSurge: 1, updateNum: 1, updateMaxUnavailable: 2},
+		},
+		{
+			name: "[specified replace] wait for replacement pod ready",
+			set:  createTestCloneSet(3, intstr.FromInt(0), intstr.FromInt(1), intstr.FromInt(1)),
+			pods: []*v1.Pod{
+				createTestPod(newRevision, appspub.LifecycleStateNormal, true, false),
+				setReplacedPod(createTestPod(newRevision, appspub.LifecycleStateNormal, true, false), "id-1"),
+				createTestPod(newRevision, appspub.LifecycleStateNormal, true, false),
+				setReplacementPod(createTestPod(newRevision, appspub.LifecycleStateNormal, false, false), "id-1"),
+			},
+			expectResult: expectationDiffs{},
+		},
+		{
+			name: "[specified replace] replacement pod does not affect update",
+			set:  createTestCloneSet(3, intstr.FromInt(0), intstr.FromInt(1), intstr.FromInt(0)),
+			pods: []*v1.Pod{
+				createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false),
+				setReplacedPod(createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false), "id-1"),
+				createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false),
+				setReplacementPod(createTestPod(newRevision, appspub.LifecycleStateNormal, true, false), "id-1"),
+			},
+			expectResult: expectationDiffs{updateNum: 3, updateMaxUnavailable: 1},
+		},
+		{
+			name: "[specified replace] replaced pod in preparing delete",
+			set:  createTestCloneSet(3, intstr.FromInt(0), intstr.FromInt(1), intstr.FromInt(0)),
+			pods: []*v1.Pod{
+				createTestPod(newRevision, appspub.LifecycleStateNormal, true, false),
+				setReplacedPod(createTestPod(newRevision, appspub.LifecycleStatePreparingDelete, true, false), "id-1"),
+				createTestPod(newRevision, appspub.LifecycleStateNormal, true, false),
+				setReplacementPod(createTestPod(newRevision, appspub.LifecycleStateNormal, true, false), "id-1"),
+			},
+			expectResult: expectationDiffs{scaleDownNum: 1, deleteReadyLimit: 1},
+		},
+		{
+			name: "[update windows] no surge for update outside update windows",
+			set:  setUpdateWindows(createTestCloneSet(3, intstr.FromInt(0), intstr.FromInt(1), intstr.FromInt(1)), "01:00", "02:00"),
+			pods: []*v1.Pod{
+				createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false),
+				createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false),
+				createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false),
+			},
+			expectResult: expectationDiffs{updateNum: 3, updateMaxUnavailable: 1},
+		},
+		{
+			name: "[update windows] use surge for update inside update windows",
+			set:  setUpdateWindows(createTestCloneSet(3, intstr.FromInt(0), intstr.FromInt(1), intstr.FromInt(1)), "11:00", "13:00"),
+			pods: []*v1.Pod{
+				createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false),
+				createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false),
+				createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false),
+			},
+			expectResult: expectationDiffs{scaleUpNum: 1, scaleUpLimit: 1, useSurge: 1, updateNum: 3, updateMaxUnavailable: 1},
+		},
+		{
+			name: "[update windows] scale in surge pods of new revision outside update windows",
+			set:  setUpdateWindows(createTestCloneSet(3, intstr.FromInt(0), intstr.FromInt(1), intstr.FromInt(1)), "01:00", "02:00"),
+			pods: []*v1.Pod{
+				createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false),
+				createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false),
+				createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false),
+				createTestPod(newRevision, appspub.LifecycleStateNormal, true, false),
+			},
+			expectResult: expectationDiffs{scaleDownNum: 1, deleteReadyLimit: 2, updateNum: 2, updateMaxUnavailable: 2},
+		},
+		{
+			name: "[update windows] no in-place update surge outside update windows",
+			set:  setUpdateWindows(setInPlaceUpdateSurge(createTestCloneSet(3, intstr.FromInt(0), intstr.FromInt(0), intstr.FromInt(1))), "01:00", "02:00"),
+			pods: []*v1.Pod{
+				createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false),
+				createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false),
+				createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false),
+			},
+			expectResult: expectationDiffs{updateNum: 3},
+		},
 	}
 
 	defer utilfeature.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.PreparingUpdateAsUpdate, true)()
+	defer func(c clock.Clock) { timer = c }(timer)
+	timer = testingclock.NewFakeClock(time.Date(2025, 7, 21, 12, 0, 0, 0, time.UTC))
 
 	for i := range cases {
 		t.Run(cases[i].name, func(t *testing.T) {
@@ -1025,6 +1209,11 @@ func createTestCloneSet(replicas int32, partition, maxUnavailable, maxSurge ints
 	}
 }
 
+func setUpdateWindows(cs *appsv1alpha1.CloneSet, start, end string) *appsv1alpha1.CloneSet {
+	cs.Spec.UpdateStrategy.UpdateWindows = []appsv1alpha1.CloneSetUpdateWindow{{Start: start, End: end}}
+	return cs
+}
+
 func setScaleStrategy(cs *appsv1alpha1.CloneSet, maxUnavailable intstr.IntOrString) *appsv1alpha1.CloneSet {
 	cs.Spec.ScaleStrategy = appsv1alpha1.CloneSetScaleStrategy{
 		MaxUnavailable: &maxUnavailable,
@@ -1046,6 +1235,28 @@ func createTestPod(revisionHash string, lifecycleState appspub.LifecycleStateTyp
 	return pod
 }
 
+func setInPlaceUpdateSurge(cs *appsv1alpha1.CloneSet) *appsv1alpha1.CloneSet {
+	cs.Spec.UpdateStrategy.Type = appsv1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType
+	cs.Spec.UpdateStrategy.InPlaceUpdateSurge = true
+	return cs
+}
+
+func setInPlaceUpdateSurgePod(pod *v1.Pod) *v1.Pod {
+	pod.Labels[appsv1alpha1.CloneSetInPlaceUpdateSurgePodLabelKey] = "true"
+	return pod
+}
+
+func setReplacedPod(pod *v1.Pod, id string) *v1.Pod {
+	pod.Labels[appsv1alpha1.CloneSetInstanceID] = id
+	pod.Labels[appsv1alpha1.SpecifiedReplaceKey] = "true"
+	return pod
+}
+
+func setReplacementPod(pod *v1.Pod, replacedID string) *v1.Pod {
+	pod.Labels[appsv1alpha1.CloneSetReplacementForLabelKey] = replacedID
+	return pod
+}
+
 func setUpdateStrategyPaused(cs *appsv1alpha1.CloneSet, paused bool) *appsv1alpha1.CloneSet {
 	cs.Spec.UpdateStrategy = appsv1alpha1.CloneSetUpdateStrategy{
 		Partition:      cs.Spec.UpdateStrategy.Partition,
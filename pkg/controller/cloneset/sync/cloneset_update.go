@@ -88,17 +88,33 @@ func (c *realControl) Update(cs *appsv1alpha1.CloneSet,
 	if diffRes.updateNum == 0 {
 		return nil
 	}
+	if inWindow, wait := clonesetutils.IsInUpdateWindows(cs.Spec.UpdateStrategy.UpdateWindows, timer.Now()); !inWindow {
+		klog.V(3).InfoS("CloneSet skipped updating pods outside update windows", "cloneSet", klog.KObj(cs), "nextWindowAfter", wait)
+		if wait > 0 {
+			clonesetutils.DurationStore.Push(key, wait)
+		}
+		return nil
+	}
 
 	// 3. find all matched pods can update
 	targetRevision := updateRevision
 	if diffRes.updateNum < 0 {
 		targetRevision = currentRevision
 	}
+	_, replacementPods := getReplacePods(cs, pods)
 	var waitUpdateIndexes []int
 	for i, pod := range pods {
 		if coreControl.IsPodUpdatePaused(pod) {
 			continue
 		}
+		// replacement pods do not serve as replicas, so they will not be updated until the replaced pods deleted
+		if isReplacementPod(pod, replacementPods) {
+			continue
+		}
+		// in-place update surge pods are temporary, they will be deleted instead of updated
+		if isInPlaceUpdateSurge(cs) && isInPlaceUpdateSurgePod(pod) {
+			continue
+		}
 
 		var waitUpdate, canUpdate bool
 		if diffRes.updateNum > 0 {
@@ -143,9 +159,8 @@ func (c *realControl) Update(cs *appsv1alpha1.CloneSet,
 			allowed, _, err := pubcontrol.PodUnavailableBudgetValidatePod(pod, policyv1alpha1.PubUpdateOperation, "kruise-manager", false)
 			if err != nil {
 				return err
-				// pub check does not pass, try again in seconds
+				// pub check does not pass, the pub event handler enqueues the cloneset once the quota is released
 			} else if !allowed {
-				clonesetutils.DurationStore.Push(key, time.Second)
 				return nil
 			}
 		}
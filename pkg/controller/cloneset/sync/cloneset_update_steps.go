@@ -0,0 +1,168 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	clonesetcore "github.com/openkruise/kruise/pkg/controller/cloneset/core"
+	clonesetutils "github.com/openkruise/kruise/pkg/controller/cloneset/utils"
+	"github.com/openkruise/kruise/pkg/util"
+)
+
+// timer is the clock used to sync CloneSet, such as the update steps and update windows.
+var timer clock.Clock = clock.RealClock{}
+
+// SyncUpdateSteps moves the CloneSet through updateStrategy.steps and records the progress into newStatus.UpdateStepStatus.
+// The newStatus should already contain the currentRevision and updateRevision of this reconcile.
+// It returns the duration to requeue when the current step is waiting for its pause or checks.
+func SyncUpdateSteps(cs *appsv1alpha1.CloneSet, newStatus *appsv1alpha1.CloneSetStatus, pods []*v1.Pod, recorder record.EventRecorder) time.Duration {
+	steps := cs.Spec.UpdateStrategy.Steps
+	if len(steps) == 0 {
+		newStatus.UpdateStepStatus = nil
+		return 0
+	}
+
+	now := timer.Now()
+	stepStatus := cs.Status.UpdateStepStatus.DeepCopy()
+	if stepStatus == nil || stepStatus.UpdateRevision != newStatus.UpdateRevision {
+		stepStatus = &appsv1alpha1.CloneSetUpdateStepStatus{
+			UpdateRevision:     newStatus.UpdateRevision,
+			CurrentStepIndex:   0,
+			CurrentStepState:   appsv1alpha1.CloneSetStepUpdating,
+			LastTransitionTime: metav1.NewTime(now),
+		}
+		if newStatus.CurrentRevision == newStatus.UpdateRevision {
+			// nothing to roll out, such as a new created CloneSet
+			stepStatus.CurrentStepIndex = int32(len(steps) - 1)
+			stepStatus.CurrentStepState = appsv1alpha1.CloneSetStepCompleted
+		}
+	}
+	if int(stepStatus.CurrentStepIndex) >= len(steps) {
+		stepStatus.CurrentStepIndex = int32(len(steps) - 1)
+	}
+	newStatus.UpdateStepStatus = stepStatus
+
+	if cs.Spec.UpdateStrategy.Paused {
+		if stepStatus.CurrentStepState == appsv1alpha1.CloneSetStepVerifying && stepStatus.PausedTime == nil {
+			stepStatus.PausedTime = &metav1.Time{Time: now}
+		}
+		return 0
+	}
+	if stepStatus.PausedTime != nil {
+		// extend the verifying of the current step by the time paused
+		stepStatus.LastTransitionTime = metav1.NewTime(stepStatus.LastTransitionTime.Add(now.Sub(stepStatus.PausedTime.Time)))
+		stepStatus.PausedTime = nil
+	}
+
+	coreControl := clonesetcore.New(cs)
+	transit := func(index int32, state appsv1alpha1.CloneSetUpdateStepState) {
+		klog.V(3).InfoS("CloneSet moved update step", "cloneSet", klog.KObj(cs), "step", index, "state", state)
+		stepStatus.CurrentStepIndex = index
+		stepStatus.CurrentStepState = state
+		stepStatus.LastTransitionTime = metav1.NewTime(now)
+	}
+
+	for {
+		step := &steps[stepStatus.CurrentStepIndex]
+		switch stepStatus.CurrentStepState {
+		case appsv1alpha1.CloneSetStepUpdating:
+			partition, err := util.CalculatePartitionReplicas(&step.Partition, cs.Spec.Replicas)
+			if err != nil {
+				klog.ErrorS(err, "CloneSet step partition value was illegal", "cloneSet", klog.KObj(cs), "step", stepStatus.CurrentStepIndex)
+				return 0
+			}
+			expected := int(*cs.Spec.Replicas) - partition
+			var updatedAvailable int
+			for _, pod := range pods {
+				if clonesetutils.EqualToRevisionHash("", pod, newStatus.UpdateRevision) && IsPodAvailable(coreControl, pod, cs.Spec.MinReadySeconds) {
+					updatedAvailable++
+				}
+			}
+			if updatedAvailable < expected {
+				stepStatus.Message = fmt.Sprintf("waiting for updated pods to be available (%d/%d)", updatedAvailable, expected)
+				return 0
+			}
+			transit(stepStatus.CurrentStepIndex, appsv1alpha1.CloneSetStepVerifying)
+
+		case appsv1alpha1.CloneSetStepVerifying:
+			elapsed := now.Sub(stepStatus.LastTransitionTime.Time)
+			pause := time.Duration(step.PauseSeconds) * time.Second
+			if elapsed < pause {
+				stepStatus.Message = fmt.Sprintf("paused for %ds", step.PauseSeconds)
+				return pause - elapsed
+			}
+			if check := findFailedStepCheck(step.Checks, pods, newStatus.UpdateRevision); check != nil {
+				// the timeout of checks starts after the pause
+				timeout := time.Duration(check.TimeoutSeconds) * time.Second
+				if timeout > 0 && elapsed-pause >= timeout {
+					stepStatus.Message = fmt.Sprintf("check of pod condition %s timed out", check.PodConditionType)
+					transit(stepStatus.CurrentStepIndex, appsv1alpha1.CloneSetStepFailed)
+					recorder.Eventf(cs, v1.EventTypeWarning, "FailedUpdateStep", "update step %d stopped: %s", stepStatus.CurrentStepIndex, stepStatus.Message)
+					return 0
+				}
+				stepStatus.Message = fmt.Sprintf("waiting for pod condition %s of updated pods", check.PodConditionType)
+				if timeout > 0 {
+					return timeout - (elapsed - pause)
+				}
+				return 0
+			}
+			stepStatus.Message = ""
+			if int(stepStatus.CurrentStepIndex) == len(steps)-1 {
+				transit(stepStatus.CurrentStepIndex, appsv1alpha1.CloneSetStepCompleted)
+				return 0
+			}
+			transit(stepStatus.CurrentStepIndex+1, appsv1alpha1.CloneSetStepUpdating)
+
+		case appsv1alpha1.CloneSetStepFailed:
+			retry := cs.Annotations[appsv1alpha1.CloneSetRetryUpdateStepAnnotation]
+			if retry == "" || retry == stepStatus.ObservedRetry {
+				return 0
+			}
+			// retry the checks without pausing again
+			stepStatus.ObservedRetry = retry
+			transit(stepStatus.CurrentStepIndex, appsv1alpha1.CloneSetStepVerifying)
+			stepStatus.LastTransitionTime = metav1.NewTime(now.Add(-time.Duration(step.PauseSeconds) * time.Second))
+
+		default:
+			return 0
+		}
+	}
+}
+
+// findFailedStepCheck returns the first check that has not passed on all the updated pods.
+func findFailedStepCheck(checks []appsv1alpha1.CloneSetUpdateStepCheck, pods []*v1.Pod, updateRevision string) *appsv1alpha1.CloneSetUpdateStepCheck {
+	for i := range checks {
+		for _, pod := range pods {
+			if !clonesetutils.EqualToRevisionHash("", pod, updateRevision) {
+				continue
+			}
+			if cond := util.GetCondition(pod, checks[i].PodConditionType); cond == nil || cond.Status != v1.ConditionTrue {
+				return &checks[i]
+			}
+		}
+	}
+	return nil
+}
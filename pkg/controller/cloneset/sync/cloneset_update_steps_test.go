@@ -0,0 +1,327 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/clock"
+	testingclock "k8s.io/utils/clock/testing"
+
+	appspub "github.com/openkruise/kruise/apis/apps/pub"
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+)
+
+func TestSyncUpdateSteps(t *testing.T) {
+	oldRevision := "old_rev"
+	newRevision := "new_rev"
+	now := time.Unix(time.Now().Unix(), 0)
+	defer func(c clock.Clock) { timer = c }(timer)
+	timer = testingclock.NewFakeClock(now)
+
+	steps := []appsv1alpha1.CloneSetUpdateStep{
+		{Partition: intstr.FromInt32(3), PauseSeconds: 60},
+		{Partition: intstr.FromInt32(1), Checks: []appsv1alpha1.CloneSetUpdateStepCheck{{PodConditionType: "Healthy", TimeoutSeconds: 30}}},
+		{Partition: intstr.FromInt32(0)},
+	}
+	healthy := func(pod *v1.Pod) *v1.Pod {
+		pod.Status.Conditions = append(pod.Status.Conditions, v1.PodCondition{Type: "Healthy", Status: v1.ConditionTrue})
+		return pod
+	}
+	stepStatus := func(index int32, state appsv1alpha1.CloneSetUpdateStepState, since time.Duration) *appsv1alpha1.CloneSetUpdateStepStatus {
+		return &appsv1alpha1.CloneSetUpdateStepStatus{
+			UpdateRevision:     newRevision,
+			CurrentStepIndex:   index,
+			CurrentStepState:   state,
+			LastTransitionTime: metav1.NewTime(now.Add(-since)),
+		}
+	}
+
+	cases := []struct {
+		name            string
+		currentRevision string
+		oldStepStatus   *appsv1alpha1.CloneSetUpdateStepStatus
+		paused          bool
+		pods            []*v1.Pod
+		expectIndex     int32
+		expectState     appsv1alpha1.CloneSetUpdateStepState
+		expectDuration  time.Duration
+	}{
+		{
+			name:            "no revision to roll out",
+			currentRevision: newRevision,
+			pods: []*v1.Pod{
+				createTestPod(newRevision, appspub.LifecycleStateNormal, true, false),
+			},
+			expectIndex: 2,
+			expectState: appsv1alpha1.CloneSetStepCompleted,
+		},
+		{
+			name:            "start the first step",
+			currentRevision: oldRevision,
+			oldStepStatus:   &appsv1alpha1.CloneSetUpdateStepStatus{UpdateRevision: "older_rev", CurrentStepIndex: 2, CurrentStepState: appsv1alpha1.CloneSetStepCompleted},
+			pods: []*v1.Pod{
+				createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false),
+				createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false),
+				createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false),
+				createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false),
+			},
+			expectIndex: 0,
+			expectState: appsv1alpha1.CloneSetStepUpdating,
+		},
+		{
+			name:            "pods of the first step available, start pausing",
+			currentRevision: oldRevision,
+			oldStepStatus:   stepStatus(0, appsv1alpha1.CloneSetStepUpdating, time.Minute),
+			pods: []*v1.Pod{
+				createTestPod(newRevision, appspub.LifecycleStateNormal, true, false),
+				createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false),
+				createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false),
+				createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false),
+			},
+			expectIndex:    0,
+			expectState:    appsv1alpha1.CloneSetStepVerifying,
+			expectDuration: time.Minute,
+		},
+		{
+			name:            "pause of the first step finished",
+			currentRevision: oldRevision,
+			oldStepStatus:   stepStatus(0, appsv1alpha1.CloneSetStepVerifying, time.Minute),
+			pods: []*v1.Pod{
+				createTestPod(newRevision, appspub.LifecycleStateNormal, true, false),
+				createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false),
+				createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false),
+				createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false),
+			},
+			expectIndex: 1,
+			expectState: appsv1alpha1.CloneSetStepUpdating,
+		},
+		{
+			name:            "wait for checks of the second step",
+			currentRevision: oldRevision,
+			oldStepStatus:   stepStatus(1, appsv1alpha1.CloneSetStepVerifying, 10*time.Second),
+			pods: []*v1.Pod{
+				healthy(createTestPod(newRevision, appspub.LifecycleStateNormal, true, false)),
+				createTestPod(newRevision, appspub.LifecycleStateNormal, true, false),
+				healthy(createTestPod(newRevision, appspub.LifecycleStateNormal, true, false)),
+				createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false),
+			},
+			expectIndex:    1,
+			expectState:    appsv1alpha1.CloneSetStepVerifying,
+			expectDuration: 20 * time.Second,
+		},
+		{
+			name:            "checks of the second step timed out",
+			currentRevision: oldRevision,
+			oldStepStatus:   stepStatus(1, appsv1alpha1.CloneSetStepVerifying, 30*time.Second),
+			pods: []*v1.Pod{
+				healthy(createTestPod(newRevision, appspub.LifecycleStateNormal, true, false)),
+				createTestPod(newRevision, appspub.LifecycleStateNormal, true, false),
+				healthy(createTestPod(newRevision, appspub.LifecycleStateNormal, true, false)),
+				createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false),
+			},
+			expectIndex: 1,
+			expectState: appsv1alpha1.CloneSetStepFailed,
+		},
+		{
+			name:            "checks of the second step paused",
+			currentRevision: oldRevision,
+			oldStepStatus:   stepStatus(1, appsv1alpha1.CloneSetStepVerifying, 10*time.Second),
+			paused:          true,
+			pods: []*v1.Pod{
+				createTestPod(newRevision, appspub.LifecycleStateNormal, true, false),
+				createTestPod(newRevision, appspub.LifecycleStateNormal, true, false),
+				createTestPod(newRevision, appspub.LifecycleStateNormal, true, false),
+				createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false),
+			},
+			expectIndex: 1,
+			expectState: appsv1alpha1.CloneSetStepVerifying,
+		},
+		{
+			name:            "checks of the second step resumed after paused for a minute",
+			currentRevision: oldRevision,
+			oldStepStatus: func() *appsv1alpha1.CloneSetUpdateStepStatus {
+				status := stepStatus(1, appsv1alpha1.CloneSetStepVerifying, 70*time.Second)
+				status.PausedTime = &metav1.Time{Time: now.Add(-time.Minute)}
+				return status
+			}(),
+			pods: []*v1.Pod{
+				healthy(createTestPod(newRevision, appspub.LifecycleStateNormal, true, false)),
+				createTestPod(newRevision, appspub.LifecycleStateNormal, true, false),
+				healthy(createTestPod(newRevision, appspub.LifecycleStateNormal, true, false)),
+				createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false),
+			},
+			expectIndex:    1,
+			expectState:    appsv1alpha1.CloneSetStepVerifying,
+			expectDuration: 20 * time.Second,
+		},
+		{
+			name:            "checks of the second step passed",
+			currentRevision: oldRevision,
+			oldStepStatus:   stepStatus(1, appsv1alpha1.CloneSetStepVerifying, 10*time.Second),
+			pods: []*v1.Pod{
+				healthy(createTestPod(newRevision, appspub.LifecycleStateNormal, true, false)),
+				healthy(createTestPod(newRevision, appspub.LifecycleStateNormal, true, false)),
+				healthy(createTestPod(newRevision, appspub.LifecycleStateNormal, true, false)),
+				createTestPod(oldRevision, appspub.LifecycleStateNormal, true, false),
+			},
+			expectIndex: 2,
+			expectState: appsv1alpha1.CloneSetStepUpdating,
+		},
+		{
+			name:            "all steps completed",
+			currentRevision: oldRevision,
+			oldStepStatus:   stepStatus(2, appsv1alpha1.CloneSetStepUpdating, 10*time.Second),
+			pods: []*v1.Pod{
+				createTestPod(newRevision, appspub.LifecycleStateNormal, true, false),
+				createTestPod(newRevision, appspub.LifecycleStateNormal, true, false),
+				createTestPod(newRevision, appspub.LifecycleStateNormal, true, false),
+				createTestPod(newRevision, appspub.LifecycleStateNormal, true, false),
+			},
+			expectIndex: 2,
+			expectState: appsv1alpha1.CloneSetStepCompleted,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cs := createTestCloneSet(4, intstr.FromInt32(0), intstr.FromInt32(1), intstr.FromInt32(0))
+			cs.Spec.UpdateStrategy.Partition = nil
+			cs.Spec.UpdateStrategy.Steps = steps
+			cs.Status.UpdateStepStatus = tc.oldStepStatus
+			cs.Spec.UpdateStrategy.Paused = tc.paused
+			newStatus := &appsv1alpha1.CloneSetStatus{CurrentRevision: tc.currentRevision, UpdateRevision: newRevision}
+
+			duration := SyncUpdateSteps(cs, newStatus, tc.pods, record.NewFakeRecorder(10))
+			if duration != tc.expectDuration {
+				t.Fatalf("expected duration %v, got %v", tc.expectDuration, duration)
+			}
+			got := newStatus.UpdateStepStatus
+			if got == nil {
+				t.Fatalf("expected step status, got nil")
+			}
+			if got.UpdateRevision != newRevision || got.CurrentStepIndex != tc.expectIndex || got.CurrentStepState != tc.expectState {
+				t.Fatalf("expected step %d in %s, got %d in %s", tc.expectIndex, tc.expectState, got.CurrentStepIndex, got.CurrentStepState)
+			}
+			if (got.PausedTime != nil) != tc.paused {
+				t.Fatalf("expected paused time recorded %v, got %v", tc.paused, got.PausedTime)
+			}
+		})
+	}
+}
+
+func TestSyncUpdateStepsCheckTimeoutAndRetry(t *testing.T) {
+	newRevision := "new_rev"
+	now := time.Unix(time.Now().Unix(), 0)
+	defer func(c clock.Clock) { timer = c }(timer)
+	timer = testingclock.NewFakeClock(now)
+
+	// the timeout of checks is shorter than the pause
+	steps := []appsv1alpha1.CloneSetUpdateStep{
+		{Partition: intstr.FromInt32(1), PauseSeconds: 60, Checks: []appsv1alpha1.CloneSetUpdateStepCheck{{PodConditionType: "Healthy", TimeoutSeconds: 30}}},
+		{Partition: intstr.FromInt32(0)},
+	}
+	cases := []struct {
+		name           string
+		state          appsv1alpha1.CloneSetUpdateStepState
+		since          time.Duration
+		observedRetry  string
+		retry          string
+		expectState    appsv1alpha1.CloneSetUpdateStepState
+		expectDuration time.Duration
+		expectEvents   int
+	}{
+		{
+			name:           "wait for checks after the pause",
+			state:          appsv1alpha1.CloneSetStepVerifying,
+			since:          70 * time.Second,
+			expectState:    appsv1alpha1.CloneSetStepVerifying,
+			expectDuration: 20 * time.Second,
+		},
+		{
+			name:         "checks timed out after the pause",
+			state:        appsv1alpha1.CloneSetStepVerifying,
+			since:        90 * time.Second,
+			expectState:  appsv1alpha1.CloneSetStepFailed,
+			expectEvents: 1,
+		},
+		{
+			name:        "failed step without retry",
+			state:       appsv1alpha1.CloneSetStepFailed,
+			since:       time.Hour,
+			expectState: appsv1alpha1.CloneSetStepFailed,
+		},
+		{
+			name:           "failed step retried",
+			state:          appsv1alpha1.CloneSetStepFailed,
+			since:          time.Hour,
+			retry:          "1",
+			expectState:    appsv1alpha1.CloneSetStepVerifying,
+			expectDuration: 30 * time.Second,
+		},
+		{
+			name:          "failed step retried already",
+			state:         appsv1alpha1.CloneSetStepFailed,
+			since:         time.Hour,
+			observedRetry: "1",
+			retry:         "1",
+			expectState:   appsv1alpha1.CloneSetStepFailed,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cs := createTestCloneSet(2, intstr.FromInt32(0), intstr.FromInt32(1), intstr.FromInt32(0))
+			cs.Spec.UpdateStrategy.Partition = nil
+			cs.Spec.UpdateStrategy.Steps = steps
+			if tc.retry != "" {
+				cs.Annotations = map[string]string{appsv1alpha1.CloneSetRetryUpdateStepAnnotation: tc.retry}
+			}
+			cs.Status.UpdateStepStatus = &appsv1alpha1.CloneSetUpdateStepStatus{
+				UpdateRevision:     newRevision,
+				CurrentStepState:   tc.state,
+				LastTransitionTime: metav1.NewTime(now.Add(-tc.since)),
+				ObservedRetry:      tc.observedRetry,
+			}
+			pods := []*v1.Pod{
+				createTestPod(newRevision, appspub.LifecycleStateNormal, true, false),
+				createTestPod("old_rev", appspub.LifecycleStateNormal, true, false),
+			}
+			newStatus := &appsv1alpha1.CloneSetStatus{CurrentRevision: "old_rev", UpdateRevision: newRevision}
+			recorder := record.NewFakeRecorder(10)
+
+			duration := SyncUpdateSteps(cs, newStatus, pods, recorder)
+			if duration != tc.expectDuration {
+				t.Fatalf("expected duration %v, got %v", tc.expectDuration, duration)
+			}
+			if got := newStatus.UpdateStepStatus; got.CurrentStepIndex != 0 || got.CurrentStepState != tc.expectState {
+				t.Fatalf("expected step 0 in %s, got %d in %s", tc.expectState, got.CurrentStepIndex, got.CurrentStepState)
+			}
+			if tc.retry != "" && newStatus.UpdateStepStatus.ObservedRetry != tc.retry {
+				t.Fatalf("expected retry %s observed, got %s", tc.retry, newStatus.UpdateStepStatus.ObservedRetry)
+			}
+			if len(recorder.Events) != tc.expectEvents {
+				t.Fatalf("expected %d events, got %d", tc.expectEvents, len(recorder.Events))
+			}
+		})
+	}
+}
@@ -28,6 +28,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
 	kubecontroller "k8s.io/kubernetes/pkg/controller"
 	"k8s.io/utils/integer"
@@ -39,6 +40,7 @@ import (
 	utilclient "github.com/openkruise/kruise/pkg/util/client"
 	"github.com/openkruise/kruise/pkg/util/expectations"
 	utilfeature "github.com/openkruise/kruise/pkg/util/feature"
+	"github.com/openkruise/kruise/pkg/util/podreadiness"
 	"github.com/openkruise/kruise/pkg/util/requeueduration"
 	"github.com/openkruise/kruise/pkg/util/revision"
 )
@@ -53,6 +55,9 @@ var (
 	ScaleExpectations           = expectations.NewScaleExpectations()
 	ResourceVersionExpectations = expectations.NewResourceVersionExpectation()
 
+	// StandbyNotReadyMessage is the not-ready key that keeps the standby pods out of the Service endpoints.
+	StandbyNotReadyMessage = podreadiness.Message{UserAgent: "CloneSet", Key: "Standby"}
+
 	// DurationStore is a short cut for any sub-functions to notify the reconcile how long to wait to requeue
 	DurationStore = requeueduration.DurationStore{}
 )
@@ -255,6 +260,24 @@ func DoItSlowly(count int, initialBatchSize int, fn func() error) (int, error) {
 	return successes, nil
 }
 
+// GetUpdatePartition returns the partition that the update should follow. If updateStrategy.steps is set,
+// it is the partition of the current step recorded in the given status, or the first step if there is no record yet.
+func GetUpdatePartition(cs *appsv1alpha1.CloneSet, status *appsv1alpha1.CloneSetStatus) *intstr.IntOrString {
+	steps := cs.Spec.UpdateStrategy.Steps
+	if len(steps) == 0 {
+		return cs.Spec.UpdateStrategy.Partition
+	}
+	var index int
+	if status != nil && status.UpdateStepStatus != nil {
+		index = int(status.UpdateStepStatus.CurrentStepIndex)
+	}
+	if index >= len(steps) {
+		index = len(steps) - 1
+	}
+	partition := steps[index].Partition
+	return &partition
+}
+
 func HasProgressDeadline(cs *appsv1alpha1.CloneSet) bool {
 	return cs.Spec.ProgressDeadlineSeconds != nil && *cs.Spec.ProgressDeadlineSeconds != math.MaxInt32
 }
@@ -48,6 +48,8 @@ type ActivePodsWithRanks struct {
 	Pods          []*v1.Pod
 	Ranker        Ranker
 	AvailableFunc func(*v1.Pod) bool
+	// VictimRanker ranks the pods by scale down policy, which takes the place of pod-deletion-cost and Ranker if set.
+	VictimRanker Ranker
 }
 
 func (s ActivePodsWithRanks) Len() int      { return len(s.Pods) }
@@ -75,21 +77,28 @@ func (s ActivePodsWithRanks) Less(i, j int) bool {
 		return !podutil.IsPodReady(s.Pods[i])
 	}
 
-	// 4. Lower pod-deletion cost < higher pod-deletion-cost
-	pi, _ := getDeletionCostFromPodAnnotations(s.Pods[i].Annotations)
-	pj, _ := getDeletionCostFromPodAnnotations(s.Pods[j].Annotations)
-	if pi != pj {
-		return pi < pj
-	}
+	if s.VictimRanker != nil {
+		// 4&5. Higher victim ranks < lower victim ranks
+		if rankI, rankJ := s.VictimRanker.GetRank(s.Pods[i]), s.VictimRanker.GetRank(s.Pods[j]); rankI != rankJ {
+			return rankI > rankJ
+		}
+	} else {
+		// 4. Lower pod-deletion cost < higher pod-deletion-cost
+		pi, _ := getDeletionCostFromPodAnnotations(s.Pods[i].Annotations)
+		pj, _ := getDeletionCostFromPodAnnotations(s.Pods[j].Annotations)
+		if pi != pj {
+			return pi < pj
+		}
 
-	// 5. Higher ranks < lower ranks
-	var rankI, rankJ float64
-	if s.Ranker != nil {
-		rankI = s.Ranker.GetRank(s.Pods[i])
-		rankJ = s.Ranker.GetRank(s.Pods[j])
-	}
-	if rankI != rankJ {
-		return rankI > rankJ
+		// 5. Higher ranks < lower ranks
+		var rankI, rankJ float64
+		if s.Ranker != nil {
+			rankI = s.Ranker.GetRank(s.Pods[i])
+			rankJ = s.Ranker.GetRank(s.Pods[j])
+		}
+		if rankI != rankJ {
+			return rankI > rankJ
+		}
 	}
 
 	// TODO: take availability into account when we push minReadySeconds information from deployment into pods,
@@ -0,0 +1,131 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"time"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+)
+
+const updateWindowTimeLayout = "15:04"
+
+var updateWindowDays = map[string]time.Weekday{
+	"Sun": time.Sunday,
+	"Mon": time.Monday,
+	"Tue": time.Tuesday,
+	"Wed": time.Wednesday,
+	"Thu": time.Thursday,
+	"Fri": time.Friday,
+	"Sat": time.Saturday,
+}
+
+// updateWindow is the parsed CloneSetUpdateWindow.
+type updateWindow struct {
+	startHour   int
+	startMinute int
+	duration    time.Duration
+	// days is nil if the window starts on every day
+	days     map[time.Weekday]bool
+	location *time.Location
+}
+
+// ValidateUpdateWindow returns error if the update window is invalid.
+func ValidateUpdateWindow(window *appsv1alpha1.CloneSetUpdateWindow) error {
+	_, err := parseUpdateWindow(window)
+	return err
+}
+
+func parseUpdateWindow(window *appsv1alpha1.CloneSetUpdateWindow) (*updateWindow, error) {
+	start, err := time.Parse(updateWindowTimeLayout, window.Start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start %q, must be in HH:MM format", window.Start)
+	}
+	end, err := time.Parse(updateWindowTimeLayout, window.End)
+	if err != nil {
+		return nil, fmt.Errorf("invalid end %q, must be in HH:MM format", window.End)
+	}
+	w := &updateWindow{
+		startHour:   start.Hour(),
+		startMinute: start.Minute(),
+		duration:    end.Sub(start),
+		location:    time.UTC,
+	}
+	if w.duration <= 0 {
+		w.duration += 24 * time.Hour
+	}
+	if len(window.Days) > 0 {
+		w.days = make(map[time.Weekday]bool, len(window.Days))
+		for _, day := range window.Days {
+			weekday, ok := updateWindowDays[day]
+			if !ok {
+				return nil, fmt.Errorf("invalid day %q, must be one of Sun, Mon, Tue, Wed, Thu, Fri, Sat", day)
+			}
+			w.days[weekday] = true
+		}
+	}
+	if window.TimeZone != "" {
+		if w.location, err = time.LoadLocation(window.TimeZone); err != nil {
+			return nil, fmt.Errorf("invalid timeZone %q: %v", window.TimeZone, err)
+		}
+	}
+	return w, nil
+}
+
+// openedAt returns the start time of the window opening on the given day.
+func (w *updateWindow) openedAt(year int, month time.Month, day int) (time.Time, bool) {
+	start := time.Date(year, month, day, w.startHour, w.startMinute, 0, 0, w.location)
+	if w.days != nil && !w.days[start.Weekday()] {
+		return time.Time{}, false
+	}
+	return start, true
+}
+
+// IsInUpdateWindows returns whether now is in any of the windows.
+// If not, it also returns the duration until the next window opens, which is zero if no window can be parsed.
+func IsInUpdateWindows(windows []appsv1alpha1.CloneSetUpdateWindow, now time.Time) (bool, time.Duration) {
+	if len(windows) == 0 {
+		return true, 0
+	}
+
+	var next time.Duration
+	for i := range windows {
+		w, err := parseUpdateWindow(&windows[i])
+		if err != nil {
+			continue
+		}
+		t := now.In(w.location)
+		// check the windows opened yesterday and today, then find the next one in a week
+		for offset := -1; offset <= 7; offset++ {
+			start, ok := w.openedAt(t.Year(), t.Month(), t.Day()+offset)
+			if !ok {
+				continue
+			}
+			if !t.Before(start) && t.Before(start.Add(w.duration)) {
+				return true, 0
+			}
+			if start.After(t) {
+				if wait := start.Sub(t); next == 0 || wait < next {
+					next = wait
+				}
+				break
+			}
+		}
+	}
+	return false, next
+}
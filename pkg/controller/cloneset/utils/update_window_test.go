@@ -0,0 +1,129 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"testing"
+	"time"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+)
+
+func TestIsInUpdateWindows(t *testing.T) {
+	// 2025-06-04 is Wednesday
+	wednesday := func(hour, minute int) time.Time {
+		return time.Date(2025, 6, 4, hour, minute, 0, 0, time.UTC)
+	}
+
+	cases := []struct {
+		name       string
+		windows    []appsv1alpha1.CloneSetUpdateWindow
+		now        time.Time
+		expectIn   bool
+		expectWait time.Duration
+	}{
+		{
+			name:     "no windows",
+			now:      wednesday(12, 0),
+			expectIn: true,
+		},
+		{
+			name:     "in daily window",
+			windows:  []appsv1alpha1.CloneSetUpdateWindow{{Start: "10:00", End: "14:00"}},
+			now:      wednesday(12, 0),
+			expectIn: true,
+		},
+		{
+			name:       "before daily window",
+			windows:    []appsv1alpha1.CloneSetUpdateWindow{{Start: "10:00", End: "14:00"}},
+			now:        wednesday(8, 30),
+			expectWait: 90 * time.Minute,
+		},
+		{
+			name:       "after daily window",
+			windows:    []appsv1alpha1.CloneSetUpdateWindow{{Start: "10:00", End: "14:00"}},
+			now:        wednesday(14, 0),
+			expectWait: 20 * time.Hour,
+		},
+		{
+			name:     "in window across midnight opened yesterday",
+			windows:  []appsv1alpha1.CloneSetUpdateWindow{{Start: "22:00", End: "06:00"}},
+			now:      wednesday(1, 0),
+			expectIn: true,
+		},
+		{
+			name:       "window across midnight only opened on weekends",
+			windows:    []appsv1alpha1.CloneSetUpdateWindow{{Start: "22:00", End: "06:00", Days: []string{"Sat", "Sun"}}},
+			now:        wednesday(1, 0),
+			expectWait: 3*24*time.Hour + 21*time.Hour,
+		},
+		{
+			name:     "in window of time zone",
+			windows:  []appsv1alpha1.CloneSetUpdateWindow{{Start: "18:00", End: "20:00", TimeZone: "Asia/Shanghai"}},
+			now:      wednesday(11, 0),
+			expectIn: true,
+		},
+		{
+			name: "choose the nearest window",
+			windows: []appsv1alpha1.CloneSetUpdateWindow{
+				{Start: "20:00", End: "21:00"},
+				{Start: "10:00", End: "11:00", Days: []string{"Thu"}},
+				{Start: "bad", End: "11:00"},
+			},
+			now:        wednesday(12, 0),
+			expectWait: 8 * time.Hour,
+		},
+		{
+			name:    "all windows invalid",
+			windows: []appsv1alpha1.CloneSetUpdateWindow{{Start: "10:00", End: "11:00", Days: []string{"Someday"}}},
+			now:     wednesday(12, 0),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			in, wait := IsInUpdateWindows(tc.windows, tc.now)
+			if in != tc.expectIn || wait != tc.expectWait {
+				t.Fatalf("expected %v %v, got %v %v", tc.expectIn, tc.expectWait, in, wait)
+			}
+		})
+	}
+}
+
+func TestValidateUpdateWindow(t *testing.T) {
+	valid := []appsv1alpha1.CloneSetUpdateWindow{
+		{Start: "00:00", End: "00:00"},
+		{Start: "22:30", End: "06:00", Days: []string{"Fri", "Sat"}, TimeZone: "America/New_York"},
+	}
+	for i := range valid {
+		if err := ValidateUpdateWindow(&valid[i]); err != nil {
+			t.Fatalf("expected valid window %v, got %v", valid[i], err)
+		}
+	}
+
+	invalid := []appsv1alpha1.CloneSetUpdateWindow{
+		{Start: "10:60", End: "02:00"},
+		{Start: "01:00", End: "24:00"},
+		{Start: "01:00", End: "02:00", Days: []string{"Monday"}},
+		{Start: "01:00", End: "02:00", TimeZone: "Unknown/Zone"},
+	}
+	for i := range invalid {
+		if err := ValidateUpdateWindow(&invalid[i]); err == nil {
+			t.Fatalf("expected invalid window %v", invalid[i])
+		}
+	}
+}
@@ -0,0 +1,256 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	resourcehelper "k8s.io/component-helpers/resource"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	utilclient "github.com/openkruise/kruise/pkg/util/client"
+	"github.com/openkruise/kruise/pkg/util/fieldindex"
+)
+
+// VictimScorer scores the pods to choose victims when scaling down.
+// The score should be normalized into [0, 1], and the pods with higher score are preferred to be deleted.
+type VictimScorer interface {
+	Score(pod *v1.Pod) float64
+}
+
+// VictimScorerFactory creates a VictimScorer for the pods to choose from.
+type VictimScorerFactory func(pods []*v1.Pod, scorer *appsv1alpha1.CloneSetScaleDownScorer, reader client.Reader) VictimScorer
+
+var victimScorerFactories = map[appsv1alpha1.CloneSetScaleDownScorerType]VictimScorerFactory{
+	appsv1alpha1.DeletionCostScaleDownScorer:   newDeletionCostScorer,
+	appsv1alpha1.NodeBinPackingScaleDownScorer: newNodeBinPackingScorer,
+	appsv1alpha1.ZoneSpreadScaleDownScorer:     newZoneSpreadScorer,
+}
+
+// RegisterVictimScorer registers a factory of VictimScorer for the given type.
+func RegisterVictimScorer(scorerType appsv1alpha1.CloneSetScaleDownScorerType, factory VictimScorerFactory) {
+	victimScorerFactories[scorerType] = factory
+}
+
+// IsVictimScorerRegistered returns whether the given type of VictimScorer has been registered.
+func IsVictimScorerRegistered(scorerType appsv1alpha1.CloneSetScaleDownScorerType) bool {
+	_, ok := victimScorerFactories[scorerType]
+	return ok
+}
+
+// WeightedVictimRanker is a Ranker that sums the weighted scores of all scorers in CloneSetScaleDownPolicy.
+type WeightedVictimRanker struct {
+	scorers []*appsv1alpha1.CloneSetScaleDownScorer
+	scores  map[types.UID][]float64
+	total   map[types.UID]float64
+}
+
+// NewWeightedVictimRanker calculates the scores of pods with the scorers in policy.
+func NewWeightedVictimRanker(pods []*v1.Pod, policy *appsv1alpha1.CloneSetScaleDownPolicy, reader client.Reader) *WeightedVictimRanker {
+	r := &WeightedVictimRanker{
+		scores: make(map[types.UID][]float64, len(pods)),
+		total:  make(map[types.UID]float64, len(pods)),
+	}
+	for i := range policy.Scorers {
+		s := &policy.Scorers[i]
+		factory, ok := victimScorerFactories[s.Type]
+		if !ok {
+			klog.InfoS("Ignored unknown scale down scorer", "type", s.Type)
+			continue
+		}
+		scorer := factory(pods, s, reader)
+		r.scorers = append(r.scorers, s)
+		for _, pod := range pods {
+			score := scorer.Score(pod)
+			r.scores[pod.UID] = append(r.scores[pod.UID], score)
+			r.total[pod.UID] += score * float64(s.Weight)
+		}
+	}
+	return r
+}
+
+func (r *WeightedVictimRanker) GetRank(pod *v1.Pod) float64 {
+	return r.total[pod.UID]
+}
+
+// Explain returns the total score and the score from each scorer of the pod.
+func (r *WeightedVictimRanker) Explain(pod *v1.Pod) string {
+	var details []string
+	for i, score := range r.scores[pod.UID] {
+		details = append(details, fmt.Sprintf("%s=%.2f*%d", r.scorers[i].Type, score, r.scorers[i].Weight))
+	}
+	return fmt.Sprintf("%.2f (%s)", r.total[pod.UID], strings.Join(details, ", "))
+}
+
+// deletionCostScorer scores the pods with lower pod-deletion-cost higher.
+type deletionCostScorer struct {
+	costs    map[types.UID]int32
+	min, max int32
+}
+
+func newDeletionCostScorer(pods []*v1.Pod, _ *appsv1alpha1.CloneSetScaleDownScorer, _ client.Reader) VictimScorer {
+	s := &deletionCostScorer{costs: make(map[types.UID]int32, len(pods)), min: math.MaxInt32, max: math.MinInt32}
+	for _, pod := range pods {
+		cost, _ := getDeletionCostFromPodAnnotations(pod.Annotations)
+		s.costs[pod.UID] = cost
+		if cost < s.min {
+			s.min = cost
+		}
+		if cost > s.max {
+			s.max = cost
+		}
+	}
+	return s
+}
+
+func (s *deletionCostScorer) Score(pod *v1.Pod) float64 {
+	if s.max <= s.min {
+		return 0
+	}
+	return float64(int64(s.max)-int64(s.costs[pod.UID])) / float64(int64(s.max)-int64(s.min))
+}
+
+// nodeBinPackingScorer scores the pods on the nodes with lower resource utilization higher.
+type nodeBinPackingScorer struct {
+	nodeScores map[string]float64
+}
+
+func newNodeBinPackingScorer(pods []*v1.Pod, _ *appsv1alpha1.CloneSetScaleDownScorer, reader client.Reader) VictimScorer {
+	s := &nodeBinPackingScorer{nodeScores: make(map[string]float64)}
+	for _, pod := range pods {
+		nodeName := pod.Spec.NodeName
+		if nodeName == "" {
+			continue
+		}
+		if _, ok := s.nodeScores[nodeName]; ok {
+			continue
+		}
+		utilization, err := getNodeUtilization(nodeName, reader)
+		if err != nil {
+			klog.ErrorS(err, "Failed to get node utilization for scale down", "node", nodeName)
+			s.nodeScores[nodeName] = 0
+			continue
+		}
+		s.nodeScores[nodeName] = 1 - utilization
+	}
+	return s
+}
+
+func (s *nodeBinPackingScorer) Score(pod *v1.Pod) float64 {
+	return s.nodeScores[pod.Spec.NodeName]
+}
+
+// getNodeUtilization returns the ratio of requested to allocatable of the dominant resource in cpu and memory.
+func getNodeUtilization(nodeName string, reader client.Reader) (float64, error) {
+	node := &v1.Node{}
+	if err := reader.Get(context.TODO(), types.NamespacedName{Name: nodeName}, node); err != nil {
+		return 0, err
+	}
+	podList := &v1.PodList{}
+	if err := reader.List(context.TODO(), podList, client.MatchingFields{fieldindex.IndexNameForPodNodeName: nodeName}, utilclient.DisableDeepCopy); err != nil {
+		return 0, err
+	}
+
+	requested := v1.ResourceList{}
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		for name, quantity := range resourcehelper.PodRequests(pod, resourcehelper.PodResourcesOptions{}) {
+			if q, ok := requested[name]; ok {
+				q.Add(quantity)
+				requested[name] = q
+			} else {
+				requested[name] = quantity.DeepCopy()
+			}
+		}
+	}
+
+	var utilization float64
+	for _, name := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+		allocatable, ok := node.Status.Allocatable[name]
+		if !ok || allocatable.IsZero() {
+			continue
+		}
+		req := requested[name]
+		if ratio := float64(req.MilliValue()) / float64(allocatable.MilliValue()); ratio > utilization {
+			utilization = ratio
+		}
+	}
+	return math.Min(utilization, 1), nil
+}
+
+// zoneSpreadScorer scores the pods in the topology with more pods higher.
+type zoneSpreadScorer struct {
+	podTopology map[types.UID]string
+	counts      map[string]int
+	min, max    int
+}
+
+func newZoneSpreadScorer(pods []*v1.Pod, scorer *appsv1alpha1.CloneSetScaleDownScorer, reader client.Reader) VictimScorer {
+	topologyKey := scorer.TopologyKey
+	if topologyKey == "" {
+		topologyKey = v1.LabelTopologyZone
+	}
+	s := &zoneSpreadScorer{podTopology: make(map[types.UID]string), counts: make(map[string]int), min: math.MaxInt32}
+	nodeTopology := make(map[string]string)
+	for _, pod := range pods {
+		nodeName := pod.Spec.NodeName
+		if nodeName == "" {
+			continue
+		}
+		value, ok := nodeTopology[nodeName]
+		if !ok {
+			node := &v1.Node{}
+			if err := reader.Get(context.TODO(), types.NamespacedName{Name: nodeName}, node); err != nil {
+				klog.ErrorS(err, "Failed to get node for scale down", "node", nodeName)
+			}
+			value = node.Labels[topologyKey]
+			nodeTopology[nodeName] = value
+		}
+		if value == "" {
+			continue
+		}
+		s.podTopology[pod.UID] = value
+		s.counts[value]++
+	}
+	for _, count := range s.counts {
+		if count < s.min {
+			s.min = count
+		}
+		if count > s.max {
+			s.max = count
+		}
+	}
+	return s
+}
+
+func (s *zoneSpreadScorer) Score(pod *v1.Pod) float64 {
+	value, ok := s.podTopology[pod.UID]
+	if !ok || s.max <= s.min {
+		return 0
+	}
+	return float64(s.counts[value]-s.min) / float64(s.max-s.min)
+}
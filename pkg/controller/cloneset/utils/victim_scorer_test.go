@@ -0,0 +1,143 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	"github.com/openkruise/kruise/pkg/util/fieldindex"
+)
+
+func TestWeightedVictimRanker(t *testing.T) {
+	newNode := func(name, zone string) *v1.Node {
+		return &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{v1.LabelTopologyZone: zone}},
+			Status: v1.NodeStatus{Allocatable: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("10"),
+				v1.ResourceMemory: resource.MustParse("10Gi"),
+			}},
+		}
+	}
+	newPod := func(name, nodeName, cpu, deletionCost string) *v1.Pod {
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, UID: types.UID(name)},
+			Spec: v1.PodSpec{
+				NodeName: nodeName,
+				Containers: []v1.Container{{Name: "main", Resources: v1.ResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse(cpu)},
+				}}},
+			},
+			Status: v1.PodStatus{Phase: v1.PodRunning},
+		}
+		if deletionCost != "" {
+			pod.Annotations = map[string]string{PodDeletionCost: deletionCost}
+		}
+		return pod
+	}
+
+	// node-a in zone-1 is 80% used, node-b in zone-1 is 20% used, node-c in zone-2 is 50% used
+	pods := []*v1.Pod{
+		newPod("pod-a1", "node-a", "4", ""),
+		newPod("pod-a2", "node-a", "4", "100"),
+		newPod("pod-b1", "node-b", "2", ""),
+		newPod("pod-c1", "node-c", "5", ""),
+	}
+	objects := []client.Object{newNode("node-a", "zone-1"), newNode("node-b", "zone-1"), newNode("node-c", "zone-2")}
+	for _, pod := range pods {
+		objects = append(objects, pod)
+	}
+	reader := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(objects...).
+		WithIndex(&v1.Pod{}, fieldindex.IndexNameForPodNodeName, func(obj client.Object) []string {
+			return []string{obj.(*v1.Pod).Spec.NodeName}
+		}).Build()
+
+	cases := []struct {
+		name        string
+		scorers     []appsv1alpha1.CloneSetScaleDownScorer
+		expectOrder []string
+	}{
+		{
+			name:        "deletion cost only",
+			scorers:     []appsv1alpha1.CloneSetScaleDownScorer{{Type: appsv1alpha1.DeletionCostScaleDownScorer, Weight: 1}},
+			expectOrder: []string{"pod-a1", "pod-b1", "pod-c1", "pod-a2"},
+		},
+		{
+			name:        "node bin-packing only",
+			scorers:     []appsv1alpha1.CloneSetScaleDownScorer{{Type: appsv1alpha1.NodeBinPackingScaleDownScorer, Weight: 1}},
+			expectOrder: []string{"pod-b1", "pod-c1", "pod-a1", "pod-a2"},
+		},
+		{
+			name:        "zone spread only",
+			scorers:     []appsv1alpha1.CloneSetScaleDownScorer{{Type: appsv1alpha1.ZoneSpreadScaleDownScorer, Weight: 1}},
+			expectOrder: []string{"pod-a1", "pod-a2", "pod-b1", "pod-c1"},
+		},
+		{
+			name: "weighted zone spread and node bin-packing",
+			scorers: []appsv1alpha1.CloneSetScaleDownScorer{
+				{Type: appsv1alpha1.ZoneSpreadScaleDownScorer, Weight: 60},
+				{Type: appsv1alpha1.NodeBinPackingScaleDownScorer, Weight: 40},
+			},
+			expectOrder: []string{"pod-b1", "pod-a1", "pod-a2", "pod-c1"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			sortedPods := make([]*v1.Pod, len(pods))
+			copy(sortedPods, pods)
+			ranker := NewWeightedVictimRanker(sortedPods, &appsv1alpha1.CloneSetScaleDownPolicy{Scorers: tc.scorers}, reader)
+			sort.Stable(ActivePodsWithRanks{Pods: sortedPods, VictimRanker: ranker})
+
+			var gotOrder []string
+			for _, pod := range sortedPods {
+				gotOrder = append(gotOrder, pod.Name)
+			}
+			if !reflect.DeepEqual(gotOrder, tc.expectOrder) {
+				t.Fatalf("expected order %v, got %v", tc.expectOrder, gotOrder)
+			}
+		})
+	}
+}
+
+func TestWeightedVictimRankerExplain(t *testing.T) {
+	pods := []*v1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod-1", UID: "pod-1", Annotations: map[string]string{PodDeletionCost: "10"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod-2", UID: "pod-2"}},
+	}
+	policy := &appsv1alpha1.CloneSetScaleDownPolicy{Scorers: []appsv1alpha1.CloneSetScaleDownScorer{
+		{Type: appsv1alpha1.DeletionCostScaleDownScorer, Weight: 50},
+		{Type: "Unknown", Weight: 50},
+	}}
+	ranker := NewWeightedVictimRanker(pods, policy, fake.NewClientBuilder().Build())
+	if got, expected := ranker.Explain(pods[1]), "50.00 (DeletionCost=1.00*50)"; got != expected {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+	if got := ranker.GetRank(pods[0]); got != 0 {
+		t.Fatalf("expected rank 0, got %v", got)
+	}
+}
@@ -35,6 +35,7 @@ import (
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	intstrutil "k8s.io/apimachinery/pkg/util/intstr"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	clientset "k8s.io/client-go/kubernetes"
 	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
@@ -97,6 +98,10 @@ var (
 	// this is a short cut for any sub-functions to notify the reconcile how long to wait to requeue
 	durationStore = requeueduration.DurationStore{}
 
+	// failedPreDownloadNodes records the nodes reported with FailedPreDownloadImage events for the update revision
+	// of each DaemonSet, so that the nodes waiting to be updated are not reported again on every recheck.
+	failedPreDownloadNodes = &revisionNodesStore{}
+
 	isPreDownloadDisabled bool
 )
 
@@ -112,6 +117,12 @@ const (
 
 	// BackoffGCInterval is the time that has to pass before next iteration of backoff GC is run
 	BackoffGCInterval = 1 * time.Minute
+
+	// nodeImagePullCheckInterval is the interval to recheck the nodes waiting for images pre-downloaded
+	nodeImagePullCheckInterval = 5 * time.Second
+
+	// nodeDrainCheckInterval is the interval to recheck the draining nodes waiting for the other pods gone
+	nodeDrainCheckInterval = 10 * time.Second
 )
 
 // Reasons for DaemonSet events
@@ -305,6 +316,7 @@ type ReconcileDaemonSet struct {
 // +kubebuilder:rbac:groups=apps.kruise.io,resources=daemonsets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps.kruise.io,resources=daemonsets/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=apps.kruise.io,resources=daemonsets/finalizers,verbs=update
+// +kubebuilder:rbac:groups=apps.kruise.io,resources=nodeimages,verbs=get;list;watch
 
 // Reconcile reads that state of the cluster for a DaemonSet object and makes changes based on the state read
 // and what is in the DaemonSet.Spec
@@ -374,6 +386,7 @@ func (dsc *ReconcileDaemonSet) syncDaemonSet(ctx context.Context, request reconc
 		if errors.IsNotFound(err) {
 			klog.V(4).InfoS("DaemonSet has been deleted", "daemonSet", request)
 			dsc.expectations.DeleteExpectations(logger, dsKey)
+			failedPreDownloadNodes.delete(dsKey)
 			return nil
 		}
 		return fmt.Errorf("unable to retrieve DaemonSet %s from store: %v", dsKey, err)
@@ -561,7 +574,14 @@ func (dsc *ReconcileDaemonSet) updateDaemonSetStatus(ctx context.Context, ds *ap
 	}
 	numberUnavailable := desiredNumberScheduled - numberAvailable
 
-	err = dsc.storeDaemonSetStatus(ctx, ds, desiredNumberScheduled, currentNumberScheduled, numberMisscheduled, numberReady, updatedNumberScheduled, numberAvailable, numberUnavailable, updateObservedGen, hash)
+	var currentWave string
+	if waveIndex, _, err := dsc.getCurrentWave(ds, nodeList, hash, nodeToDaemonPods, now); err != nil {
+		return fmt.Errorf("couldn't get current wave for DaemonSet %q: %v", ds.Name, err)
+	} else if waveIndex >= 0 {
+		currentWave = ds.Spec.UpdateStrategy.RollingUpdate.Waves[waveIndex].Name
+	}
+
+	err = dsc.storeDaemonSetStatus(ctx, ds, desiredNumberScheduled, currentNumberScheduled, numberMisscheduled, numberReady, updatedNumberScheduled, numberAvailable, numberUnavailable, updateObservedGen, hash, currentWave)
 	if err != nil {
 		return fmt.Errorf("error storing status for DaemonSet %v: %v", ds.Name, err)
 	}
@@ -584,7 +604,8 @@ func (dsc *ReconcileDaemonSet) storeDaemonSetStatus(
 	numberAvailable,
 	numberUnavailable int,
 	updateObservedGen bool,
-	hash string) error {
+	hash string,
+	currentWave string) error {
 	if int(ds.Status.DesiredNumberScheduled) == desiredNumberScheduled &&
 		int(ds.Status.CurrentNumberScheduled) == currentNumberScheduled &&
 		int(ds.Status.NumberMisscheduled) == numberMisscheduled &&
@@ -593,7 +614,8 @@ func (dsc *ReconcileDaemonSet) storeDaemonSetStatus(
 		int(ds.Status.NumberAvailable) == numberAvailable &&
 		int(ds.Status.NumberUnavailable) == numberUnavailable &&
 		ds.Status.ObservedGeneration >= ds.Generation &&
-		ds.Status.UpdateRevision == hash {
+		ds.Status.UpdateRevision == hash &&
+		ds.Status.CurrentWave == currentWave {
 		return nil
 	}
 
@@ -612,6 +634,7 @@ func (dsc *ReconcileDaemonSet) storeDaemonSetStatus(
 		toUpdate.Status.NumberAvailable = int32(numberAvailable)
 		toUpdate.Status.NumberUnavailable = int32(numberUnavailable)
 		toUpdate.Status.UpdateRevision = hash
+		toUpdate.Status.CurrentWave = currentWave
 
 		if _, updateErr = dsClient.UpdateStatus(ctx, toUpdate, metav1.UpdateOptions{}); updateErr == nil {
 			klog.InfoS("Updated DaemonSet status", "daemonSet", klog.KObj(ds), "status", kruiseutil.DumpJSON(toUpdate.Status))
@@ -679,12 +702,13 @@ func (dsc *ReconcileDaemonSet) manage(ctx context.Context, ds *appsv1beta1.Daemo
 	}
 
 	// Label new pods using the hash label value of the current history when creating them
-	return dsc.syncNodes(ctx, ds, podsToDelete, nodesNeedingDaemonPods, hash)
+	return dsc.syncNodes(ctx, ds, podsToDelete, nodesNeedingDaemonPods, hash, nil)
 }
 
 // syncNodes deletes given pods and creates new daemon set pods on the given nodes
+// nodesWithoutHostPorts are the nodes where the new pods are surge pods that hand off the host ports from the old pods.
 // returns slice with errors if any
-func (dsc *ReconcileDaemonSet) syncNodes(ctx context.Context, ds *appsv1beta1.DaemonSet, podsToDelete, nodesNeedingDaemonPods []string, hash string) error {
+func (dsc *ReconcileDaemonSet) syncNodes(ctx context.Context, ds *appsv1beta1.DaemonSet, podsToDelete, nodesNeedingDaemonPods []string, hash string, nodesWithoutHostPorts sets.String) error {
 	if ds.Spec.Lifecycle != nil && ds.Spec.Lifecycle.PreDelete != nil {
 		var err error
 		podsToDelete, err = dsc.syncWithPreparingDelete(ds, podsToDelete)
@@ -761,7 +785,17 @@ func (dsc *ReconcileDaemonSet) syncNodes(ctx context.Context, ds *appsv1beta1.Da
 					podTemplate.Spec.NodeName = nodesNeedingDaemonPods[ix]
 				}
 
-				err = dsc.podControl.CreatePods(ctx, ds.Namespace, podTemplate, ds, metav1.NewControllerRef(ds, controllerKind))
+				// Advanced: patch the pod template by the node overrides
+				err = dsc.applyNodeOverrides(ds, nodesNeedingDaemonPods[ix], podTemplate)
+				if err == nil {
+					if nodesWithoutHostPorts.Has(nodesNeedingDaemonPods[ix]) && removeHostPorts(podTemplate) {
+						if podTemplate.Annotations == nil {
+							podTemplate.Annotations = map[string]string{}
+						}
+						podTemplate.Annotations[appsv1beta1.DaemonSetHostPortsRemovedAnnotation] = "true"
+					}
+					err = dsc.podControl.CreatePods(ctx, ds.Namespace, podTemplate, ds, metav1.NewControllerRef(ds, controllerKind))
+				}
 
 				if err != nil {
 					if errors.HasStatusCause(err, corev1.NamespaceTerminatingCause) {
@@ -854,6 +888,15 @@ func (dsc *ReconcileDaemonSet) podsShouldBeOnNode(
 	shouldRun, shouldContinueRunning := nodeShouldRunDaemonPod(node, ds)
 	daemonPods, exists := nodeToDaemonPods[node.Name]
 
+	// Advanced: keep the pods on the draining node until the other pods are gone
+	if !shouldContinueRunning && exists {
+		if waiting, err := dsc.hasOtherPodsOnDrainingNode(node, ds); err != nil || waiting {
+			klog.V(4).InfoS("DaemonSet kept pods on draining node for other pods", "daemonSet", klog.KObj(ds), "nodeName", node.Name, "err", err)
+			durationStore.Push(keyFunc(ds), nodeDrainCheckInterval)
+			shouldContinueRunning = true
+		}
+	}
+
 	switch {
 	case shouldRun && !exists:
 		// If daemon pod is supposed to be running on node, but isn't, create daemon pod.
@@ -924,8 +967,15 @@ func (dsc *ReconcileDaemonSet) podsShouldBeOnNode(
 		// deleted. If neither pod is ready, only the one matching the current hash revision is kept.
 		var oldestNewPod, oldestOldPod *corev1.Pod
 		sort.Sort(podByCreationTimestampAndPhase(daemonPodsRunning))
+		// Advanced: the pod without host ports is regarded as old once the pods of old revisions are gone
+		hasOldRevisionPods := false
 		for _, pod := range daemonPodsRunning {
-			if pod.Labels[apps.ControllerRevisionHashLabelKey] == hash {
+			if pod.Labels[apps.ControllerRevisionHashLabelKey] != hash {
+				hasOldRevisionPods = true
+			}
+		}
+		for _, pod := range daemonPodsRunning {
+			if pod.Labels[apps.ControllerRevisionHashLabelKey] == hash && (hasOldRevisionPods || !isHostPortsRemoved(pod)) {
 				if oldestNewPod == nil {
 					oldestNewPod = pod
 					continue
@@ -944,6 +994,9 @@ func (dsc *ReconcileDaemonSet) podsShouldBeOnNode(
 				klog.V(5).InfoS("Pod from DaemonSet is no longer ready and will be replaced with newer pod",
 					"daemonSet", klog.KObj(ds), "oldestOldPod", klog.KObj(oldestOldPod), "oldestNewPod", klog.KObj(oldestNewPod))
 				podsToDelete = append(podsToDelete, oldestOldPod.Name)
+			case ds.Spec.UpdateStrategy.RollingUpdate.HostPortHandoff && !isHostPortHandedOff(ds, oldestOldPod, dsc.failedPodsBackoff.Clock.Now()):
+				klog.V(5).InfoS("Pod from DaemonSet is waiting for older pod to hand off host ports",
+					"daemonSet", klog.KObj(ds), "oldestOldPod", klog.KObj(oldestOldPod), "oldestNewPod", klog.KObj(oldestNewPod))
 			case podutil.IsPodAvailable(oldestNewPod, ds.Spec.MinReadySeconds, metav1.Time{Time: dsc.failedPodsBackoff.Clock.Now()}):
 				klog.V(5).InfoS("Pod from DaemonSet is now ready and will replace older pod",
 					"daemonSet", klog.KObj(ds), "oldestOldPod", klog.KObj(oldestOldPod), "oldestNewPod", klog.KObj(oldestNewPod))
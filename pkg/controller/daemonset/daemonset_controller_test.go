@@ -128,8 +128,9 @@ func (f *fakePodControl) CreatePods(ctx context.Context, namespace string, templ
 
 	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
-			Labels:    template.Labels,
-			Namespace: namespace,
+			Labels:      template.Labels,
+			Annotations: template.Annotations,
+			Namespace:   namespace,
 		},
 	}
 
@@ -138,7 +138,7 @@ func Match(ds *appsv1beta1.DaemonSet, history *apps.ControllerRevision) (bool, e
 
 // getPatch returns a strategic merge patch that can be applied to restore a Daemonset to a
 // previous version. If the returned error is nil the patch is valid. The current state that we save is just the
-// PodSpecTemplate. We can modify this later to encompass more state (or less) and remain compatible with previously
+// PodSpecTemplate and the NodeOverrides. We can modify this later to encompass more state (or less) and remain compatible with previously
 // recorded patches.
 func getPatch(ds *appsv1beta1.DaemonSet) ([]byte, error) {
 	dsBytes, err := json.Marshal(ds)
@@ -158,6 +158,10 @@ func getPatch(ds *appsv1beta1.DaemonSet) ([]byte, error) {
 	template := spec["template"].(map[string]interface{})
 	specCopy["template"] = template
 	template["$patch"] = "replace"
+	// Advanced: the node overrides are also part of the revision
+	if nodeOverrides, ok := spec["nodeOverrides"]; ok {
+		specCopy["nodeOverrides"] = nodeOverrides
+	}
 	objCopy["spec"] = specCopy
 	patch, err := json.Marshal(objCopy)
 	return patch, err
@@ -179,7 +183,7 @@ func (dsc *ReconcileDaemonSet) snapshot(ctx context.Context, ds *appsv1beta1.Dae
 	if err != nil {
 		return nil, err
 	}
-	hash := kubecontroller.ComputeHash(&ds.Spec.Template, ds.Status.CollisionCount)
+	hash := computeRevisionHash(ds)
 	name := ds.Name + "-" + hash
 	history := &apps.ControllerRevision{
 		ObjectMeta: metav1.ObjectMeta{
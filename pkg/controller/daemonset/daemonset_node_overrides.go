@@ -0,0 +1,133 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package daemonset
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	kubecontroller "k8s.io/kubernetes/pkg/controller"
+	hashutil "k8s.io/kubernetes/pkg/util/hash"
+
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+)
+
+// applyNodeOverrides patches the pod template by the node overrides matching the node in order.
+func (dsc *ReconcileDaemonSet) applyNodeOverrides(ds *appsv1beta1.DaemonSet, nodeName string, template *corev1.PodTemplateSpec) error {
+	if len(ds.Spec.NodeOverrides) == 0 {
+		return nil
+	}
+	node, err := dsc.nodeLister.Get(nodeName)
+	if err != nil {
+		return err
+	}
+
+	for i := range ds.Spec.NodeOverrides {
+		override := &ds.Spec.NodeOverrides[i]
+		selector, err := metav1.LabelSelectorAsSelector(override.NodeSelector)
+		if err != nil {
+			return fmt.Errorf("invalid nodeSelector of nodeOverrides[%d]: %v", i, err)
+		}
+		if override.Patch.Raw == nil || !selector.Matches(labels.Set(node.Labels)) {
+			continue
+		}
+
+		templateBytes, err := json.Marshal(template)
+		if err != nil {
+			return err
+		}
+		modified, err := strategicpatch.StrategicMergePatch(templateBytes, override.Patch.Raw, &corev1.PodTemplateSpec{})
+		if err != nil {
+			return fmt.Errorf("failed to apply patch of nodeOverrides[%d]: %v", i, err)
+		}
+		newTemplate := &corev1.PodTemplateSpec{}
+		if err = json.Unmarshal(modified, newTemplate); err != nil {
+			return err
+		}
+		*template = *newTemplate
+	}
+	return nil
+}
+
+// hasInPlaceUpdatableNodeOverrides returns true if the node overrides on the node patch the fields that can be
+// updated in-place, including the metadata, and the images and resources of containers. The in-place update is
+// calculated from the revisions without the overrides, so it would revert these fields.
+func (dsc *ReconcileDaemonSet) hasInPlaceUpdatableNodeOverrides(ds *appsv1beta1.DaemonSet, nodeName string) (bool, error) {
+	if len(ds.Spec.NodeOverrides) == 0 {
+		return false, nil
+	}
+	template := ds.Spec.Template.DeepCopy()
+	if err := dsc.applyNodeOverrides(ds, nodeName, template); err != nil {
+		return false, err
+	}
+	if !equality.Semantic.DeepEqual(template.ObjectMeta, ds.Spec.Template.ObjectMeta) ||
+		len(template.Spec.Containers) != len(ds.Spec.Template.Spec.Containers) {
+		return true, nil
+	}
+	for i := range template.Spec.Containers {
+		c, origin := &template.Spec.Containers[i], &ds.Spec.Template.Spec.Containers[i]
+		if c.Name != origin.Name || c.Image != origin.Image || !equality.Semantic.DeepEqual(c.Resources, origin.Resources) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// computeRevisionHash returns the hash of the pod template and the node overrides.
+// The hash of DaemonSet without node overrides is the same as the upstream one.
+func computeRevisionHash(ds *appsv1beta1.DaemonSet) string {
+	if len(ds.Spec.NodeOverrides) == 0 {
+		return kubecontroller.ComputeHash(&ds.Spec.Template, ds.Status.CollisionCount)
+	}
+
+	hasher := fnv.New32a()
+	hashutil.DeepHashObject(hasher, ds.Spec.Template)
+	hashutil.DeepHashObject(hasher, ds.Spec.NodeOverrides)
+	if ds.Status.CollisionCount != nil {
+		collisionCountBytes := make([]byte, 8)
+		binary.LittleEndian.PutUint32(collisionCountBytes, uint32(*ds.Status.CollisionCount))
+		hasher.Write(collisionCountBytes)
+	}
+	return rand.SafeEncodeString(fmt.Sprint(hasher.Sum32()))
+}
+
+// isNodeOverridesChanged returns true if the node overrides recorded in the two revisions are different.
+func isNodeOverridesChanged(oldRevision, newRevision *apps.ControllerRevision) bool {
+	type revisionNodeOverrides struct {
+		Spec struct {
+			NodeOverrides json.RawMessage `json:"nodeOverrides,omitempty"`
+		} `json:"spec"`
+	}
+	var oldOverrides, newOverrides revisionNodeOverrides
+	if err := json.Unmarshal(oldRevision.Data.Raw, &oldOverrides); err != nil {
+		return true
+	}
+	if err := json.Unmarshal(newRevision.Data.Raw, &newOverrides); err != nil {
+		return true
+	}
+	return !bytes.Equal(oldOverrides.Spec.NodeOverrides, newOverrides.Spec.NodeOverrides)
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package daemonset
+
+import (
+	"reflect"
+	"testing"
+
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubecontroller "k8s.io/kubernetes/pkg/controller"
+
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+)
+
+func TestApplyNodeOverrides(t *testing.T) {
+	ds := newDaemonSet("foo")
+	ds.Spec.Template.Spec.Containers[0].Name = "foo"
+	ds.Spec.NodeOverrides = []appsv1beta1.DaemonSetNodeOverride{
+		{
+			NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "big"}},
+			Patch:        runtime.RawExtension{Raw: []byte(`{"spec":{"containers":[{"name":"foo","env":[{"name":"SIZE","value":"big"}]}]}}`)},
+		},
+		{
+			NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"gpu": "true"}},
+			Patch:        runtime.RawExtension{Raw: []byte(`{"metadata":{"labels":{"gpu":"true"}}}`)},
+		},
+	}
+	manager, _, _, err := newTestController(ds)
+	if err != nil {
+		t.Fatalf("error creating DaemonSets controller: %v", err)
+	}
+	manager.nodeStore.Add(newNode("big", map[string]string{"pool": "big", "gpu": "true"}))
+	manager.nodeStore.Add(newNode("small", nil))
+
+	template := ds.Spec.Template.DeepCopy()
+	if err := manager.applyNodeOverrides(ds, "big", template); err != nil {
+		t.Fatalf("failed to apply node overrides: %v", err)
+	}
+	expectedEnv := []corev1.EnvVar{{Name: "SIZE", Value: "big"}}
+	if !reflect.DeepEqual(template.Spec.Containers[0].Env, expectedEnv) || template.Labels["gpu"] != "true" {
+		t.Fatalf("expected all the overrides applied, got %v", template)
+	}
+	if template.Spec.Containers[0].Image != ds.Spec.Template.Spec.Containers[0].Image {
+		t.Fatalf("expected image kept, got %v", template.Spec.Containers[0].Image)
+	}
+
+	template = ds.Spec.Template.DeepCopy()
+	if err := manager.applyNodeOverrides(ds, "small", template); err != nil {
+		t.Fatalf("failed to apply node overrides: %v", err)
+	}
+	if !reflect.DeepEqual(template, &ds.Spec.Template) {
+		t.Fatalf("expected no override applied, got %v", template)
+	}
+}
+
+func TestRevisionWithNodeOverrides(t *testing.T) {
+	ds := newDaemonSet("foo")
+	if hash := computeRevisionHash(ds); hash != kubecontroller.ComputeHash(&ds.Spec.Template, ds.Status.CollisionCount) {
+		t.Fatalf("expected hash without node overrides unchanged, got %s", hash)
+	}
+	oldPatch, err := getPatch(ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldHash := computeRevisionHash(ds)
+
+	ds.Spec.NodeOverrides = []appsv1beta1.DaemonSetNodeOverride{{
+		NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "big"}},
+		Patch:        runtime.RawExtension{Raw: []byte(`{"metadata":{"labels":{"pool":"big"}}}`)},
+	}}
+	newPatch, err := getPatch(ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if computeRevisionHash(ds) == oldHash {
+		t.Fatalf("expected hash changed by node overrides")
+	}
+
+	oldRevision := &apps.ControllerRevision{Data: runtime.RawExtension{Raw: oldPatch}}
+	newRevision := &apps.ControllerRevision{Data: runtime.RawExtension{Raw: newPatch}}
+	if !isNodeOverridesChanged(oldRevision, newRevision) {
+		t.Fatalf("expected node overrides changed")
+	}
+	if isNodeOverridesChanged(newRevision, newRevision.DeepCopy()) {
+		t.Fatalf("expected node overrides unchanged")
+	}
+}
+
+func TestHasInPlaceUpdatableNodeOverrides(t *testing.T) {
+	ds := newDaemonSet("foo")
+	ds.Spec.Template.Spec.Containers[0].Name = "foo"
+	ds.Spec.NodeOverrides = []appsv1beta1.DaemonSetNodeOverride{
+		{
+			NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "big"}},
+			Patch:        runtime.RawExtension{Raw: []byte(`{"spec":{"containers":[{"name":"foo","env":[{"name":"SIZE","value":"big"}]}]}}`)},
+		},
+		{
+			NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"gpu": "true"}},
+			Patch:        runtime.RawExtension{Raw: []byte(`{"metadata":{"labels":{"gpu":"true"}}}`)},
+		},
+		{
+			NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"arch": "arm64"}},
+			Patch:        runtime.RawExtension{Raw: []byte(`{"spec":{"containers":[{"name":"foo","image":"foo/bar:arm64"}]}}`)},
+		},
+	}
+	manager, _, _, err := newTestController(ds)
+	if err != nil {
+		t.Fatalf("error creating DaemonSets controller: %v", err)
+	}
+	manager.nodeStore.Add(newNode("big", map[string]string{"pool": "big"}))
+	manager.nodeStore.Add(newNode("gpu", map[string]string{"gpu": "true"}))
+	manager.nodeStore.Add(newNode("arm", map[string]string{"arch": "arm64"}))
+	manager.nodeStore.Add(newNode("small", nil))
+
+	expected := map[string]bool{"big": false, "gpu": true, "arm": true, "small": false}
+	for nodeName, expectedTouched := range expected {
+		touched, err := manager.hasInPlaceUpdatableNodeOverrides(ds, nodeName)
+		if err != nil {
+			t.Fatalf("failed to check node overrides on %s: %v", nodeName, err)
+		}
+		if touched != expectedTouched {
+			t.Fatalf("expected in-place updatable overrides on %s to be %v, got %v", nodeName, expectedTouched, touched)
+		}
+	}
+}
@@ -19,13 +19,17 @@ package daemonset
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	apps "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	intstrutil "k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/controller/history"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -33,6 +37,7 @@ import (
 	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
 	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
 	clonesetutils "github.com/openkruise/kruise/pkg/controller/cloneset/utils"
+	daemonutil "github.com/openkruise/kruise/pkg/daemon/util"
 	imagejobutilfunc "github.com/openkruise/kruise/pkg/util/imagejob/utilfunction"
 	"github.com/openkruise/kruise/pkg/util/inplaceupdate"
 )
@@ -92,24 +97,35 @@ func (dsc *ReconcileDaemonSet) createImagePullJobsForInPlaceUpdate(ds *appsv1bet
 	containerImages := diffImagesBetweenRevisions(oldRevisions, updateRevision)
 	klog.V(3).InfoS("DaemonSet begin to create ImagePullJobs for revision",
 		"daemonSet", klog.KObj(ds), "revision", klog.KObj(updateRevision), "containerImageNames", containerImages)
+	var errs []error
 	for name, image := range containerImages {
-		// job name is revision name + container name, it can not be more than 255 characters
-		jobName := fmt.Sprintf("%s-%s", updateRevision.Name, name)
+		jobName := getImagePullJobName(updateRevision, name)
 		err := imagejobutilfunc.CreateJobForWorkload(dsc.Client, ds, controllerKind, jobName, image, labelMap, annotationMap, *selector, pullSecrets)
 		if err != nil {
 			if !errors.IsAlreadyExists(err) {
 				klog.ErrorS(err, "DaemonSet failed to create ImagePullJob", "daemonSet", klog.KObj(ds), "jobName", jobName)
 				dsc.eventRecorder.Eventf(ds, v1.EventTypeNormal, "FailedCreateImagePullJob", "failed to create ImagePullJob %s: %v", jobName, err)
+				errs = append(errs, err)
 			}
 			continue
 		}
 		klog.V(3).InfoS("DaemonSet created ImagePullJob for image", "daemonSet", klog.KObj(ds), "jobName", jobName, "image", image)
 		dsc.eventRecorder.Eventf(ds, v1.EventTypeNormal, "CreatedImagePullJob", "created ImagePullJob %s for image: %s", jobName, image)
 	}
+	// retry to create the jobs failed, before the revision is marked
+	if len(errs) > 0 {
+		return utilerrors.NewAggregate(errs)
+	}
 
 	return dsc.patchControllerRevisionLabels(updateRevision, appsv1alpha1.ImagePreDownloadCreatedKey, "true")
 }
 
+// getImagePullJobName returns the name of ImagePullJob for the container of revision.
+func getImagePullJobName(revision *apps.ControllerRevision, containerName string) string {
+	// job name is revision name + container name, it can not be more than 255 characters
+	return fmt.Sprintf("%s-%s", revision.Name, containerName)
+}
+
 func (dsc *ReconcileDaemonSet) patchControllerRevisionLabels(revision *apps.ControllerRevision, key, value string) error {
 	oldRevision := revision.ResourceVersion
 	newRevision := &apps.ControllerRevision{
@@ -167,3 +183,124 @@ func diffImagesBetweenRevisions(oldRevisions []*apps.ControllerRevision, newRevi
 	}
 	return containerImages
 }
+
+// isImagePreDownloadGateUpdate returns true if the pods should be updated only on the nodes
+// where the images of the update revision have been pre-downloaded.
+func (dsc *ReconcileDaemonSet) isImagePreDownloadGateUpdate(ds *appsv1beta1.DaemonSet, updateRevision *apps.ControllerRevision) bool {
+	if isPreDownloadDisabled || dsc.Client == nil || ds.Annotations[appsv1alpha1.ImagePreDownloadGateUpdateKey] != "true" {
+		return false
+	}
+	// no ImagePullJob has been created for the revision, so there is nothing to wait for
+	_, ok := updateRevision.Labels[appsv1alpha1.ImagePreDownloadCreatedKey]
+	return ok
+}
+
+// getImagesPreDownloading returns the images of the update revision whose ImagePullJobs are still running.
+// The images whose jobs have been completed or cleaned up are not waited for any more.
+func (dsc *ReconcileDaemonSet) getImagesPreDownloading(ds *appsv1beta1.DaemonSet, oldRevisions []*apps.ControllerRevision, updateRevision *apps.ControllerRevision) (map[string]string, error) {
+	images := make(map[string]string)
+	for name, image := range diffImagesBetweenRevisions(oldRevisions, updateRevision) {
+		job := &appsv1beta1.ImagePullJob{}
+		if err := dsc.Get(context.TODO(), types.NamespacedName{Namespace: ds.Namespace, Name: getImagePullJobName(updateRevision, name)}, job); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		if job.Status.CompletionTime != nil {
+			continue
+		}
+		images[name] = image
+	}
+	return images, nil
+}
+
+// isNodeImagesPullFinished returns true if the NodeImage of the node reports all the images pulled.
+// The images failed to pull or timed out are regarded as finished, so that the pod can still be updated.
+func (dsc *ReconcileDaemonSet) isNodeImagesPullFinished(ds *appsv1beta1.DaemonSet, hash, nodeName string, images map[string]string, now time.Time) (bool, error) {
+	nodeImage := &appsv1beta1.NodeImage{}
+	if err := dsc.Get(context.TODO(), types.NamespacedName{Name: nodeName}, nodeImage); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	timeout := time.Duration(imagejobutilfunc.GetPullTimeoutSeconds(ds)) * time.Second
+	var failures []string
+	for _, image := range images {
+		name, tag, err := daemonutil.NormalizeImageRefToNameTag(image)
+		if err != nil {
+			return false, err
+		}
+		imageStatus, ok := nodeImage.Status.ImageStatuses[name]
+		if !ok {
+			return false, nil
+		}
+		var finished bool
+		for _, tagStatus := range imageStatus.Tags {
+			if tagStatus.Tag != tag {
+				continue
+			}
+			switch {
+			case tagStatus.Phase == appsv1beta1.ImagePhaseSucceeded:
+				finished = true
+			case tagStatus.Phase == appsv1beta1.ImagePhaseFailed:
+				failures = append(failures, fmt.Sprintf("failed to pre-download image %s on node %s, update pod without waiting: %s", image, nodeName, tagStatus.Message))
+				finished = true
+			case tagStatus.StartTime != nil && now.After(tagStatus.StartTime.Add(timeout)):
+				failures = append(failures, fmt.Sprintf("timed out to pre-download image %s on node %s, update pod without waiting", image, nodeName))
+				finished = true
+			}
+			break
+		}
+		if !finished {
+			return false, nil
+		}
+	}
+
+	// the node may be rechecked many times before its pod is updated, so only report it the first time
+	if len(failures) > 0 && failedPreDownloadNodes.observe(keyFunc(ds), hash, nodeName) {
+		for _, msg := range failures {
+			dsc.eventRecorder.Event(ds, v1.EventTypeWarning, "FailedPreDownloadImage", msg)
+		}
+	}
+	return true, nil
+}
+
+// revisionNodesStore records the nodes of the update revision of each DaemonSet.
+type revisionNodesStore struct {
+	sync.Mutex
+	store map[string]*revisionNodes
+}
+
+type revisionNodes struct {
+	revision string
+	nodes    sets.Set[string]
+}
+
+// observe records the node for the revision of the DaemonSet, and returns false if it has already been recorded.
+// The nodes recorded for the previous revision are forgotten.
+func (s *revisionNodesStore) observe(key, revision, nodeName string) bool {
+	s.Lock()
+	defer s.Unlock()
+	if s.store == nil {
+		s.store = make(map[string]*revisionNodes)
+	}
+	rn, ok := s.store[key]
+	if !ok || rn.revision != revision {
+		rn = &revisionNodes{revision: revision, nodes: sets.New[string]()}
+		s.store[key] = rn
+	}
+	if rn.nodes.Has(nodeName) {
+		return false
+	}
+	rn.nodes.Insert(nodeName)
+	return true
+}
+
+func (s *revisionNodesStore) delete(key string) {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.store, key)
+}
@@ -22,6 +22,7 @@ import (
 	"sort"
 	"strconv"
 	"sync"
+	"time"
 
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -52,14 +53,37 @@ func (dsc *ReconcileDaemonSet) rollingUpdate(ctx context.Context, ds *appsv1beta
 		return fmt.Errorf("couldn't get unavailable numbers: %v", err)
 	}
 
+	now := dsc.failedPodsBackoff.Clock.Now()
+
+	// Advanced: only the nodes in the current wave can be updated, with the maxUnavailable of the wave
+	waveIndex, waveNodes, err := dsc.getCurrentWave(ds, nodeList, hash, nodeToDaemonPods, now)
+	if err != nil {
+		return fmt.Errorf("failed to get current wave: %v", err)
+	}
+	if waveIndex >= 0 && ds.Spec.UpdateStrategy.RollingUpdate.Waves[waveIndex].MaxUnavailable != nil {
+		maxUnavailable, err = intstrutil.GetScaledValueFromIntOrPercent(ds.Spec.UpdateStrategy.RollingUpdate.Waves[waveIndex].MaxUnavailable, waveNodes.Len(), true)
+		if err != nil {
+			return fmt.Errorf("invalid value for MaxUnavailable of wave: %v", err)
+		}
+		if maxUnavailable == 0 && maxSurge == 0 {
+			maxUnavailable = 1
+		}
+	}
+
+	// Advanced: only update the pods on nodes where the new images have been pre-downloaded
+	var imagesToPull map[string]string
+	if dsc.isImagePreDownloadGateUpdate(ds, curRevision) {
+		if imagesToPull, err = dsc.getImagesPreDownloading(ds, oldRevisions, curRevision); err != nil {
+			return fmt.Errorf("failed to get images pre-downloading: %v", err)
+		}
+	}
+
 	// Advanced: filter the pods updated, updating and can update, according to partition and selector
-	nodeToDaemonPods, err = dsc.filterDaemonPodsToUpdate(ds, nodeList, hash, nodeToDaemonPods)
+	nodeToDaemonPods, err = dsc.filterDaemonPodsToUpdate(ds, nodeList, hash, nodeToDaemonPods, waveNodes, imagesToPull)
 	if err != nil {
 		return fmt.Errorf("failed to filterDaemonPodsToUpdate: %v", err)
 	}
 
-	now := dsc.failedPodsBackoff.Clock.Now()
-
 	// When not surging, we delete just enough pods to stay under the maxUnavailable limit, if any
 	// are necessary, and let the core loop create new instances on those nodes.
 	//
@@ -146,7 +170,7 @@ func (dsc *ReconcileDaemonSet) rollingUpdate(ctx context.Context, ds *appsv1beta
 			}
 		}
 
-		return dsc.syncNodes(ctx, ds, oldPodsToDelete, nil, hash)
+		return dsc.syncNodes(ctx, ds, oldPodsToDelete, nil, hash, nil)
 	}
 
 	// When surging, we create new pods whenever an old pod is unavailable, and we can create up
@@ -166,6 +190,8 @@ func (dsc *ReconcileDaemonSet) rollingUpdate(ctx context.Context, ds *appsv1beta
 	var candidateNewNodes []string
 	var allowedNewNodes []string
 	var numSurge int
+	// Advanced: the surge pods are created without host ports, which are still held by the old pods
+	nodesWithoutHostPorts := sets.NewString()
 
 	for nodeName, pods := range nodeToDaemonPods {
 		newPod, oldPod, ok := findUpdatedPodsOnNode(ds, pods, hash)
@@ -179,6 +205,10 @@ func (dsc *ReconcileDaemonSet) rollingUpdate(ctx context.Context, ds *appsv1beta
 		case isPodNilOrPreDeleting(oldPod):
 			// we don't need to do anything to this node, the manage loop will handle it
 		case newPod == nil:
+			// the pod without host ports is replaced by the one with full template, which takes over the ports
+			if ds.Spec.UpdateStrategy.RollingUpdate.HostPortHandoff && !isHostPortsRemoved(oldPod) {
+				nodesWithoutHostPorts.Insert(nodeName)
+			}
 			// this is a surge candidate
 			switch {
 			case !podutil.IsPodAvailable(oldPod, ds.Spec.MinReadySeconds, metav1.Time{Time: now}):
@@ -211,6 +241,12 @@ func (dsc *ReconcileDaemonSet) rollingUpdate(ctx context.Context, ds *appsv1beta
 				numSurge++
 				continue
 			}
+			// Advanced: the old pod has to hand off its host ports before deleted
+			if ds.Spec.UpdateStrategy.RollingUpdate.HostPortHandoff && !isHostPortHandedOff(ds, oldPod, now) {
+				klog.V(5).InfoS("DaemonSet pod on node was waiting for old pod to hand off host ports", "daemonSet", klog.KObj(ds), "newPod", klog.KObj(newPod), "oldPod", klog.KObj(oldPod), "nodeName", nodeName)
+				numSurge++
+				continue
+			}
 			// we're available, delete the old pod
 			klog.V(5).InfoS("DaemonSet pod on node was available, removed old pod", "daemonSet", klog.KObj(ds), "newPod", klog.KObj(newPod), "oldPod", klog.KObj(oldPod), "nodeName", nodeName)
 			oldPodsToDelete = append(oldPodsToDelete, oldPod.Name)
@@ -229,7 +265,40 @@ func (dsc *ReconcileDaemonSet) rollingUpdate(ctx context.Context, ds *appsv1beta
 	}
 	newNodesToCreate := append(allowedNewNodes, candidateNewNodes[:remainingSurge]...)
 
-	return dsc.syncNodes(ctx, ds, oldPodsToDelete, newNodesToCreate, hash)
+	return dsc.syncNodes(ctx, ds, oldPodsToDelete, newNodesToCreate, hash, nodesWithoutHostPorts)
+}
+
+// isHostPortHandedOff returns true if the old pod has released its host ports to the surge pod,
+// or it is not available any more.
+func isHostPortHandedOff(ds *appsv1beta1.DaemonSet, oldPod *corev1.Pod, now time.Time) bool {
+	if oldPod.Labels[appsv1beta1.DaemonSetHostPortHandedOffLabel] == "true" {
+		return true
+	}
+	return !podutil.IsPodAvailable(oldPod, ds.Spec.MinReadySeconds, metav1.Time{Time: now})
+}
+
+// removeHostPorts removes the host ports from the pod template, so that the surge pod can be
+// scheduled onto the node where the old pod still holds the ports. It returns true if any port removed.
+// In host network, all the container ports are host ports.
+func removeHostPorts(template *corev1.PodTemplateSpec) bool {
+	var removed bool
+	for i := range template.Spec.Containers {
+		container := &template.Spec.Containers[i]
+		var ports []corev1.ContainerPort
+		for _, port := range container.Ports {
+			if port.HostPort == 0 && !template.Spec.HostNetwork {
+				ports = append(ports, port)
+			}
+		}
+		removed = removed || len(ports) != len(container.Ports)
+		container.Ports = ports
+	}
+	return removed
+}
+
+// isHostPortsRemoved returns true if the pod has been created without the host ports of template.
+func isHostPortsRemoved(pod *corev1.Pod) bool {
+	return pod.Annotations[appsv1beta1.DaemonSetHostPortsRemovedAnnotation] == "true"
 }
 
 // updatedDesiredNodeCounts calculates the true number of allowed unavailable or surge pods and
@@ -281,7 +350,7 @@ func GetTemplateGeneration(ds *appsv1beta1.DaemonSet) (*int64, error) {
 	return &generation, nil
 }
 
-func (dsc *ReconcileDaemonSet) filterDaemonPodsToUpdate(ds *appsv1beta1.DaemonSet, nodeList []*corev1.Node, hash string, nodeToDaemonPods map[string][]*corev1.Pod) (map[string][]*corev1.Pod, error) {
+func (dsc *ReconcileDaemonSet) filterDaemonPodsToUpdate(ds *appsv1beta1.DaemonSet, nodeList []*corev1.Node, hash string, nodeToDaemonPods map[string][]*corev1.Pod, waveNodes sets.String, imagesToPull map[string]string) (map[string][]*corev1.Pod, error) {
 	existingNodes := sets.NewString()
 	for _, node := range nodeList {
 		existingNodes.Insert(node.Name)
@@ -292,7 +361,7 @@ func (dsc *ReconcileDaemonSet) filterDaemonPodsToUpdate(ds *appsv1beta1.DaemonSe
 		}
 	}
 
-	nodeNames, err := dsc.filterDaemonPodsNodeToUpdate(ds, hash, nodeToDaemonPods)
+	nodeNames, err := dsc.filterDaemonPodsNodeToUpdate(ds, hash, nodeToDaemonPods, waveNodes, imagesToPull)
 	if err != nil {
 		return nil, err
 	}
@@ -304,21 +373,10 @@ func (dsc *ReconcileDaemonSet) filterDaemonPodsToUpdate(ds *appsv1beta1.DaemonSe
 	return ret, nil
 }
 
-func (dsc *ReconcileDaemonSet) filterDaemonPodsNodeToUpdate(ds *appsv1beta1.DaemonSet, hash string, nodeToDaemonPods map[string][]*corev1.Pod) ([]string, error) {
-	var err error
-	var partition int32
-	var selector labels.Selector
-	if ds.Spec.UpdateStrategy.RollingUpdate != nil && ds.Spec.UpdateStrategy.RollingUpdate.Partition != nil {
-		partitionInt, err := intstrutil.GetScaledValueFromIntOrPercent(ds.Spec.UpdateStrategy.RollingUpdate.Partition, int(ds.Status.DesiredNumberScheduled), false)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get partition value: %v", err)
-		}
-		partition = int32(partitionInt)
-	}
-	if ds.Spec.UpdateStrategy.RollingUpdate != nil && ds.Spec.UpdateStrategy.RollingUpdate.Selector != nil {
-		if selector, err = util.ValidatedLabelSelectorAsSelector(ds.Spec.UpdateStrategy.RollingUpdate.Selector); err != nil {
-			return nil, err
-		}
+func (dsc *ReconcileDaemonSet) filterDaemonPodsNodeToUpdate(ds *appsv1beta1.DaemonSet, hash string, nodeToDaemonPods map[string][]*corev1.Pod, waveNodes sets.String, imagesToPull map[string]string) ([]string, error) {
+	partition, selector, err := getUpdatePartitionAndSelector(ds)
+	if err != nil {
+		return nil, err
 	}
 
 	var allNodeNames []string
@@ -343,7 +401,10 @@ func (dsc *ReconcileDaemonSet) filterDaemonPodsNodeToUpdate(ds *appsv1beta1.Daem
 			updating = append(updating, nodeName)
 			continue
 		}
-
+		// the nodes out of the current wave wait for their turns
+		if waveNodes != nil && !waveNodes.Has(nodeName) {
+			continue
+		}
 		if selector != nil {
 			node, err := dsc.nodeLister.Get(nodeName)
 			if err != nil {
@@ -364,21 +425,128 @@ func (dsc *ReconcileDaemonSet) filterDaemonPodsNodeToUpdate(ds *appsv1beta1.Daem
 	} else {
 		sorted = append(sorted, rest...)
 	}
-	if maxUpdate := len(allNodeNames) - int(partition); maxUpdate <= 0 {
+	if maxUpdate := len(allNodeNames) - partition; maxUpdate <= 0 {
 		return nil, nil
 	} else if maxUpdate < len(sorted) {
 		sorted = sorted[:maxUpdate]
 	}
+
+	// the nodes selected to update wait for the new images to be pre-downloaded
+	if len(imagesToPull) > 0 {
+		now := dsc.failedPodsBackoff.Clock.Now()
+		numUpdated := len(updated) + len(updating)
+		for i := numUpdated; i < len(sorted); {
+			nodeName := sorted[i]
+			if finished, err := dsc.isNodeImagesPullFinished(ds, hash, nodeName, imagesToPull, now); err != nil {
+				return nil, err
+			} else if !finished {
+				klog.V(4).InfoS("DaemonSet was waiting for images pre-downloaded on node", "daemonSet", klog.KObj(ds), "nodeName", nodeName)
+				durationStore.Push(keyFunc(ds), nodeImagePullCheckInterval)
+				sorted = append(sorted[:i], sorted[i+1:]...)
+				continue
+			}
+			i++
+		}
+	}
 	return sorted, nil
 }
 
+// getUpdatePartitionAndSelector returns the partition and selector of rollingUpdate, which limit the nodes to update.
+func getUpdatePartitionAndSelector(ds *appsv1beta1.DaemonSet) (int, labels.Selector, error) {
+	var partition int
+	var selector labels.Selector
+	if ds.Spec.UpdateStrategy.RollingUpdate != nil && ds.Spec.UpdateStrategy.RollingUpdate.Partition != nil {
+		var err error
+		partition, err = intstrutil.GetScaledValueFromIntOrPercent(ds.Spec.UpdateStrategy.RollingUpdate.Partition, int(ds.Status.DesiredNumberScheduled), false)
+		if err != nil {
+			return 0, nil, fmt.Errorf("failed to get partition value: %v", err)
+		}
+	}
+	if ds.Spec.UpdateStrategy.RollingUpdate != nil && ds.Spec.UpdateStrategy.RollingUpdate.Selector != nil {
+		var err error
+		if selector, err = util.ValidatedLabelSelectorAsSelector(ds.Spec.UpdateStrategy.RollingUpdate.Selector); err != nil {
+			return 0, nil, err
+		}
+	}
+	return partition, selector, nil
+}
+
+// getCurrentWave returns the index of the first wave that has not been fully updated and available,
+// and the names of the nodes in it that should run the daemon pod. If all the waves have been completed,
+// it returns -1 and the nodes not selected by any wave. If there is no wave, the returned nodes are nil.
+// The old pods held back by selector or partition of rollingUpdate never block the waves.
+func (dsc *ReconcileDaemonSet) getCurrentWave(ds *appsv1beta1.DaemonSet, nodeList []*corev1.Node, hash string,
+	nodeToDaemonPods map[string][]*corev1.Pod, now time.Time) (int, sets.String, error) {
+	if ds.Spec.UpdateStrategy.RollingUpdate == nil || len(ds.Spec.UpdateStrategy.RollingUpdate.Waves) == 0 {
+		return -1, nil, nil
+	}
+	waves := ds.Spec.UpdateStrategy.RollingUpdate.Waves
+	selectors := make([]labels.Selector, len(waves))
+	for i := range waves {
+		var err error
+		if selectors[i], err = util.ValidatedLabelSelectorAsSelector(waves[i].NodeSelector); err != nil {
+			return -1, nil, err
+		}
+	}
+
+	partition, selector, err := getUpdatePartitionAndSelector(ds)
+	if err != nil {
+		return -1, nil, err
+	}
+
+	waveToNodes := make([]sets.String, len(waves)+1)
+	for i := range waveToNodes {
+		waveToNodes[i] = sets.NewString()
+	}
+	unselectedNodes := sets.NewString()
+	for _, node := range nodeList {
+		if shouldRun, _ := nodeShouldRunDaemonPod(node, ds); !shouldRun {
+			continue
+		}
+		if selector != nil && !selector.Matches(labels.Set(node.Labels)) {
+			unselectedNodes.Insert(node.Name)
+		}
+		i := 0
+		for ; i < len(waves); i++ {
+			if selectors[i].Matches(labels.Set(node.Labels)) {
+				break
+			}
+		}
+		// the last one contains the nodes not selected by any wave
+		waveToNodes[i].Insert(node.Name)
+	}
+
+	// the nodes updated or updating take up the quota of partition first, as filterDaemonPodsNodeToUpdate does
+	quota := len(nodeToDaemonPods) - partition
+	for _, pods := range nodeToDaemonPods {
+		if newPod, oldPod, ok := findUpdatedPodsOnNode(ds, pods, hash); !ok || newPod != nil || isPodNilOrPreDeleting(oldPod) {
+			quota--
+		}
+	}
+
+	for i := range waves {
+		for nodeName := range waveToNodes[i] {
+			newPod, oldPod, ok := findUpdatedPodsOnNode(ds, nodeToDaemonPods[nodeName], hash)
+			if ok && oldPod == nil && newPod != nil && podutil.IsPodAvailable(newPod, ds.Spec.MinReadySeconds, metav1.Time{Time: now}) {
+				continue
+			}
+			if ok && newPod == nil && !isPodNilOrPreDeleting(oldPod) && (unselectedNodes.Has(nodeName) || quota <= 0) {
+				// the old pod is held back by selector or partition
+				continue
+			}
+			return i, waveToNodes[i], nil
+		}
+	}
+	return -1, waveToNodes[len(waves)], nil
+}
+
 func getInPlaceUpdateOptions() *inplaceupdate.UpdateOptions {
 	return &inplaceupdate.UpdateOptions{GetRevision: func(rev *apps.ControllerRevision) string {
 		return rev.Labels[apps.DefaultDaemonSetUniqueLabelKey]
 	}}
 }
 
-func (dsc *ReconcileDaemonSet) canPodInPlaceUpdate(pod *corev1.Pod, curRevision *apps.ControllerRevision, oldRevisions []*apps.ControllerRevision) bool {
+func (dsc *ReconcileDaemonSet) canPodInPlaceUpdate(ds *appsv1beta1.DaemonSet, pod *corev1.Pod, curRevision *apps.ControllerRevision, oldRevisions []*apps.ControllerRevision) bool {
 	if !ContainsReadinessGate(pod) {
 		return false
 	}
@@ -389,7 +557,11 @@ func (dsc *ReconcileDaemonSet) canPodInPlaceUpdate(pod *corev1.Pod, curRevision
 			break
 		}
 	}
-	if oldRevision == nil {
+	if oldRevision == nil || isNodeOverridesChanged(oldRevision, curRevision) {
+		return false
+	}
+	// Advanced: the in-place update would revert the fields patched by the node overrides
+	if touched, err := dsc.hasInPlaceUpdatableNodeOverrides(ds, pod.Spec.NodeName); err != nil || touched {
 		return false
 	}
 	return dsc.inplaceControl.CanUpdateInPlace(oldRevision, curRevision, getInPlaceUpdateOptions())
@@ -399,7 +571,7 @@ func (dsc *ReconcileDaemonSet) inPlaceUpdatePods(ds *appsv1beta1.DaemonSet, podN
 	var podsToUpdate []*corev1.Pod
 	for _, name := range podNames {
 		pod, err := dsc.podLister.Pods(ds.Namespace).Get(name)
-		if err != nil || !dsc.canPodInPlaceUpdate(pod, curRevision, oldRevisions) {
+		if err != nil || !dsc.canPodInPlaceUpdate(ds, pod, curRevision, oldRevisions) {
 			podsNeedDelete = append(podsNeedDelete, name)
 			continue
 		}
@@ -26,13 +26,18 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/flowcontrol"
 	"k8s.io/klog/v2"
 	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
 	"k8s.io/kubernetes/pkg/controller/daemon/util"
 	testingclock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
 )
@@ -119,6 +124,195 @@ func TestDaemonSetUpdatesPodsWithMaxSurge(t *testing.T) {
 	expectSyncDaemonSets(t, manager, ds, podControl, 0, 0, 0)
 }
 
+func TestDaemonSetUpdatesPodsWithHostPortHandoff(t *testing.T) {
+	ds := newDaemonSet("foo")
+	ds.Spec.Template.Spec.Containers[0].Ports = []corev1.ContainerPort{{ContainerPort: 8080, HostPort: 8080}}
+	manager, podControl, _, err := newTestController(ds)
+	if err != nil {
+		t.Fatalf("error creating DaemonSets controller: %v", err)
+	}
+	addNodes(manager.nodeStore, 0, 2, nil)
+	manager.dsStore.Add(ds)
+	expectSyncDaemonSets(t, manager, ds, podControl, 2, 0, 0)
+	markPodsReady(podControl.podStore)
+
+	ds.Spec.Template.Spec.Containers[0].Image = "foo2/bar2"
+	ds.Spec.UpdateStrategy = newUpdateSurge(intstr.FromInt(2))
+	ds.Spec.UpdateStrategy.RollingUpdate.HostPortHandoff = true
+	manager.dsStore.Update(ds)
+
+	// the surge pods are created without host ports
+	clearExpectations(t, manager, ds, podControl)
+	expectSyncDaemonSets(t, manager, ds, podControl, 2, 0, 0)
+	for _, obj := range podControl.podStore.List() {
+		pod := obj.(*corev1.Pod)
+		if pod.Spec.Containers[0].Image == "foo2/bar2" && len(pod.Spec.Containers[0].Ports) != 0 {
+			t.Fatalf("expected surge pod without host ports, got %v", pod.Spec.Containers[0].Ports)
+		}
+	}
+	markPodsReady(podControl.podStore)
+
+	// the old pods wait to hand off host ports
+	clearExpectations(t, manager, ds, podControl)
+	expectSyncDaemonSets(t, manager, ds, podControl, 0, 0, 0)
+
+	for _, obj := range podControl.podStore.List() {
+		pod := obj.(*corev1.Pod)
+		if pod.Spec.Containers[0].Image != "foo2/bar2" {
+			pod.Labels[appsv1beta1.DaemonSetHostPortHandedOffLabel] = "true"
+		}
+	}
+	// the pods without host ports are replaced by the pods with full template once the old pods are gone
+	clearExpectations(t, manager, ds, podControl)
+	expectSyncDaemonSets(t, manager, ds, podControl, 2, 2, 0)
+	for _, obj := range podControl.podStore.List() {
+		pod := obj.(*corev1.Pod)
+		if !isHostPortsRemoved(pod) && len(pod.Spec.Containers[0].Ports) != 1 {
+			t.Fatalf("expected pod with host ports, got %v", pod.Spec.Containers[0].Ports)
+		}
+	}
+	markPodsReady(podControl.podStore)
+
+	// the pods without host ports wait to hand off host ports
+	clearExpectations(t, manager, ds, podControl)
+	expectSyncDaemonSets(t, manager, ds, podControl, 0, 0, 0)
+
+	for _, obj := range podControl.podStore.List() {
+		pod := obj.(*corev1.Pod)
+		if isHostPortsRemoved(pod) {
+			pod.Labels[appsv1beta1.DaemonSetHostPortHandedOffLabel] = "true"
+		}
+	}
+	clearExpectations(t, manager, ds, podControl)
+	expectSyncDaemonSets(t, manager, ds, podControl, 0, 2, 0)
+	clearExpectations(t, manager, ds, podControl)
+	expectSyncDaemonSets(t, manager, ds, podControl, 0, 0, 0)
+	for _, obj := range podControl.podStore.List() {
+		if pod := obj.(*corev1.Pod); isHostPortsRemoved(pod) {
+			t.Fatalf("expected no pod without host ports, got %s", pod.Name)
+		}
+	}
+}
+
+func TestDaemonSetUpdatesPodsInWaves(t *testing.T) {
+	ds := newDaemonSet("foo")
+	manager, podControl, _, err := newTestController(ds)
+	if err != nil {
+		t.Fatalf("error creating DaemonSets controller: %v", err)
+	}
+	addNodes(manager.nodeStore, 0, 2, map[string]string{"pool": "a"})
+	addNodes(manager.nodeStore, 2, 3, map[string]string{"pool": "b"})
+	manager.dsStore.Add(ds)
+	expectSyncDaemonSets(t, manager, ds, podControl, 5, 0, 0)
+	markPodsReady(podControl.podStore)
+
+	expectCurrentWave := func(wave string) {
+		t.Helper()
+		got, err := manager.kruiseClient.AppsV1beta1().DaemonSets(ds.Namespace).Get(context.TODO(), ds.Name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Status.CurrentWave != wave {
+			t.Fatalf("expected current wave %q, got %q", wave, got.Status.CurrentWave)
+		}
+	}
+
+	one, two := intstr.FromInt(1), intstr.FromInt(2)
+	ds.Spec.Template.Spec.Containers[0].Image = "foo2/bar2"
+	ds.Spec.UpdateStrategy = newStandardRollingUpdateStrategy(nil)
+	ds.Spec.UpdateStrategy.RollingUpdate.Waves = []appsv1beta1.RollingUpdateWave{
+		{Name: "pool-a", NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "a"}}, MaxUnavailable: &two},
+		{Name: "pool-b", NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "b"}}, MaxUnavailable: &one},
+	}
+	manager.dsStore.Update(ds)
+
+	// the first wave with maxUnavailable 2
+	clearExpectations(t, manager, ds, podControl)
+	expectSyncDaemonSets(t, manager, ds, podControl, 0, 2, 0)
+	clearExpectations(t, manager, ds, podControl)
+	expectSyncDaemonSets(t, manager, ds, podControl, 2, 0, 0)
+	expectCurrentWave("pool-a")
+	markPodsReady(podControl.podStore)
+
+	// the second wave with maxUnavailable 1
+	for i := 0; i < 3; i++ {
+		clearExpectations(t, manager, ds, podControl)
+		expectSyncDaemonSets(t, manager, ds, podControl, 0, 1, 0)
+		clearExpectations(t, manager, ds, podControl)
+		expectSyncDaemonSets(t, manager, ds, podControl, 1, 0, 0)
+		expectCurrentWave("pool-b")
+		markPodsReady(podControl.podStore)
+	}
+
+	clearExpectations(t, manager, ds, podControl)
+	expectSyncDaemonSets(t, manager, ds, podControl, 0, 0, 0)
+	expectCurrentWave("")
+}
+
+func TestDaemonSetUpdatesPodsInWavesWithPartitionAndSelector(t *testing.T) {
+	one := intstr.FromInt(1)
+	tests := []struct {
+		name      string
+		partition *intstr.IntOrString
+		selector  *metav1.LabelSelector
+		updates   []int
+	}{
+		{
+			name:      "the pods held back by partition do not block the waves",
+			partition: &one,
+			updates:   []int{1, 1, 1, 1},
+		},
+		{
+			name:     "the pods not selected do not block the waves",
+			selector: &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "b"}},
+			updates:  []int{1, 1, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := newDaemonSet("foo")
+			manager, podControl, _, err := newTestController(ds)
+			if err != nil {
+				t.Fatalf("error creating DaemonSets controller: %v", err)
+			}
+			addNodes(manager.nodeStore, 0, 2, map[string]string{"pool": "a"})
+			addNodes(manager.nodeStore, 2, 3, map[string]string{"pool": "b"})
+			manager.dsStore.Add(ds)
+			expectSyncDaemonSets(t, manager, ds, podControl, 5, 0, 0)
+			markPodsReady(podControl.podStore)
+
+			ds.Spec.Template.Spec.Containers[0].Image = "foo2/bar2"
+			ds.Spec.UpdateStrategy = newStandardRollingUpdateStrategy(nil)
+			ds.Spec.UpdateStrategy.RollingUpdate.Partition = tt.partition
+			ds.Spec.UpdateStrategy.RollingUpdate.Selector = tt.selector
+			ds.Spec.UpdateStrategy.RollingUpdate.Waves = []appsv1beta1.RollingUpdateWave{
+				{Name: "pool-a", NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "a"}}},
+				{Name: "pool-b", NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "b"}}},
+			}
+			ds.Status.DesiredNumberScheduled = 5
+			manager.dsStore.Update(ds)
+
+			for _, num := range tt.updates {
+				clearExpectations(t, manager, ds, podControl)
+				expectSyncDaemonSets(t, manager, ds, podControl, 0, num, 0)
+				clearExpectations(t, manager, ds, podControl)
+				expectSyncDaemonSets(t, manager, ds, podControl, num, 0, 0)
+				markPodsReady(podControl.podStore)
+			}
+
+			clearExpectations(t, manager, ds, podControl)
+			expectSyncDaemonSets(t, manager, ds, podControl, 0, 0, 0)
+			got, err := manager.kruiseClient.AppsV1beta1().DaemonSets(ds.Namespace).Get(context.TODO(), ds.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Status.CurrentWave != "" {
+				t.Fatalf("expected all the waves completed, got current wave %q", got.Status.CurrentWave)
+			}
+		})
+	}
+}
+
 func TestDaemonSetUpdatesWhenNewPosIsNotReady(t *testing.T) {
 	ds := newDaemonSet("foo")
 	manager, podControl, _, err := newTestController(ds)
@@ -872,7 +1066,7 @@ func TestFilterDaemonPodsNodeToUpdate(t *testing.T) {
 			Type:          appsv1beta1.RollingUpdateDaemonSetStrategyType,
 			RollingUpdate: test.rolling,
 		}}}
-		got, err := dsc.filterDaemonPodsNodeToUpdate(ds, test.hash, test.nodeToDaemonPods)
+		got, err := dsc.filterDaemonPodsNodeToUpdate(ds, test.hash, test.nodeToDaemonPods, nil, nil)
 		if err != nil {
 			t.Fatalf("failed to call filterDaemonPodsNodeToUpdate: %v", err)
 		}
@@ -887,3 +1081,160 @@ func TestFilterDaemonPodsNodeToUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestFilterDaemonPodsNodeToUpdateWithImagesToPull(t *testing.T) {
+	pulled := appsv1beta1.NodeImageStatus{ImageStatuses: map[string]appsv1beta1.ImageStatus{
+		"nginx": {Tags: []appsv1beta1.ImageTagStatus{{Tag: "1.26", Phase: appsv1beta1.ImagePhaseSucceeded}}},
+	}}
+	nodeToDaemonPods := map[string][]*corev1.Pod{}
+	var objs []client.Object
+	for _, name := range []string{"n1", "n2", "n3"} {
+		nodeToDaemonPods[name] = []*corev1.Pod{
+			{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{apps.DefaultDaemonSetUniqueLabelKey: "v1"}}},
+		}
+		nodeImage := &appsv1beta1.NodeImage{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if name != "n3" {
+			nodeImage.Status = pulled
+		}
+		objs = append(objs, nodeImage)
+	}
+	dsc := &ReconcileDaemonSet{
+		Client:            fake.NewClientBuilder().WithObjects(objs...).Build(),
+		failedPodsBackoff: flowcontrol.NewFakeBackOff(time.Second, time.Minute, testingclock.NewFakeClock(time.Now())),
+	}
+	ds := newDaemonSet("foo")
+	ds.Spec.UpdateStrategy = appsv1beta1.DaemonSetUpdateStrategy{
+		Type: appsv1beta1.RollingUpdateDaemonSetStrategyType,
+		RollingUpdate: &appsv1beta1.RollingUpdateDaemonSet{
+			Type:      appsv1beta1.StandardRollingUpdateType,
+			Partition: &intstr.IntOrString{Type: intstr.Int, IntVal: 1},
+		},
+	}
+
+	// n3 and n2 are selected by partition, and only n2 has pulled the images
+	got, err := dsc.filterDaemonPodsNodeToUpdate(ds, "v2", nodeToDaemonPods, nil, map[string]string{"c0": "nginx:1.26"})
+	if err != nil {
+		t.Fatalf("failed to call filterDaemonPodsNodeToUpdate: %v", err)
+	}
+	if expected := []string{"n2"}; !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestIsNodeImagesPullFinished(t *testing.T) {
+	now := time.Now()
+	nodeImage := &appsv1beta1.NodeImage{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-0"},
+		Status: appsv1beta1.NodeImageStatus{
+			ImageStatuses: map[string]appsv1beta1.ImageStatus{
+				"nginx": {Tags: []appsv1beta1.ImageTagStatus{
+					{Tag: "1.25", Phase: appsv1beta1.ImagePhaseSucceeded},
+					{Tag: "1.26", Phase: appsv1beta1.ImagePhasePulling, StartTime: &metav1.Time{Time: now.Add(-time.Minute)}},
+					{Tag: "1.27", Phase: appsv1beta1.ImagePhaseFailed},
+					{Tag: "1.28", Phase: appsv1beta1.ImagePhasePulling, StartTime: &metav1.Time{Time: now.Add(-10 * time.Minute)}},
+				}},
+			},
+		},
+	}
+	dsc := &ReconcileDaemonSet{
+		Client:        fake.NewClientBuilder().WithObjects(nodeImage).Build(),
+		eventRecorder: record.NewFakeRecorder(10),
+	}
+	ds := newDaemonSet("foo")
+
+	tests := []struct {
+		name     string
+		nodeName string
+		images   map[string]string
+		expected bool
+	}{
+		{
+			name:     "image pulled",
+			nodeName: "node-0",
+			images:   map[string]string{"c0": "nginx:1.25"},
+			expected: true,
+		},
+		{
+			name:     "image pulling",
+			nodeName: "node-0",
+			images:   map[string]string{"c0": "nginx:1.25", "c1": "nginx:1.26"},
+			expected: false,
+		},
+		{
+			name:     "image failed to pull",
+			nodeName: "node-0",
+			images:   map[string]string{"c0": "nginx:1.25", "c1": "nginx:1.27"},
+			expected: true,
+		},
+		{
+			name:     "image timed out to pull",
+			nodeName: "node-0",
+			images:   map[string]string{"c0": "nginx:1.28"},
+			expected: true,
+		},
+		{
+			name:     "image not found",
+			nodeName: "node-0",
+			images:   map[string]string{"c0": "busybox:latest"},
+			expected: false,
+		},
+		{
+			name:     "node image not found",
+			nodeName: "node-1",
+			images:   map[string]string{"c0": "nginx:1.25"},
+			expected: false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			finished, err := dsc.isNodeImagesPullFinished(ds, "v1", test.nodeName, test.images, now)
+			if err != nil {
+				t.Fatalf("failed to check images pulled: %v", err)
+			}
+			if finished != test.expected {
+				t.Fatalf("expected %v, got %v", test.expected, finished)
+			}
+		})
+	}
+
+	// the failures are reported once per node and revision
+	failedPreDownloadNodes.delete(keyFunc(ds))
+	recorder := record.NewFakeRecorder(10)
+	dsc.eventRecorder = recorder
+	images := map[string]string{"c0": "nginx:1.27"}
+	for _, hash := range []string{"v1", "v1", "v2"} {
+		if finished, err := dsc.isNodeImagesPullFinished(ds, hash, "node-0", images, now); err != nil || !finished {
+			t.Fatalf("expected finished, got %v, %v", finished, err)
+		}
+	}
+	if len(recorder.Events) != 2 {
+		t.Fatalf("expected 2 FailedPreDownloadImage events, got %d", len(recorder.Events))
+	}
+}
+
+func TestGetImagesPreDownloading(t *testing.T) {
+	ds := newDaemonSet("foo")
+	oldRevision := &apps.ControllerRevision{
+		Data: runtime.RawExtension{Raw: []byte(`{"spec":{"template":{"spec":{"containers":[{"name":"a","image":"nginx:1.25"},{"name":"b","image":"busybox:1.0"},{"name":"c","image":"redis:6"}]}}}}`)},
+	}
+	updateRevision := &apps.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo-v2"},
+		Data:       runtime.RawExtension{Raw: []byte(`{"spec":{"template":{"spec":{"containers":[{"name":"a","image":"nginx:1.26"},{"name":"b","image":"busybox:1.1"},{"name":"c","image":"redis:7"}]}}}}`)},
+	}
+	runningJob := &appsv1beta1.ImagePullJob{ObjectMeta: metav1.ObjectMeta{Namespace: ds.Namespace, Name: "foo-v2-a"}}
+	completedJob := &appsv1beta1.ImagePullJob{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ds.Namespace, Name: "foo-v2-b"},
+		Status:     appsv1beta1.ImagePullJobStatus{CompletionTime: &metav1.Time{Time: time.Now()}},
+	}
+	dsc := &ReconcileDaemonSet{Client: fake.NewClientBuilder().WithObjects(runningJob, completedJob).Build()}
+
+	// the job of container c has been cleaned up
+	images, err := dsc.getImagesPreDownloading(ds, []*apps.ControllerRevision{oldRevision}, updateRevision)
+	if err != nil {
+		t.Fatalf("failed to get images pre-downloading: %v", err)
+	}
+	expected := map[string]string{"a": "nginx:1.26"}
+	if !reflect.DeepEqual(images, expected) {
+		t.Fatalf("expected %v, got %v", expected, images)
+	}
+}
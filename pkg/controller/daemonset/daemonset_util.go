@@ -17,6 +17,7 @@ limitations under the License.
 package daemonset
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"sync"
@@ -32,10 +33,13 @@ import (
 	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
 	"k8s.io/kubernetes/pkg/controller/daemon/util"
 	"k8s.io/utils/integer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appspub "github.com/openkruise/kruise/apis/apps/pub"
 	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
 	kruiseutil "github.com/openkruise/kruise/pkg/util"
+	utilclient "github.com/openkruise/kruise/pkg/util/client"
+	"github.com/openkruise/kruise/pkg/util/fieldindex"
 	"github.com/openkruise/kruise/pkg/util/inplaceupdate"
 	"github.com/openkruise/kruise/pkg/util/lifecycle"
 )
@@ -112,15 +116,66 @@ func nodeShouldRunDaemonPod(node *corev1.Node, ds *appsv1beta1.DaemonSet) (bool,
 		return false, false
 	}
 
+	shouldRun, shouldContinueRunning := true, true
 	if !fitsTaints {
 		// Scheduled daemon pods should continue running if they tolerate NoExecute taint.
 		_, hasUntoleratedTaint := v1helper.FindMatchingUntoleratedTaint(taints, pod.Spec.Tolerations, func(t *corev1.Taint) bool {
 			return t.Effect == corev1.TaintEffectNoExecute
 		})
-		return false, !hasUntoleratedTaint
+		shouldRun, shouldContinueRunning = false, !hasUntoleratedTaint
 	}
 
-	return true, true
+	// Advanced: no new pod on the draining node, and the running pod is evicted if required
+	if isNodeDraining(node, ds) {
+		shouldRun = false
+		shouldContinueRunning = shouldContinueRunning && !ds.Spec.NodeDrainPolicy.EvictPods
+	}
+	return shouldRun, shouldContinueRunning
+}
+
+// isNodeDraining returns true if the node is cordoned or tainted by the drain taints of the DaemonSet.
+func isNodeDraining(node *corev1.Node, ds *appsv1beta1.DaemonSet) bool {
+	policy := ds.Spec.NodeDrainPolicy
+	if policy == nil {
+		return false
+	}
+	if node.Spec.Unschedulable {
+		return true
+	}
+	for _, taint := range node.Spec.Taints {
+		for _, key := range policy.Taints {
+			if taint.Key == key {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasOtherPodsOnDrainingNode returns true if the pods of DaemonSet should wait for the other pods
+// on the draining node to be gone before evicted.
+func (dsc *ReconcileDaemonSet) hasOtherPodsOnDrainingNode(node *corev1.Node, ds *appsv1beta1.DaemonSet) (bool, error) {
+	if !isNodeDraining(node, ds) || !ds.Spec.NodeDrainPolicy.WaitForOtherPods {
+		return false, nil
+	}
+	podList := &corev1.PodList{}
+	if err := dsc.List(context.TODO(), podList, client.MatchingFields{fieldindex.IndexNameForPodNodeName: node.Name}, utilclient.DisableDeepCopy); err != nil {
+		return false, err
+	}
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if _, isMirrorPod := pod.Annotations[corev1.MirrorPodAnnotationKey]; isMirrorPod {
+			continue
+		}
+		if owner := metav1.GetControllerOf(pod); owner != nil && owner.Kind == "DaemonSet" {
+			continue
+		}
+		return true, nil
+	}
+	return false, nil
 }
 
 func shouldIgnoreNodeUpdate(oldNode, curNode corev1.Node) bool {
@@ -311,6 +366,7 @@ func getUnscheduledPodsWithoutNode(runningNodesList []*corev1.Node, nodeToDaemon
 // processing the particular node in those scenarios and let the manage loop prune the
 // excess pods for our next time around.
 func findUpdatedPodsOnNode(ds *appsv1beta1.DaemonSet, podsOnNode []*corev1.Pod, hash string) (newPod, oldPod *corev1.Pod, ok bool) {
+	var hostPortsRemovedPod *corev1.Pod
 	for _, pod := range podsOnNode {
 		if pod.DeletionTimestamp != nil {
 			continue
@@ -320,6 +376,13 @@ func findUpdatedPodsOnNode(ds *appsv1beta1.DaemonSet, podsOnNode []*corev1.Pod,
 			generation = nil
 		}
 		if util.IsPodUpdated(pod, hash, generation) {
+			if isHostPortsRemoved(pod) {
+				if hostPortsRemovedPod != nil {
+					return nil, nil, false
+				}
+				hostPortsRemovedPod = pod
+				continue
+			}
 			if newPod != nil {
 				return nil, nil, false
 			}
@@ -331,6 +394,18 @@ func findUpdatedPodsOnNode(ds *appsv1beta1.DaemonSet, podsOnNode []*corev1.Pod,
 			oldPod = pod
 		}
 	}
+	// Advanced: the pod without host ports is new until the old pod is gone, and then it has to be replaced
+	// by the pod with full template
+	if hostPortsRemovedPod != nil {
+		switch {
+		case oldPod == nil:
+			oldPod = hostPortsRemovedPod
+		case newPod == nil:
+			newPod = hostPortsRemovedPod
+		default:
+			return nil, nil, false
+		}
+	}
 	return newPod, oldPod, true
 }
 
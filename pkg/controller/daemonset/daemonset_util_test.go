@@ -17,6 +17,7 @@ limitations under the License.
 package daemonset
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"testing"
@@ -31,8 +32,11 @@ import (
 	kubecontroller "k8s.io/kubernetes/pkg/controller"
 	"k8s.io/kubernetes/pkg/securitycontext"
 	labelsutil "k8s.io/kubernetes/pkg/util/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	"github.com/openkruise/kruise/pkg/util/fieldindex"
 )
 
 func Test_nodeInSameCondition(t *testing.T) {
@@ -719,3 +723,49 @@ func newStandardRollingUpdateStrategy(matchLabels map[string]string) appsv1beta1
 	}
 	return strategy
 }
+
+func TestDaemonSetOnDrainingNodes(t *testing.T) {
+	ds := newDaemonSet("foo")
+	ds.Spec.NodeDrainPolicy = &appsv1beta1.DaemonSetNodeDrainPolicy{
+		Taints:           []string{"node.kubernetes.io/draining"},
+		EvictPods:        true,
+		WaitForOtherPods: true,
+	}
+	manager, podControl, _, err := newTestController(ds)
+	if err != nil {
+		t.Fatalf("error creating DaemonSets controller: %v", err)
+	}
+	// the client is only used to list the other pods on the node here
+	isPreDownloadDisabled = true
+	defer func() { isPreDownloadDisabled = false }()
+	manager.Client = fake.NewClientBuilder().
+		WithIndex(&corev1.Pod{}, fieldindex.IndexNameForPodNodeName, func(obj client.Object) []string {
+			return []string{obj.(*corev1.Pod).Spec.NodeName}
+		}).Build()
+	addNodes(manager.nodeStore, 0, 2, nil)
+	// no pod is created on the draining node
+	tainted := newNode("node-2", nil)
+	tainted.Spec.Taints = []corev1.Taint{{Key: "node.kubernetes.io/draining", Effect: corev1.TaintEffectPreferNoSchedule}}
+	manager.nodeStore.Add(tainted)
+	manager.dsStore.Add(ds)
+	expectSyncDaemonSets(t, manager, ds, podControl, 2, 0, 0)
+	markPodsReady(podControl.podStore)
+
+	// the pod on the cordoned node waits for the other pods
+	cordoned := newNode("node-0", nil)
+	cordoned.Spec.Unschedulable = true
+	manager.nodeStore.Update(cordoned)
+	otherPod := newPod("other", "node-0", nil, nil)
+	if err := manager.Client.Create(context.TODO(), otherPod); err != nil {
+		t.Fatalf("failed to create pod: %v", err)
+	}
+	clearExpectations(t, manager, ds, podControl)
+	expectSyncDaemonSets(t, manager, ds, podControl, 0, 0, 0)
+
+	// the pod is evicted after the other pods gone
+	if err := manager.Client.Delete(context.TODO(), otherPod); err != nil {
+		t.Fatalf("failed to delete pod: %v", err)
+	}
+	clearExpectations(t, manager, ds, podControl)
+	expectSyncDaemonSets(t, manager, ds, podControl, 0, 1, 0)
+}
@@ -165,11 +165,37 @@ func TestUpdateHotUpgradeSidecar(t *testing.T) {
 			pods[0].Status.ContainerStatuses[1].ImageID = hotUpgradeEmptyImageID
 		},
 	}
-	testUpdateHotUpgradeSidecar(t, hotUpgradeEmptyImage, sidecarSetInput, handlers)
+	testUpdateHotUpgradeSidecar(t, podHotUpgrade.DeepCopy(), hotUpgradeEmptyImage, sidecarSetInput, handlers)
 }
 
-func testUpdateHotUpgradeSidecar(t *testing.T, hotUpgradeEmptyImage string, sidecarSetInput *appsv1beta1.SidecarSet, handlers map[string]HandlePod) {
+func TestUpdateNativeSidecarHotUpgrade(t *testing.T) {
+	// the hot upgrade sidecar containers are native sidecar initContainers
+	sidecarSetInput := sidecarSetHotUpgrade.DeepCopy()
+	sidecarSetInput.Spec.InitContainers = sidecarSetInput.Spec.Containers
+	sidecarSetInput.Spec.InitContainers[0].RestartPolicy = ptr.To(corev1.ContainerRestartPolicyAlways)
+	sidecarSetInput.Spec.Containers = nil
 	podInput := podHotUpgrade.DeepCopy()
+	podInput.Spec.InitContainers = podInput.Spec.Containers[1:]
+	podInput.Spec.Containers = podInput.Spec.Containers[:1]
+	for i := range podInput.Spec.InitContainers {
+		podInput.Spec.InitContainers[i].RestartPolicy = ptr.To(corev1.ContainerRestartPolicyAlways)
+	}
+	podInput.Status.InitContainerStatuses = podInput.Status.ContainerStatuses[1:]
+	podInput.Status.ContainerStatuses = podInput.Status.ContainerStatuses[:1]
+	handlers := map[string]HandlePod{
+		"test-sidecar-2 container is upgrading": func(pods []*corev1.Pod) {
+			pods[0].Status.InitContainerStatuses[1].Image = "test-image:v2"
+			pods[0].Status.InitContainerStatuses[1].ImageID = testImageV2ImageID
+		},
+		"test-sidecar-2 container upgrade complete, and reset test-sidecar-1 empty image": func(pods []*corev1.Pod) {
+			pods[0].Status.InitContainerStatuses[0].Image = hotUpgradeEmptyImage
+			pods[0].Status.InitContainerStatuses[0].ImageID = hotUpgradeEmptyImageID
+		},
+	}
+	testUpdateHotUpgradeSidecar(t, podInput, hotUpgradeEmptyImage, sidecarSetInput, handlers)
+}
+
+func testUpdateHotUpgradeSidecar(t *testing.T, podInput *corev1.Pod, hotUpgradeEmptyImage string, sidecarSetInput *appsv1beta1.SidecarSet, handlers map[string]HandlePod) {
 	podInput.Name = "liheng-test"
 	cases := []struct {
 		name          string
@@ -260,7 +286,7 @@ func testUpdateHotUpgradeSidecar(t *testing.T, hotUpgradeEmptyImage string, side
 			}
 			podInput = podOutput.DeepCopy()
 			for cName, infos := range cs.expectedInfo {
-				sidecarContainer := util.GetPodContainerByName(cName, podOutput)
+				sidecarContainer := util.GetContainer(cName, podOutput)
 				if infos[0] != sidecarContainer.Image {
 					t.Fatalf("expect pod(%s) container(%s) image(%s), but get image(%s)", pod.Name, sidecarContainer.Name, infos[0], sidecarContainer.Image)
 				}
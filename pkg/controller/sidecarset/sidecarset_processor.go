@@ -88,6 +88,13 @@ func (p *Processor) UpdateSidecarSet(sidecarSet *appsv1beta1.SidecarSet) (reconc
 
 	// 2. calculate SidecarSet status based on pod and revision information
 	status := calculateStatus(control, pods, latestRevision, collisionCount)
+	// only the pods of current rollout phase can be updated
+	phasePods, phase, phaseRequeueAfter, err := p.getRolloutPhasePods(control, pods)
+	if err != nil {
+		klog.ErrorS(err, "SidecarSet get rollout phase error", "sidecarSet", klog.KObj(sidecarSet))
+		return reconcile.Result{}, err
+	}
+	status.RolloutPhase = phase
 	// update sidecarSet status in store
 	if err := p.updateSidecarSetStatus(sidecarSet, status); err != nil {
 		return reconcile.Result{}, err
@@ -131,7 +138,7 @@ func (p *Processor) UpdateSidecarSet(sidecarSet *appsv1beta1.SidecarSet) (reconc
 
 			// don't contain sidecar empty containers
 			sidecarContainers := sidecarcontrol.GetSidecarContainersInPod(sidecarSet)
-			for _, sidecarContainer := range sidecarSet.Spec.Containers {
+			for _, sidecarContainer := range sidecarcontrol.GetSidecarSetContainersWithNativeSidecars(sidecarSet) {
 				if sidecarcontrol.IsHotUpgradeContainer(&sidecarContainer) {
 					_, emptyContainer := sidecarcontrol.GetPodHotUpgradeContainers(sidecarContainer.Name, pod)
 					sidecarContainers.Delete(emptyContainer)
@@ -157,10 +164,10 @@ func (p *Processor) UpdateSidecarSet(sidecarSet *appsv1beta1.SidecarSet) (reconc
 	}
 
 	// 7. upgrade pod sidecar
-	if err := p.updatePods(control, pods); err != nil {
+	if err := p.updatePods(control, phasePods); err != nil {
 		return reconcile.Result{}, err
 	}
-	return reconcile.Result{}, nil
+	return reconcile.Result{RequeueAfter: phaseRequeueAfter}, nil
 }
 
 func (p *Processor) updatePods(control sidecarcontrol.SidecarControl, pods []*corev1.Pod) error {
@@ -559,9 +566,9 @@ func isSidecarSetNotUpdate(s *appsv1beta1.SidecarSet) bool {
 }
 
 func updateContainerInPod(container corev1.Container, pod *corev1.Pod) {
-	for i := range pod.Spec.Containers {
-		if pod.Spec.Containers[i].Name == container.Name {
-			pod.Spec.Containers[i] = container
+	for _, c := range sidecarcontrol.GetPodContainersWithNativeSidecars(pod) {
+		if c.Name == container.Name {
+			*c = container
 			return
 		}
 	}
@@ -572,8 +579,7 @@ func updatePodSidecarContainer(control sidecarcontrol.SidecarControl, pod *corev
 
 	// upgrade sidecar containers
 	var changedContainers []string
-	for _, sidecarContainer := range sidecarSet.Spec.Containers {
-		// sidecarContainer := &sidecarset.Spec.Containers[i]
+	for _, sidecarContainer := range sidecarcontrol.GetSidecarSetContainersWithNativeSidecars(sidecarSet) {
 		// volumeMounts that injected into sidecar container
 		// when volumeMounts SubPathExpr contains expansions, then need copy container EnvVars(injectEnvs)
 		injectedMounts, injectedEnvs := sidecarcontrol.GetInjectedVolumeMountsAndEnvs(control, &sidecarContainer, pod)
@@ -637,6 +643,7 @@ func inconsistentStatus(sidecarSet *appsv1beta1.SidecarSet, status *appsv1beta1.
 		status.ReadyPods != sidecarSet.Status.ReadyPods ||
 		status.UpdatedReadyPods != sidecarSet.Status.UpdatedReadyPods ||
 		status.LatestRevision != sidecarSet.Status.LatestRevision ||
+		status.RolloutPhase != sidecarSet.Status.RolloutPhase ||
 		!pointer.Int32Equal(sidecarSet.Status.CollisionCount, status.CollisionCount)
 }
 
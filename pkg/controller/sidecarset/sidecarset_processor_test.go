@@ -29,6 +29,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/kubernetes/pkg/controller/history"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
@@ -71,6 +72,35 @@ func TestUpdateColdUpgradeSidecar(t *testing.T) {
 	testUpdateColdUpgradeSidecar(t, podInput, sidecarSetInput, handlers)
 }
 
+func TestUpdateNativeSidecarColdUpgrade(t *testing.T) {
+	// the sidecar container is a native sidecar initContainer
+	sidecarSetInput := sidecarSetDemo.DeepCopy()
+	sidecarSetInput.Spec.InitContainers = sidecarSetInput.Spec.Containers
+	sidecarSetInput.Spec.InitContainers[0].RestartPolicy = ptr.To(corev1.ContainerRestartPolicyAlways)
+	sidecarSetInput.Spec.Containers = nil
+	podInput := podDemo.DeepCopy()
+	podInput.Spec.InitContainers = podInput.Spec.Containers[1:]
+	podInput.Spec.InitContainers[0].RestartPolicy = ptr.To(corev1.ContainerRestartPolicyAlways)
+	podInput.Spec.InitContainers[0].Env = []corev1.EnvVar{{Name: "nginx-env", Value: "nginx-value"}}
+	podInput.Spec.InitContainers[0].VolumeMounts = []corev1.VolumeMount{{MountPath: "/data/nginx"}}
+	podInput.Spec.Containers = podInput.Spec.Containers[:1]
+	podInput.Status.InitContainerStatuses = podInput.Status.ContainerStatuses[1:]
+	podInput.Status.ContainerStatuses = podInput.Status.ContainerStatuses[:1]
+	handlers := map[string]HandlePod{
+		"pod test-pod-1 is upgrading": func(pods []*corev1.Pod) {
+			cStatus := &pods[0].Status.InitContainerStatuses[0]
+			cStatus.Image = "test-image:v2"
+			cStatus.ImageID = testImageV2ImageID
+		},
+		"pod test-pod-2 is upgrading": func(pods []*corev1.Pod) {
+			cStatus := &pods[1].Status.InitContainerStatuses[0]
+			cStatus.Image = "test-image:v2"
+			cStatus.ImageID = testImageV2ImageID
+		},
+	}
+	testUpdateColdUpgradeSidecar(t, podInput, sidecarSetInput, handlers)
+}
+
 func testUpdateColdUpgradeSidecar(t *testing.T, podDemo *corev1.Pod, sidecarSetInput *appsv1beta1.SidecarSet, handlers map[string]HandlePod) {
 	podInput1 := podDemo.DeepCopy()
 	podInput2 := podDemo.DeepCopy()
@@ -190,7 +220,7 @@ func testUpdateColdUpgradeSidecar(t *testing.T, podDemo *corev1.Pod, sidecarSetI
 				if err != nil {
 					t.Errorf("get latest pod(%s) failed: %s", pod.Name, err.Error())
 				}
-				sidecarContainer := &podOutput.Spec.Containers[1]
+				sidecarContainer := util.GetContainer("test-sidecar", podOutput)
 				if infos[0] != sidecarContainer.Image {
 					t.Fatalf("expect pod(%s) container(%s) image(%s), but get image(%s)", pod.Name, sidecarContainer.Name, infos[0], sidecarContainer.Image)
 				}
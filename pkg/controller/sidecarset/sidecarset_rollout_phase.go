@@ -0,0 +1,230 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecarset
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	"github.com/openkruise/kruise/pkg/control/sidecarcontrol"
+	"github.com/openkruise/kruise/pkg/util"
+)
+
+// getRolloutPhasePods returns the pods of the current rollout phase and the name of the phase.
+// The current phase is the first one whose pods are not all updated and healthy, and the duration
+// is returned if the phase is waiting for the pods to be ready long enough.
+func (p *Processor) getRolloutPhasePods(control sidecarcontrol.SidecarControl, pods []*corev1.Pod) ([]*corev1.Pod, string, time.Duration, error) {
+	sidecarSet := control.GetSidecarset()
+	phases := sidecarSet.Spec.UpdateStrategy.RolloutPhases
+	if len(phases) == 0 {
+		return pods, "", 0, nil
+	}
+
+	// the pods not selected by any phase belong to the implicit last phase
+	podsOfPhases := make([][]*corev1.Pod, len(phases)+1)
+	namespaces := make(map[string]*corev1.Namespace)
+	for _, pod := range pods {
+		index, err := p.getPodRolloutPhase(phases, pod, namespaces)
+		if err != nil {
+			return nil, "", 0, err
+		}
+		podsOfPhases[index] = append(podsOfPhases[index], pod)
+	}
+
+	now := time.Now()
+	for i := range phases {
+		var finished, updating = true, false
+		var requeueAfter time.Duration
+		var blockedPods []string
+		for _, pod := range podsOfPhases[i] {
+			if !isPodInRollout(control, pod) {
+				continue
+			}
+			if !sidecarcontrol.IsPodSidecarUpdated(sidecarSet, pod) {
+				finished, updating = false, true
+				break
+			}
+			if passed, wait, reason := isPodPassedHealthGate(control, pod, phases[i].HealthGate, now); !passed {
+				finished = false
+				if wait > 0 && (requeueAfter == 0 || wait < requeueAfter) {
+					requeueAfter = wait
+				} else if wait == 0 {
+					blockedPods = append(blockedPods, fmt.Sprintf("%s(%s)", pod.Name, reason))
+				}
+			}
+		}
+		if !finished {
+			klog.V(3).InfoS("SidecarSet was rolling out phase", "sidecarSet", klog.KObj(sidecarSet), "phase", phases[i].Name, "podCount", len(podsOfPhases[i]))
+			// all the pods of the phase have been updated, but some of them will never pass the health gate by waiting
+			if !updating && requeueAfter == 0 && len(blockedPods) > 0 {
+				p.recorder.Eventf(sidecarSet, corev1.EventTypeWarning, "RolloutPhaseBlocked",
+					"rollout phase %s is blocked by the pods failing the health gate: %s", phases[i].Name, strings.Join(blockedPods, ", "))
+			}
+			return podsOfPhases[i], phases[i].Name, requeueAfter, nil
+		}
+	}
+	return podsOfPhases[len(phases)], "", 0, nil
+}
+
+// getPodRolloutPhase returns the index of the first phase selecting the pod,
+// or the number of phases if no phase selects it.
+func (p *Processor) getPodRolloutPhase(phases []appsv1beta1.SidecarSetRolloutPhase, pod *corev1.Pod, namespaces map[string]*corev1.Namespace) (int, error) {
+	var workloads []metav1.OwnerReference
+	for i := range phases {
+		phase := &phases[i]
+		if phase.NamespaceSelector != nil {
+			ns, ok := namespaces[pod.Namespace]
+			if !ok {
+				ns = &corev1.Namespace{}
+				if err := p.Client.Get(context.TODO(), client.ObjectKey{Name: pod.Namespace}, ns); err != nil {
+					return -1, err
+				}
+				namespaces[pod.Namespace] = ns
+			}
+			selector, err := util.ValidatedLabelSelectorAsSelector(phase.NamespaceSelector)
+			if err != nil {
+				return -1, err
+			}
+			if !selector.Matches(labels.Set(ns.Labels)) {
+				continue
+			}
+		}
+		if len(phase.Workloads) > 0 {
+			if workloads == nil {
+				var err error
+				if workloads, err = p.getPodWorkloads(pod); err != nil {
+					return -1, err
+				}
+			}
+			if !isWorkloadSelected(phase.Workloads, workloads) {
+				continue
+			}
+		}
+		return i, nil
+	}
+	return len(phases), nil
+}
+
+// getPodWorkloads returns the controller of the pod, and also the Deployment owning its ReplicaSet.
+func (p *Processor) getPodWorkloads(pod *corev1.Pod) ([]metav1.OwnerReference, error) {
+	workloads := []metav1.OwnerReference{}
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return workloads, nil
+	}
+	workloads = append(workloads, *owner)
+	if owner.Kind == "ReplicaSet" {
+		rs := &apps.ReplicaSet{}
+		if err := p.Client.Get(context.TODO(), client.ObjectKey{Namespace: pod.Namespace, Name: owner.Name}, rs); err != nil {
+			return nil, client.IgnoreNotFound(err)
+		}
+		if rsOwner := metav1.GetControllerOf(rs); rsOwner != nil {
+			workloads = append(workloads, *rsOwner)
+		}
+	}
+	return workloads, nil
+}
+
+func isWorkloadSelected(selected []appsv1beta1.SidecarSetRolloutWorkload, workloads []metav1.OwnerReference) bool {
+	for _, s := range selected {
+		for _, w := range workloads {
+			if s.Kind == w.Kind && (s.Name == "" || s.Name == w.Name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isPodInRollout returns true if the pod is expected to be updated, which is selected by
+// the updateStrategy and can be updated in-place.
+func isPodInRollout(control sidecarcontrol.SidecarControl, pod *corev1.Pod) bool {
+	strategy := control.GetSidecarset().Spec.UpdateStrategy
+	if strategy.Selector != nil {
+		selector, err := util.ValidatedLabelSelectorAsSelector(strategy.Selector)
+		if err != nil || !selector.Matches(labels.Set(pod.Labels)) {
+			return false
+		}
+	}
+	if sidecarcontrol.IsPodSidecarUpdated(control.GetSidecarset(), pod) {
+		return true
+	}
+	canUpgrade, _ := control.IsSidecarSetUpgradable(pod)
+	return canUpgrade
+}
+
+// isPodPassedHealthGate returns true if the updated pod passes the health gate,
+// otherwise the duration to wait if it will pass after a while, or the reason why it does not pass.
+func isPodPassedHealthGate(control sidecarcontrol.SidecarControl, pod *corev1.Pod, gate *appsv1beta1.SidecarSetRolloutHealthGate, now time.Time) (bool, time.Duration, string) {
+	if !control.IsPodStateConsistent(pod, nil) {
+		return false, 0, "not consistent"
+	} else if !control.IsPodReady(pod) {
+		return false, 0, "not ready"
+	}
+	if gate == nil {
+		return true, 0, ""
+	}
+
+	sidecarSet := control.GetSidecarset()
+	sidecarNames := sidecarcontrol.GetSidecarContainersInPod(sidecarSet)
+	emptyImages := sets.NewString()
+	for _, sidecarContainer := range sidecarcontrol.GetSidecarSetContainersWithNativeSidecars(sidecarSet) {
+		if image := sidecarContainer.UpgradeStrategy.HotUpgradeEmptyImage; image != "" {
+			emptyImages.Insert(image)
+		}
+	}
+	images := make(map[string]string, len(pod.Spec.Containers))
+	for _, container := range sidecarcontrol.GetPodContainersWithNativeSidecars(pod) {
+		images[container.Name] = container.Image
+	}
+	updateTimestamp := sidecarcontrol.GetPodSidecarSetUpgradeSpecInAnnotations(sidecarSet.Name, sidecarcontrol.SidecarSetHashAnnotation, pod).UpdateTimestamp
+
+	var wait time.Duration
+	containerStatuses := sidecarcontrol.GetPodContainerStatusesWithNativeSidecars(pod)
+	for i := range containerStatuses {
+		status := &containerStatuses[i]
+		// the empty container of hot upgrade is not checked
+		if !sidecarNames.Has(status.Name) || emptyImages.Has(images[status.Name]) {
+			continue
+		}
+		if status.State.Running == nil {
+			return false, 0, fmt.Sprintf("container %s not running", status.Name)
+		}
+		// the container started after updated has terminated
+		if gate.NoRestarts && status.LastTerminationState.Terminated != nil &&
+			!status.LastTerminationState.Terminated.StartedAt.Before(&updateTimestamp) {
+			klog.V(3).InfoS("SidecarSet updated sidecar container was restarted", "sidecarSet", klog.KObj(sidecarSet), "pod", klog.KObj(pod), "container", status.Name)
+			return false, 0, fmt.Sprintf("container %s restarted", status.Name)
+		}
+		minReady := time.Duration(gate.MinReadySeconds) * time.Second
+		if remaining := status.State.Running.StartedAt.Add(minReady).Sub(now); remaining > wait {
+			wait = remaining
+		}
+	}
+	return wait <= 0, wait, ""
+}
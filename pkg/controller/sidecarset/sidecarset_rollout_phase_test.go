@@ -0,0 +1,129 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecarset
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	"github.com/openkruise/kruise/pkg/control/sidecarcontrol"
+)
+
+func TestGetRolloutPhasePods(t *testing.T) {
+	sidecarSet := factorySidecarSet()
+	sidecarSet.Spec.UpdateStrategy.RolloutPhases = []appsv1beta1.SidecarSetRolloutPhase{
+		{
+			Name:              "canary",
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "canary"}},
+			HealthGate:        &appsv1beta1.SidecarSetRolloutHealthGate{MinReadySeconds: 60, NoRestarts: true},
+		},
+		{
+			Name:      "web",
+			Workloads: []appsv1beta1.SidecarSetRolloutWorkload{{Kind: "Deployment", Name: "web"}},
+		},
+	}
+	canaryNs := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "canary", Labels: map[string]string{"env": "canary"}}}
+	prodNs := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "prod"}}
+	rs := &apps.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+		Namespace:       "prod",
+		Name:            "web-abc",
+		OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", Controller: ptr.To(true)}},
+	}}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(canaryNs, prodNs, rs).Build()
+	recorder := record.NewFakeRecorder(10)
+	processor := NewSidecarSetProcessor(fakeClient, recorder)
+	control := sidecarcontrol.New(sidecarSet)
+
+	now := time.Now()
+	pods := factoryPods(4, 0, 0)
+	for i, pod := range pods {
+		pod.Namespace = "prod"
+		if i < 2 {
+			pod.Namespace = "canary"
+		}
+		for j := range pod.Status.ContainerStatuses {
+			pod.Status.ContainerStatuses[j].State.Running = &corev1.ContainerStateRunning{StartedAt: metav1.NewTime(now.Add(-10 * time.Second))}
+		}
+	}
+	pods[2].OwnerReferences = []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web-abc", Controller: ptr.To(true)}}
+	upgrade := func(pod *corev1.Pod) {
+		pod.Spec.Containers[1].Image = "test-image:v2"
+		pod.Status.ContainerStatuses[1].Image = "test-image:v2"
+		pod.Status.ContainerStatuses[1].ImageID = testImageV2ImageID
+		sidecarcontrol.UpdatePodSidecarSetHash(pod, sidecarSet)
+	}
+	expectPhase := func(expectedPhase string, expectedPods []*corev1.Pod, expectedRequeue bool) {
+		t.Helper()
+		phasePods, phase, requeueAfter, err := processor.getRolloutPhasePods(control, pods)
+		if err != nil {
+			t.Fatalf("failed to get rollout phase pods: %v", err)
+		}
+		if phase != expectedPhase {
+			t.Fatalf("expected phase %q, got %q", expectedPhase, phase)
+		}
+		if len(phasePods) != len(expectedPods) {
+			t.Fatalf("expected %d pods in phase, got %d", len(expectedPods), len(phasePods))
+		}
+		for i := range expectedPods {
+			if phasePods[i] != expectedPods[i] {
+				t.Fatalf("expected pod %s in phase, got %s", expectedPods[i].Name, phasePods[i].Name)
+			}
+		}
+		if (requeueAfter > 0) != expectedRequeue {
+			t.Fatalf("expected requeue %v, got %v", expectedRequeue, requeueAfter)
+		}
+	}
+
+	// the canary pods are updated first
+	expectPhase("canary", pods[:2], false)
+
+	// the canary pods have not been ready for minReadySeconds
+	upgrade(pods[0])
+	upgrade(pods[1])
+	expectPhase("canary", pods[:2], true)
+
+	// the sidecar container restarted after updated
+	for _, pod := range pods[:2] {
+		pod.Status.ContainerStatuses[1].State.Running.StartedAt = metav1.NewTime(now.Add(-2 * time.Minute))
+	}
+	pods[1].Status.ContainerStatuses[1].LastTerminationState.Terminated = &corev1.ContainerStateTerminated{StartedAt: metav1.NewTime(now.Add(time.Second))}
+	expectPhase("canary", pods[:2], false)
+	if len(recorder.Events) != 1 {
+		t.Fatalf("expected 1 event for the blocked phase, got %d", len(recorder.Events))
+	}
+	if event := <-recorder.Events; !strings.Contains(event, "RolloutPhaseBlocked") || !strings.Contains(event, "canary") ||
+		!strings.Contains(event, pods[1].Name) || strings.Contains(event, pods[0].Name+"(") {
+		t.Fatalf("unexpected event for the blocked phase: %s", event)
+	}
+
+	// the pods of Deployment web are updated next
+	pods[1].Status.ContainerStatuses[1].LastTerminationState.Terminated = nil
+	expectPhase("web", pods[2:3], false)
+
+	// the rest pods are updated at last
+	upgrade(pods[2])
+	expectPhase("", pods[3:], false)
+}
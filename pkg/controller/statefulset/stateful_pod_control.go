@@ -24,6 +24,7 @@ import (
 
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -52,7 +53,14 @@ type StatefulPodControlObjectManager interface {
 	CreateClaim(claim *v1.PersistentVolumeClaim) error
 	GetClaim(namespace, claimName string) (*v1.PersistentVolumeClaim, error)
 	UpdateClaim(claim *v1.PersistentVolumeClaim) error
+	DeleteClaim(claim *v1.PersistentVolumeClaim) error
 	GetStorageClass(scName string) (*storagev1.StorageClass, error)
+	GetPersistentVolume(pvName string) (*v1.PersistentVolume, error)
+	UpdatePersistentVolume(pv *v1.PersistentVolume) error
+	GetJob(namespace, jobName string) (*batchv1.Job, error)
+	CreateJob(job *batchv1.Job) error
+	UpdateJob(job *batchv1.Job) error
+	DeleteJob(job *batchv1.Job) error
 }
 
 // StatefulPodControl defines the interface that StatefulSetController uses to create, update, and delete Pods,
@@ -119,10 +127,44 @@ func (om *realStatefulPodControlObjectManager) UpdateClaim(claim *v1.PersistentV
 	return err
 }
 
+func (om *realStatefulPodControlObjectManager) DeleteClaim(claim *v1.PersistentVolumeClaim) error {
+	return om.client.CoreV1().PersistentVolumeClaims(claim.Namespace).Delete(context.TODO(), claim.Name, metav1.DeleteOptions{})
+}
+
 func (om *realStatefulPodControlObjectManager) GetStorageClass(scName string) (*storagev1.StorageClass, error) {
 	return om.scLister.Get(scName)
 }
 
+// PersistentVolumes and Jobs are only used by pvc migration, so they are got from the apiserver instead of informers.
+
+func (om *realStatefulPodControlObjectManager) GetPersistentVolume(pvName string) (*v1.PersistentVolume, error) {
+	return om.client.CoreV1().PersistentVolumes().Get(context.TODO(), pvName, metav1.GetOptions{})
+}
+
+func (om *realStatefulPodControlObjectManager) UpdatePersistentVolume(pv *v1.PersistentVolume) error {
+	_, err := om.client.CoreV1().PersistentVolumes().Update(context.TODO(), pv, metav1.UpdateOptions{})
+	return err
+}
+
+func (om *realStatefulPodControlObjectManager) GetJob(namespace, jobName string) (*batchv1.Job, error) {
+	return om.client.BatchV1().Jobs(namespace).Get(context.TODO(), jobName, metav1.GetOptions{})
+}
+
+func (om *realStatefulPodControlObjectManager) CreateJob(job *batchv1.Job) error {
+	_, err := om.client.BatchV1().Jobs(job.Namespace).Create(context.TODO(), job, metav1.CreateOptions{})
+	return err
+}
+
+func (om *realStatefulPodControlObjectManager) UpdateJob(job *batchv1.Job) error {
+	_, err := om.client.BatchV1().Jobs(job.Namespace).Update(context.TODO(), job, metav1.UpdateOptions{})
+	return err
+}
+
+func (om *realStatefulPodControlObjectManager) DeleteJob(job *batchv1.Job) error {
+	propagation := metav1.DeletePropagationBackground
+	return om.client.BatchV1().Jobs(job.Namespace).Delete(context.TODO(), job.Name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+}
+
 func (spc *StatefulPodControl) CreateStatefulPod(ctx context.Context, set *appsv1beta1.StatefulSet, pod *v1.Pod) error {
 	// Create the Pod's PVCs prior to creating the Pod
 	if err := spc.createPersistentVolumeClaims(set, pod); err != nil {
@@ -215,6 +257,26 @@ func (spc *StatefulPodControl) DeleteStatefulPod(set *appsv1beta1.StatefulSet, p
 	return err
 }
 
+// RequestSwitchover annotates the leader pod to request a switchover before it is updated to updateRevision.
+func (spc *StatefulPodControl) RequestSwitchover(set *appsv1beta1.StatefulSet, pod *v1.Pod, updateRevision string) error {
+	if pod.Annotations[appsv1beta1.SwitchoverRequestedAnnotationKey] == updateRevision {
+		return nil
+	}
+	clone := pod.DeepCopy()
+	if clone.Annotations == nil {
+		clone.Annotations = map[string]string{}
+	}
+	clone.Annotations[appsv1beta1.SwitchoverRequestedAnnotationKey] = updateRevision
+	if err := spc.objectMgr.UpdatePod(clone); err != nil {
+		spc.recorder.Eventf(set, v1.EventTypeWarning, "FailedRequestSwitchover",
+			"request switchover of Pod %s in StatefulSet %s failed error: %s", pod.Name, set.Name, err)
+		return err
+	}
+	spc.recorder.Eventf(set, v1.EventTypeNormal, "SuccessfulRequestSwitchover",
+		"request switchover of Pod %s in StatefulSet %s successful", pod.Name, set.Name)
+	return nil
+}
+
 // ClaimsMatchRetentionPolicy returns false if the PVCs for pod are not consistent with set's PVC deletion policy.
 // An error is returned if something is not consistent. This is expected if the pod is being otherwise updated,
 // but a problem otherwise (see usage of this method in UpdateStatefulPod).
@@ -269,12 +331,9 @@ func (spc *StatefulPodControl) UpdatePodClaimForRetentionPolicy(set *appsv1beta1
 // PodClaimIsStale returns true for a stale PVC that should block pod creation. If the scaling
 // policy is deletion, and a PVC has an ownerRef that does not match the pod, the PVC is stale. This
 // includes pods whose UID has not been created.
+// Note that the scaling policy may be overridden to deletion by the annotation of the previous pod
+// with the same ordinal, so the claims are always checked even if the policy of set is retention.
 func (spc *StatefulPodControl) PodClaimIsStale(set *appsv1beta1.StatefulSet, pod *v1.Pod) (bool, error) {
-	policy := getPersistentVolumeClaimRetentionPolicy(set)
-	if policy.WhenScaled == appsv1beta1.RetainPersistentVolumeClaimRetentionPolicyType {
-		// PVCs are meant to be reused and so can't be stale.
-		return false, nil
-	}
 	for _, claim := range getPersistentVolumeClaims(set, pod) {
 		pvc, err := spc.objectMgr.GetClaim(claim.Namespace, claim.Name)
 		switch {
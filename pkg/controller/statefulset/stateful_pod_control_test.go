@@ -502,6 +502,40 @@ func TestStatefulPodControlDeleteFailure(t *testing.T) {
 	}
 }
 
+func TestStatefulPodControlRequestSwitchover(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	set := newStatefulSet(3)
+	pod := newStatefulSetPod(set, 0)
+	fakeClient := fake.NewSimpleClientset(pod)
+	control := NewStatefulPodControl(fakeClient, nil, nil, nil, recorder)
+	var updated *v1.Pod
+	fakeClient.PrependReactor("update", "pods", func(action core.Action) (bool, runtime.Object, error) {
+		update := action.(core.UpdateAction)
+		updated = update.GetObject().(*v1.Pod)
+		return true, update.GetObject(), nil
+	})
+	if err := control.RequestSwitchover(set, pod, "rev-2"); err != nil {
+		t.Errorf("Successful request switchover returned an error: %s", err)
+	}
+	if updated == nil || updated.Annotations[appsv1beta1.SwitchoverRequestedAnnotationKey] != "rev-2" {
+		t.Fatalf("Expected switchover requested annotation, got %v", updated)
+	}
+	if pod.Annotations[appsv1beta1.SwitchoverRequestedAnnotationKey] != "" {
+		t.Error("Request switchover should not mutate the given pod")
+	}
+	events := collectEvents(recorder.Events)
+	if eventCount := len(events); eventCount != 1 {
+		t.Errorf("request switchover: got %d events, but want 1", eventCount)
+	}
+
+	// no more update if the switchover has been requested for the revision
+	requested := updated
+	updated = nil
+	if err := control.RequestSwitchover(set, requested, "rev-2"); err != nil || updated != nil {
+		t.Errorf("Expected no update for requested switchover, got %v, %v", updated, err)
+	}
+}
+
 func TestStatefulPodControlClaimsMatchDeletionPolicy(t *testing.T) {
 	// The claimOwnerMatchesSetAndPod is tested exhaustively in stateful_set_utils_test; this
 	// test is for the wiring to the method tested there.
@@ -593,6 +627,7 @@ func TestPodClaimIsStale(t *testing.T) {
 		claimStates []string
 		expected    bool
 		skipPodUID  bool
+		retainSet   bool
 	}{
 		{
 			name:        "all missing",
@@ -635,6 +670,12 @@ func TestPodClaimIsStale(t *testing.T) {
 			skipPodUID:  true,
 			expected:    true,
 		},
+		{
+			name:        "stale, retained by set",
+			claimStates: []string{stale},
+			retainSet:   true,
+			expected:    true,
+		},
 	}
 	for _, tc := range testCases {
 		set := appsv1beta1.StatefulSet{}
@@ -644,6 +685,10 @@ func TestPodClaimIsStale(t *testing.T) {
 			WhenDeleted: appsv1beta1.RetainPersistentVolumeClaimRetentionPolicyType,
 			WhenScaled:  appsv1beta1.DeletePersistentVolumeClaimRetentionPolicyType,
 		}
+		if tc.retainSet {
+			// the stale claim may be left by the previous pod which overrides the policy
+			set.Spec.PersistentVolumeClaimRetentionPolicy.WhenScaled = appsv1beta1.RetainPersistentVolumeClaimRetentionPolicyType
+		}
 		set.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"key": "value"}}
 		claimIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
 		for i, claimState := range tc.claimStates {
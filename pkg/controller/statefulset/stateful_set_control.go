@@ -39,6 +39,7 @@ import (
 	appspub "github.com/openkruise/kruise/apis/apps/pub"
 	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
 	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	"github.com/openkruise/kruise/pkg/control/pubcontrol"
 	"github.com/openkruise/kruise/pkg/features"
 	"github.com/openkruise/kruise/pkg/util"
 	utilfeature "github.com/openkruise/kruise/pkg/util/feature"
@@ -385,6 +386,11 @@ func (ssc *defaultStatefulSetControl) updateStatefulSet(
 	status.CollisionCount = ptr.To[int32](collisionCount)
 	status.LabelSelector = selector.String()
 	minReadySeconds := getMinReadySeconds(set)
+	// keep the throttled condition until the rolling update decides whether it is still throttled
+	if cond := GetStatefulsetConditition(set.Status, appsv1beta1.PodUnavailableBudgetThrottled); cond != nil &&
+		set.Spec.UpdateStrategy.Type != apps.OnDeleteStatefulSetStrategyType {
+		status.Conditions = append(status.Conditions, *cond)
+	}
 
 	ssc.updatePVCStatus(&status, set, pods)
 	updateStatus(&status, minReadySeconds, currentRevision, updateRevision, pods)
@@ -567,7 +573,9 @@ func (ssc *defaultStatefulSetControl) rollingUpdateStatefulsetPods(
 	var err error
 	// we compute the minimum ordinal of the target sequence for a destructive update based on the strategy.
 	maxUnavailable := 1
+	var roleAwareUpdate *appsv1beta1.RoleAwareUpdateStrategy
 	if set.Spec.UpdateStrategy.RollingUpdate != nil {
+		roleAwareUpdate = set.Spec.UpdateStrategy.RollingUpdate.RoleAwareUpdate
 		if set.Spec.UpdateStrategy.RollingUpdate.Paused {
 			return status, nil
 		}
@@ -632,16 +640,31 @@ func (ssc *defaultStatefulSetControl) rollingUpdateStatefulsetPods(
 		return status, err
 	}
 
+	// the PodUnavailableBudgets protecting the pods may allow fewer unavailable pods than maxUnavailable,
+	// so check their quota in advance instead of being rejected by the pub webhook again and again
+	pubs, err := getPubsForRollingUpdate(set, replicas)
+	if err != nil {
+		return status, err
+	}
+	status.Conditions = filterOutCondition(status.Conditions, appsv1beta1.PodUnavailableBudgetThrottled)
+
 	updateIndexes := sortPodsToUpdate(set.Spec.UpdateStrategy.RollingUpdate, updateRevision.Name, *set.Spec.Replicas, replicas)
 	klog.V(3).InfoS("Prepare to update pods indexes for StatefulSet", "statefulSet", klog.KObj(set), "podIndexes", updateIndexes)
 	// update pods in sequence
 	for _, target := range updateIndexes {
 		var pvcMatched bool = true
+		var pvcNeedMigration bool
 		if utilfeature.DefaultFeatureGate.Enabled(features.StatefulSetAutoResizePVCGate) &&
 			set.Spec.VolumeClaimUpdateStrategy.Type == appsv1beta1.OnPodRollingUpdateVolumeClaimUpdateStrategyType {
 			if pvcMatched, err = ssc.podControl.IsClaimsCompatible(set, replicas[target]); err != nil {
 				return status, err
 			}
+			// the pod has to be recreated, and its claims will be migrated before it is created again
+			if !pvcMatched {
+				if pvcNeedMigration, err = ssc.podControl.IsClaimsNeedMigration(set, replicas[target]); err != nil {
+					return status, err
+				}
+			}
 		}
 
 		// the target is already up-to-date, go to next
@@ -657,11 +680,27 @@ func (ssc *defaultStatefulSetControl) rollingUpdateStatefulsetPods(
 			return status, nil
 		}
 
+		// the leader is updated after all the other pods are available, and after its switchover has been acknowledged
+		if roleAwareUpdate != nil && isLeaderPod(roleAwareUpdate, replicas[target]) && !isTerminating(replicas[target]) {
+			if others := unavailablePods.Difference(sets.NewString(replicas[target].Name)); others.Len() > 0 {
+				klog.V(4).InfoS("StatefulSet was waiting for unavailable Pods before updating leader",
+					"statefulSet", klog.KObj(set), "unavailablePods", others.List(), "leader", klog.KObj(replicas[target]))
+				return status, nil
+			}
+			if lifecycle.IsPodHooked(roleAwareUpdate.SwitchoverHook, replicas[target]) {
+				klog.V(3).InfoS("StatefulSet was waiting for leader to switch over", "statefulSet", klog.KObj(set), "leader", klog.KObj(replicas[target]))
+				if err := ssc.podControl.RequestSwitchover(set, replicas[target], updateRevision.Name); err != nil {
+					return status, err
+				}
+				return status, nil
+			}
+		}
+
 		// Kruise currently will not patch pvc size until a pod references the resized volume.
 		// online-file-system-expansion: if no pods referencing the volume are running, file system expansion will not happen.
 		// refer to https://kubernetes.io/blog/2018/07/12/resizing-persistent-volumes-using-kubernetes/#online-file-system-expansion
 		if utilfeature.DefaultFeatureGate.Enabled(features.StatefulSetAutoResizePVCGate) &&
-			set.Spec.VolumeClaimUpdateStrategy.Type == appsv1beta1.OnPodRollingUpdateVolumeClaimUpdateStrategyType {
+			set.Spec.VolumeClaimUpdateStrategy.Type == appsv1beta1.OnPodRollingUpdateVolumeClaimUpdateStrategyType && !pvcNeedMigration {
 			// resize pvc if necessary and wait for resize completed
 			if !pvcMatched {
 				err = ssc.podControl.TryPatchPVC(set, replicas[target])
@@ -679,14 +718,32 @@ func (ssc *defaultStatefulSetControl) rollingUpdateStatefulsetPods(
 				// need to wait for pvc resize completed, continue to handle next pod
 				continue
 			}
+			// the claims have been patched in place, and the pod is already up-to-date
+			if getPodRevision(replicas[target]) == updateRevision.Name {
+				continue
+			}
 		}
 
 		// delete the Pod if it is not already terminating and does not match the update revision.
 		if !specifiedDeletedPods.Has(replicas[target].Name) && !isTerminating(replicas[target]) {
-			// todo validate in-place for pub
-			inplacing, inplaceUpdateErr := ssc.inPlaceUpdatePod(set, replicas[target], updateRevision, revisions)
-			if inplaceUpdateErr != nil {
-				return status, inplaceUpdateErr
+			if pub := pubs[replicas[target].Annotations[pubcontrol.PodRelatedPubAnnotation]]; pub != nil && pubcontrol.IsPodConsumingPubQuota(replicas[target], pub) {
+				if pub.Status.UnavailableAllowed <= 0 {
+					msg := fmt.Sprintf("PodUnavailableBudget %s allows no more unavailable pods, blocked pod %s", pub.Name, replicas[target].Name)
+					klog.V(3).InfoS("StatefulSet rolling update was throttled by PodUnavailableBudget",
+						"statefulSet", klog.KObj(set), "pub", klog.KObj(pub), "blockedPod", klog.KObj(replicas[target]))
+					SetStatefulsetCondition(status, NewStatefulsetCondition(appsv1beta1.PodUnavailableBudgetThrottled, v1.ConditionTrue, "PodUnavailableBudget", msg))
+					// the pub event handler enqueues the statefulset once the pub controller releases the quota
+					return status, nil
+				}
+				pub.Status.UnavailableAllowed--
+			}
+			var inplacing bool
+			if !pvcNeedMigration {
+				var inplaceUpdateErr error
+				inplacing, inplaceUpdateErr = ssc.inPlaceUpdatePod(set, replicas[target], updateRevision, revisions)
+				if inplaceUpdateErr != nil {
+					return status, inplaceUpdateErr
+				}
 			}
 			// if pod is inplacing or actual deleting, decrease revision
 			revisionNeedDecrease := inplacing
@@ -773,6 +830,7 @@ func (ssc *defaultStatefulSetControl) refreshPodState(set *appsv1beta1.StatefulS
 	opts := &inplaceupdate.UpdateOptions{}
 	if set.Spec.UpdateStrategy.RollingUpdate != nil && set.Spec.UpdateStrategy.RollingUpdate.InPlaceUpdateStrategy != nil {
 		opts.GracePeriodSeconds = set.Spec.UpdateStrategy.RollingUpdate.InPlaceUpdateStrategy.GracePeriodSeconds
+		opts.ContainerUpdateOrder = set.Spec.UpdateStrategy.RollingUpdate.InPlaceUpdateStrategy.ContainerUpdateOrder
 	}
 	opts = inplaceupdate.SetOptionsDefaults(opts)
 
@@ -857,6 +915,7 @@ func (ssc *defaultStatefulSetControl) inPlaceUpdatePod(
 	opts := &inplaceupdate.UpdateOptions{}
 	if set.Spec.UpdateStrategy.RollingUpdate.InPlaceUpdateStrategy != nil {
 		opts.GracePeriodSeconds = set.Spec.UpdateStrategy.RollingUpdate.InPlaceUpdateStrategy.GracePeriodSeconds
+		opts.ContainerUpdateOrder = set.Spec.UpdateStrategy.RollingUpdate.InPlaceUpdateStrategy.ContainerUpdateOrder
 	}
 
 	if ssc.inplaceControl.CanUpdateInPlace(oldRevision, updateRevision, opts) {
@@ -1111,6 +1170,17 @@ func (ssc *defaultStatefulSetControl) processReplica(
 	}
 	// If we find a Pod that has not been created we create the Pod
 	if !isCreated(replicas[i]) {
+		// the pod waits for its startup dependencies, and the others can be created in parallel
+		if len(set.Spec.StartupDependencies) > 0 {
+			if ready, waitTime := isStartupDependenciesReady(set, replicas, replicas[i]); !ready {
+				if waitTime > 0 {
+					durationStore.Push(getStatefulSetKey(set), waitTime)
+				}
+				logger.V(4).Info("StatefulSet is waiting for startup dependencies before creating Pod",
+					"statefulSet", klog.KObj(set), "pod", klog.KObj(replicas[i]))
+				return monotonic, false, nil
+			}
+		}
 		if utilfeature.DefaultFeatureGate.Enabled(features.StatefulSetAutoDeletePVC) {
 			if isStale, err := ssc.podControl.PodClaimIsStale(set, replicas[i]); err != nil {
 				return true, false, err
@@ -1119,6 +1189,14 @@ func (ssc *defaultStatefulSetControl) processReplica(
 				return true, false, err
 			}
 		}
+		// the claims of the pod should be migrated before it is created, since they can not be updated in place.
+		if migrated, err := ssc.podControl.MigrateClaims(set, replicas[i]); err != nil {
+			return true, false, err
+		} else if !migrated {
+			logger.V(4).Info("StatefulSet is waiting for PVC migration before creating Pod",
+				"statefulSet", klog.KObj(set), "pod", klog.KObj(replicas[i]))
+			return true, false, nil
+		}
 		// asts ut invoke updateStatefulset once by once,
 		// so we can update pod into normal state to avoid changing so many ut cases
 		state := appspub.LifecycleStatePreparingNormal
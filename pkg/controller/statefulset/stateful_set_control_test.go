@@ -32,6 +32,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	apps "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
@@ -56,17 +57,21 @@ import (
 	testingclock "k8s.io/utils/clock/testing"
 	utilpointer "k8s.io/utils/pointer"
 	"k8s.io/utils/ptr"
+	crfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	appspub "github.com/openkruise/kruise/apis/apps/pub"
 	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
 	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	policyv1alpha1 "github.com/openkruise/kruise/apis/policy/v1alpha1"
 	kruiseclientset "github.com/openkruise/kruise/pkg/client/clientset/versioned"
 	kruisefake "github.com/openkruise/kruise/pkg/client/clientset/versioned/fake"
 	kruiseinformers "github.com/openkruise/kruise/pkg/client/informers/externalversions"
 	kruiseappsinformers "github.com/openkruise/kruise/pkg/client/informers/externalversions/apps/v1beta1"
 	kruiseappslisters "github.com/openkruise/kruise/pkg/client/listers/apps/v1beta1"
+	"github.com/openkruise/kruise/pkg/control/pubcontrol"
 	"github.com/openkruise/kruise/pkg/features"
 	"github.com/openkruise/kruise/pkg/util"
+	"github.com/openkruise/kruise/pkg/util/controllerfinder"
 	utilfeature "github.com/openkruise/kruise/pkg/util/feature"
 	"github.com/openkruise/kruise/pkg/util/inplaceupdate"
 	"github.com/openkruise/kruise/pkg/util/lifecycle"
@@ -2221,6 +2226,73 @@ func TestStatefulSetControlRollingUpdateBlockByMaxUnavailable(t *testing.T) {
 	}
 }
 
+func TestStatefulSetControlRollingUpdateThrottledByPub(t *testing.T) {
+	set := burst(newStatefulSet(4))
+	var partition int32 = 0
+	var maxUnavailable = intstr.FromInt(3)
+	set.Spec.UpdateStrategy = appsv1beta1.StatefulSetUpdateStrategy{
+		Type: apps.RollingUpdateStatefulSetStrategyType,
+		RollingUpdate: &appsv1beta1.RollingUpdateStatefulSetStrategy{
+			Partition:      &partition,
+			MaxUnavailable: &maxUnavailable,
+		},
+	}
+	set.Spec.Template.Annotations = map[string]string{pubcontrol.PodRelatedPubAnnotation: "pub-test"}
+
+	pub := &policyv1alpha1.PodUnavailableBudget{
+		ObjectMeta: metav1.ObjectMeta{Namespace: set.Namespace, Name: "pub-test"},
+		Status: policyv1alpha1.PodUnavailableBudgetStatus{
+			UnavailableAllowed: 1,
+			DesiredAvailable:   3,
+		},
+	}
+	scheme := apiruntime.NewScheme()
+	_ = policyv1alpha1.AddToScheme(scheme)
+	pubClient := crfake.NewClientBuilder().WithScheme(scheme).WithObjects(pub).Build()
+	pubcontrol.InitPubControl(pubClient, &controllerfinder.ControllerFinder{Client: pubClient}, record.NewFakeRecorder(10))
+
+	client := fake.NewSimpleClientset()
+	kruiseClient := kruisefake.NewSimpleClientset(set)
+	spc, _, ssc, stop := setupController(client, kruiseClient)
+	defer close(stop)
+	if err := scaleUpStatefulSetControl(set, ssc, spc, assertBurstInvariants); err != nil {
+		t.Fatal(err)
+	}
+	set, err := spc.setsLister.StatefulSets(set.Namespace).Get(set.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(set.Spec.Selector)
+	if err != nil {
+		t.Fatal(err)
+	}
+	originalPods, err := spc.podsLister.Pods(set.Namespace).List(selector)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// only one pod is deleted, because the pub allows one more unavailable pod
+	set.Spec.Template.Spec.Containers[0].Image = "foo"
+	if err = ssc.UpdateStatefulSet(context.TODO(), set, originalPods); err != nil {
+		t.Fatal(err)
+	}
+	pods, err := spc.podsLister.Pods(set.Namespace).List(selector)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pods) != 3 {
+		t.Fatalf("Expected 3 pods left, got %d", len(pods))
+	}
+	set, err = spc.setsLister.StatefulSets(set.Namespace).Get(set.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cond := GetStatefulsetConditition(set.Status, appsv1beta1.PodUnavailableBudgetThrottled)
+	if cond == nil || cond.Status != v1.ConditionTrue {
+		t.Fatalf("Expected PodUnavailableBudgetThrottled condition, got %v", set.Status.Conditions)
+	}
+}
+
 func TestStatefulSetControlRollingUpdateWithSpecifiedDelete(t *testing.T) {
 	set := burst(newStatefulSet(6))
 	var partition int32 = 3
@@ -3873,6 +3945,8 @@ type fakeObjectManager struct {
 	createPodTracker requestTracker
 	updatePodTracker requestTracker
 	deletePodTracker requestTracker
+	pvsIndexer       cache.Indexer
+	jobsIndexer      cache.Indexer
 }
 
 func newFakeObjectManager(informerFactory informers.SharedInformerFactory, kruiseInformerFactory kruiseinformers.SharedInformerFactory) *fakeObjectManager {
@@ -3893,7 +3967,9 @@ func newFakeObjectManager(informerFactory informers.SharedInformerFactory, kruis
 		revisionInformer.Informer().GetIndexer(),
 		requestTracker{sync.Mutex{}, 0, nil, 0},
 		requestTracker{sync.Mutex{}, 0, nil, 0},
-		requestTracker{sync.Mutex{}, 0, nil, 0}}
+		requestTracker{sync.Mutex{}, 0, nil, 0},
+		informerFactory.Core().V1().PersistentVolumes().Informer().GetIndexer(),
+		informerFactory.Batch().V1().Jobs().Informer().GetIndexer()}
 }
 
 func (om *fakeObjectManager) CreatePod(ctx context.Context, pod *v1.Pod) error {
@@ -3952,10 +4028,50 @@ func (om *fakeObjectManager) UpdateClaim(claim *v1.PersistentVolumeClaim) error
 	return nil
 }
 
+func (om *fakeObjectManager) DeleteClaim(claim *v1.PersistentVolumeClaim) error {
+	return om.claimsIndexer.Delete(claim)
+}
+
 func (om *fakeObjectManager) GetStorageClass(scName string) (*storagev1.StorageClass, error) {
 	return om.scLister.Get(scName)
 }
 
+func (om *fakeObjectManager) GetPersistentVolume(pvName string) (*v1.PersistentVolume, error) {
+	obj, exists, err := om.pvsIndexer.GetByKey(pvName)
+	if err != nil {
+		return nil, err
+	} else if !exists {
+		return nil, apierrors.NewNotFound(v1.Resource("persistentvolumes"), pvName)
+	}
+	return obj.(*v1.PersistentVolume), nil
+}
+
+func (om *fakeObjectManager) UpdatePersistentVolume(pv *v1.PersistentVolume) error {
+	return om.pvsIndexer.Update(pv)
+}
+
+func (om *fakeObjectManager) GetJob(namespace, jobName string) (*batchv1.Job, error) {
+	obj, exists, err := om.jobsIndexer.GetByKey(namespace + "/" + jobName)
+	if err != nil {
+		return nil, err
+	} else if !exists {
+		return nil, apierrors.NewNotFound(batchv1.Resource("jobs"), jobName)
+	}
+	return obj.(*batchv1.Job), nil
+}
+
+func (om *fakeObjectManager) CreateJob(job *batchv1.Job) error {
+	return om.jobsIndexer.Add(job.DeepCopy())
+}
+
+func (om *fakeObjectManager) UpdateJob(job *batchv1.Job) error {
+	return om.jobsIndexer.Update(job)
+}
+
+func (om *fakeObjectManager) DeleteJob(job *batchv1.Job) error {
+	return om.jobsIndexer.Delete(job)
+}
+
 func (om *fakeObjectManager) SetCreateStatefulPodError(err error, after int) {
 	om.createPodTracker.err = err
 	om.createPodTracker.after = after
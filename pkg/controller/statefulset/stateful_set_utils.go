@@ -29,6 +29,7 @@ import (
 	apps "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/strategicpatch"
@@ -40,6 +41,8 @@ import (
 
 	appspub "github.com/openkruise/kruise/apis/apps/pub"
 	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	policyv1alpha1 "github.com/openkruise/kruise/apis/policy/v1alpha1"
+	"github.com/openkruise/kruise/pkg/control/pubcontrol"
 	"github.com/openkruise/kruise/pkg/features"
 	apiutil "github.com/openkruise/kruise/pkg/util/api"
 	utilfeature "github.com/openkruise/kruise/pkg/util/feature"
@@ -160,10 +163,31 @@ func getPersistentVolumeClaimRetentionPolicy(set *appsv1beta1.StatefulSet) appsv
 	return policy
 }
 
+// getPodPersistentVolumeClaimRetentionPolicy returns the PVC retention policy of set, overridden by the
+// retention policy annotations of pod for this ordinal. Annotations with unknown values are ignored.
+func getPodPersistentVolumeClaimRetentionPolicy(set *appsv1beta1.StatefulSet, pod *v1.Pod) appsv1beta1.StatefulSetPersistentVolumeClaimRetentionPolicy {
+	policy := getPersistentVolumeClaimRetentionPolicy(set)
+	override := func(key string, target *appsv1beta1.PersistentVolumeClaimRetentionPolicyType) {
+		value, ok := pod.Annotations[key]
+		if !ok {
+			return
+		}
+		switch t := appsv1beta1.PersistentVolumeClaimRetentionPolicyType(value); t {
+		case appsv1beta1.RetainPersistentVolumeClaimRetentionPolicyType, appsv1beta1.DeletePersistentVolumeClaimRetentionPolicyType:
+			*target = t
+		default:
+			klog.InfoS("Unknown policy in pod annotation, ignored", "pod", klog.KObj(pod), "annotation", key, "policy", value)
+		}
+	}
+	override(appsv1beta1.PVCRetentionPolicyWhenScaledAnnotationKey, &policy.WhenScaled)
+	override(appsv1beta1.PVCRetentionPolicyWhenDeletedAnnotationKey, &policy.WhenDeleted)
+	return policy
+}
+
 // claimOwnerMatchesSetAndPod returns false if the ownerRefs of the claim are not set consistently with the
 // PVC deletion policy for the StatefulSet.
 func claimOwnerMatchesSetAndPod(claim *v1.PersistentVolumeClaim, set *appsv1beta1.StatefulSet, pod *v1.Pod) bool {
-	policy := getPersistentVolumeClaimRetentionPolicy(set)
+	policy := getPodPersistentVolumeClaimRetentionPolicy(set, pod)
 	const retain = appsv1beta1.RetainPersistentVolumeClaimRetentionPolicyType
 	const delete = appsv1beta1.DeletePersistentVolumeClaimRetentionPolicyType
 	switch {
@@ -225,7 +249,7 @@ func updateClaimOwnerRefForSetAndPod(claim *v1.PersistentVolumeClaim, set *appsv
 	updateMeta(&podMeta, "Pod")
 	setMeta := set.TypeMeta
 	updateMeta(&setMeta, "StatefulSet")
-	policy := getPersistentVolumeClaimRetentionPolicy(set)
+	policy := getPodPersistentVolumeClaimRetentionPolicy(set, pod)
 	const retain = appsv1beta1.RetainPersistentVolumeClaimRetentionPolicyType
 	const delete = appsv1beta1.DeletePersistentVolumeClaimRetentionPolicyType
 	switch {
@@ -474,6 +498,55 @@ func getMinReadySeconds(set *appsv1beta1.StatefulSet) int32 {
 	return *set.Spec.UpdateStrategy.RollingUpdate.MinReadySeconds
 }
 
+// isStartupDependenciesReady returns true if all the pods that pod depends on in startupDependencies are running
+// and available. Otherwise, the returned duration is the minimum time to wait for them to be available.
+func isStartupDependenciesReady(set *appsv1beta1.StatefulSet, replicas []*v1.Pod, pod *v1.Pod) (bool, time.Duration) {
+	ordinal := getOrdinal(pod)
+	minReadySeconds := getMinReadySeconds(set)
+	ready := true
+	var waitTime time.Duration
+	for i := range set.Spec.StartupDependencies {
+		dependency := &set.Spec.StartupDependencies[i]
+		dependsOnOrdinals := apiutil.GetReserveOrdinalIntSet(dependency.DependsOnOrdinals)
+		var selector labels.Selector
+		if dependency.DependsOnSelector != nil {
+			var err error
+			if selector, err = metav1.LabelSelectorAsSelector(dependency.DependsOnSelector); err != nil {
+				klog.ErrorS(err, "Invalid dependsOnSelector of startup dependency", "statefulSet", klog.KObj(set))
+				continue
+			}
+		}
+		isDependency := func(p *v1.Pod) bool {
+			return dependsOnOrdinals.Has(getOrdinal(p)) || (selector != nil && selector.Matches(labels.Set(p.Labels)))
+		}
+
+		if len(dependency.Ordinals) > 0 {
+			if !apiutil.GetReserveOrdinalIntSet(dependency.Ordinals).Has(ordinal) {
+				continue
+			}
+		} else if isDependency(pod) {
+			continue
+		}
+
+		for _, replica := range replicas {
+			if replica == nil || getOrdinal(replica) == ordinal || !isDependency(replica) {
+				continue
+			}
+			if !isCreated(replica) {
+				ready = false
+				continue
+			}
+			if available, wait := isRunningAndAvailable(replica, minReadySeconds); !available {
+				ready = false
+				if wait > 0 && (waitTime == 0 || wait < waitTime) {
+					waitTime = wait
+				}
+			}
+		}
+	}
+	return ready, waitTime
+}
+
 // setPodRevision sets the revision of Pod to revision by adding the StatefulSetRevisionLabel
 func setPodRevision(pod *v1.Pod, revision string) {
 	if pod.Labels == nil {
@@ -525,6 +598,9 @@ func isCurrentRevisionNeeded(set *appsv1beta1.StatefulSet, updateRevision string
 	if set.Spec.UpdateStrategy.RollingUpdate == nil {
 		return ordinal < getStartOrdinal(set)+int(set.Status.CurrentReplicas)
 	}
+	if len(set.Spec.UpdateStrategy.RollingUpdate.PartitionOrdinals) > 0 {
+		return apiutil.GetReserveOrdinalIntSet(set.Spec.UpdateStrategy.RollingUpdate.PartitionOrdinals).Has(ordinal)
+	}
 	if set.Spec.UpdateStrategy.RollingUpdate.UnorderedUpdate == nil {
 		unreservedPodsNum := 0
 		// assume all pods [0, idx) are created and only reserved pods are nil
@@ -656,6 +732,11 @@ func inconsistentStatus(set *appsv1beta1.StatefulSet, status *appsv1beta1.Statef
 		return true
 	}
 
+	if !isConditionEqual(GetStatefulsetConditition(*status, appsv1beta1.PodUnavailableBudgetThrottled),
+		GetStatefulsetConditition(set.Status, appsv1beta1.PodUnavailableBudgetThrottled)) {
+		return true
+	}
+
 	volumeClaimName2StatusIdx := map[string]int{}
 	for i, v := range status.VolumeClaims {
 		volumeClaimName2StatusIdx[v.VolumeClaimName] = i
@@ -721,6 +802,21 @@ func (do descendingOrdinal) Less(i, j int) bool {
 	return getOrdinal(do[i]) > getOrdinal(do[j])
 }
 
+// getPubsForRollingUpdate returns the PodUnavailableBudgets protecting the pods from being updated or recreated,
+// keyed by name, and the status.unavailableAllowed of each pub is how many more of its pods can be unavailable.
+func getPubsForRollingUpdate(set *appsv1beta1.StatefulSet, replicas []*v1.Pod) (map[string]*policyv1alpha1.PodUnavailableBudget, error) {
+	operation, gate := policyv1alpha1.PubDeleteOperation, features.PodUnavailableBudgetDeleteGate
+	if set.Spec.UpdateStrategy.RollingUpdate != nil &&
+		(set.Spec.UpdateStrategy.RollingUpdate.PodUpdatePolicy == appsv1beta1.InPlaceIfPossiblePodUpdateStrategyType ||
+			set.Spec.UpdateStrategy.RollingUpdate.PodUpdatePolicy == appsv1beta1.InPlaceOnlyPodUpdateStrategyType) {
+		operation, gate = policyv1alpha1.PubUpdateOperation, features.PodUnavailableBudgetUpdateGate
+	}
+	if !utilfeature.DefaultFeatureGate.Enabled(gate) {
+		return nil, nil
+	}
+	return pubcontrol.GetPubsUnavailableAllowed(replicas, operation)
+}
+
 // NewStatefulsetCondition creates a new statefulset condition.
 func NewStatefulsetCondition(conditionType apps.StatefulSetConditionType, conditionStatus v1.ConditionStatus, reason, message string) apps.StatefulSetCondition {
 	return apps.StatefulSetCondition{
@@ -757,6 +853,13 @@ func SetStatefulsetCondition(status *appsv1beta1.StatefulSetStatus, condition ap
 	status.Conditions = append(newConditions, condition)
 }
 
+func isConditionEqual(a, b *apps.StatefulSetCondition) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Status == b.Status && a.Reason == b.Reason && a.Message == b.Message
+}
+
 func filterOutCondition(conditions []apps.StatefulSetCondition, condType apps.StatefulSetConditionType) []apps.StatefulSetCondition {
 	var newCondititions []apps.StatefulSetCondition
 	for _, c := range conditions {
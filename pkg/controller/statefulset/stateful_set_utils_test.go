@@ -454,6 +454,7 @@ func TestUpdateClaimOwnerRefForSetAndPod(t *testing.T) {
 		name            string
 		scaleDownPolicy appsv1beta1.PersistentVolumeClaimRetentionPolicyType
 		setDeletePolicy appsv1beta1.PersistentVolumeClaimRetentionPolicyType
+		podAnnotations  map[string]string
 		condemned       bool
 		needsPodRef     bool
 		needsSetRef     bool
@@ -506,6 +507,36 @@ func TestUpdateClaimOwnerRefForSetAndPod(t *testing.T) {
 			needsPodRef:     true,
 			needsSetRef:     false,
 		},
+		{
+			name:            "retain, overridden to delete on scaledown by pod, condemned",
+			scaleDownPolicy: appsv1beta1.RetainPersistentVolumeClaimRetentionPolicyType,
+			setDeletePolicy: appsv1beta1.RetainPersistentVolumeClaimRetentionPolicyType,
+			podAnnotations:  map[string]string{appsv1beta1.PVCRetentionPolicyWhenScaledAnnotationKey: "Delete"},
+			condemned:       true,
+			needsPodRef:     true,
+			needsSetRef:     false,
+		},
+		{
+			name:            "delete on both, overridden to retain by pod, condemned",
+			scaleDownPolicy: appsv1beta1.DeletePersistentVolumeClaimRetentionPolicyType,
+			setDeletePolicy: appsv1beta1.DeletePersistentVolumeClaimRetentionPolicyType,
+			podAnnotations: map[string]string{
+				appsv1beta1.PVCRetentionPolicyWhenScaledAnnotationKey:  "Retain",
+				appsv1beta1.PVCRetentionPolicyWhenDeletedAnnotationKey: "Retain",
+			},
+			condemned:   true,
+			needsPodRef: false,
+			needsSetRef: false,
+		},
+		{
+			name:            "retain, unknown override by pod, condemned",
+			scaleDownPolicy: appsv1beta1.RetainPersistentVolumeClaimRetentionPolicyType,
+			setDeletePolicy: appsv1beta1.RetainPersistentVolumeClaimRetentionPolicyType,
+			podAnnotations:  map[string]string{appsv1beta1.PVCRetentionPolicyWhenScaledAnnotationKey: "Unknown"},
+			condemned:       true,
+			needsPodRef:     false,
+			needsSetRef:     false,
+		},
 	}
 	for _, tc := range testCases {
 		for _, hasPodRef := range []bool{true, false} {
@@ -520,6 +551,7 @@ func TestUpdateClaimOwnerRefForSetAndPod(t *testing.T) {
 					WhenDeleted: tc.setDeletePolicy,
 				}
 				pod := corev1.Pod{}
+				pod.Annotations = tc.podAnnotations
 				if tc.condemned {
 					pod.Name = "pod-8"
 				} else {
@@ -738,6 +770,58 @@ func TestIsRunningAndAvailable(t *testing.T) {
 	}
 }
 
+func TestIsStartupDependenciesReady(t *testing.T) {
+	set := newStatefulSet(5)
+	set.Spec.PodManagementPolicy = apps.ParallelPodManagement
+	set.Spec.StartupDependencies = []appsv1beta1.StatefulSetStartupDependency{
+		{DependsOnOrdinals: []intstr.IntOrString{intstr.FromInt32(0)}},
+		{
+			Ordinals:          []intstr.IntOrString{intstr.FromString("3-4")},
+			DependsOnSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"role": "coordinator"}},
+		},
+	}
+	replicas := make([]*corev1.Pod, 5)
+	for i := range replicas {
+		replicas[i] = newStatefulSetPod(set, i)
+	}
+	replicas[1].Labels["role"] = "coordinator"
+
+	// pod 0 is the dependency and never waits
+	if ready, _ := isStartupDependenciesReady(set, replicas, replicas[0]); !ready {
+		t.Fatalf("expected pod 0 to be ready to start")
+	}
+	// pod 0 has not been created
+	if ready, _ := isStartupDependenciesReady(set, replicas, replicas[2]); ready {
+		t.Fatalf("expected pod 2 to wait for pod 0")
+	}
+
+	markAvailable := func(pod *corev1.Pod) {
+		pod.Status.Phase = corev1.PodRunning
+		pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+	}
+	markAvailable(replicas[0])
+	if ready, _ := isStartupDependenciesReady(set, replicas, replicas[2]); !ready {
+		t.Fatalf("expected pod 2 to be ready to start")
+	}
+	// pods 3-4 also wait for the coordinator pod 1
+	if ready, _ := isStartupDependenciesReady(set, replicas, replicas[3]); ready {
+		t.Fatalf("expected pod 3 to wait for pod 1")
+	}
+
+	set.Spec.UpdateStrategy.RollingUpdate = &appsv1beta1.RollingUpdateStatefulSetStrategy{MinReadySeconds: ptr.To[int32](10)}
+	markAvailable(replicas[1])
+	now := metav1.Now()
+	replicas[1].Status.Conditions[0].LastTransitionTime = now
+	replicas[0].Status.Conditions[0].LastTransitionTime = metav1.NewTime(now.Add(-time.Minute))
+	if ready, wait := isStartupDependenciesReady(set, replicas, replicas[4]); ready || wait <= 0 || wait > 10*time.Second {
+		t.Fatalf("expected pod 4 to wait for minReadySeconds of pod 1, got %v, %v", ready, wait)
+	}
+	replicas[1].Status.Conditions[0].LastTransitionTime = metav1.NewTime(now.Add(-time.Minute))
+	if ready, _ := isStartupDependenciesReady(set, replicas, replicas[4]); !ready {
+		t.Fatalf("expected pod 4 to be ready to start")
+	}
+}
+
 func TestAscendingOrdinal(t *testing.T) {
 	set := newStatefulSet(10)
 	pods := make([]*corev1.Pod, 10)
@@ -1851,6 +1935,35 @@ func TestIsCurrentRevisionNeeded(t *testing.T) {
 			}(),
 			expectedRes: true,
 		},
+		{
+			// start ordinal 10, partitionOrdinals [10-11, 14], replicas 4
+			// 10, 11: current revision
+			// => 12: should be updated revision, 14: should be current revision
+			name: "Ordinals start 10, partitionOrdinals 10-11 and 14, create pod12",
+			statefulSet: &appsv1beta1.StatefulSet{
+				Spec: appsv1beta1.StatefulSetSpec{
+					Replicas: int32Ptr(4),
+					Ordinals: &appsv1beta1.StatefulSetOrdinals{
+						Start: 10,
+					},
+					UpdateStrategy: appsv1beta1.StatefulSetUpdateStrategy{
+						Type: apps.RollingUpdateStatefulSetStrategyType,
+						RollingUpdate: &appsv1beta1.RollingUpdateStatefulSetStrategy{
+							Partition:         int32Ptr(3),
+							PartitionOrdinals: []intstr.IntOrString{intstr.FromString("10-11"), intstr.FromInt32(14)},
+						},
+					},
+				},
+			},
+			updateRevision: updatedRevisionHash,
+			ordinal:        12,
+			replicas: func() []*corev1.Pod {
+				pods := newReplicas(10, 2, currentRevisionHash)
+				pods = append(pods, nil, nil)
+				return pods
+			}(),
+			expectedRes: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -17,14 +17,24 @@ limitations under the License.
 package statefulset
 
 import (
+	"sort"
+
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
 
 	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	apiutil "github.com/openkruise/kruise/pkg/util/api"
 	"github.com/openkruise/kruise/pkg/util/revision"
 	"github.com/openkruise/kruise/pkg/util/updatesort"
 )
 
 func sortPodsToUpdate(rollingUpdateStrategy *appsv1beta1.RollingUpdateStatefulSetStrategy, updateRevision string, totalReplicas int32, replicas []*v1.Pod) []int {
+	if rollingUpdateStrategy != nil && len(rollingUpdateStrategy.PartitionOrdinals) > 0 {
+		return sortPodsToUpdateByPartitionOrdinals(rollingUpdateStrategy, updateRevision, replicas)
+	}
+
 	var updateMin int
 	if rollingUpdateStrategy != nil && rollingUpdateStrategy.Partition != nil {
 		updateMin = int(*rollingUpdateStrategy.Partition)
@@ -43,7 +53,7 @@ func sortPodsToUpdate(rollingUpdateStrategy *appsv1beta1.RollingUpdateStatefulSe
 			}
 			indexes = append(indexes, target)
 		}
-		return indexes
+		return sortLeaderLast(rollingUpdateStrategy, replicas, indexes)
 	}
 
 	priorityStrategy := rollingUpdateStrategy.UnorderedUpdate.PriorityStrategy
@@ -72,5 +82,63 @@ func sortPodsToUpdate(rollingUpdateStrategy *appsv1beta1.RollingUpdateStatefulSe
 		allIdxs = allIdxs[:maxUpdate]
 	}
 
-	return allIdxs
+	return sortLeaderLast(rollingUpdateStrategy, replicas, allIdxs)
+}
+
+// sortPodsToUpdateByPartitionOrdinals returns the indexes of pods whose ordinals are not in partitionOrdinals,
+// which means all of them should be updated.
+func sortPodsToUpdateByPartitionOrdinals(rollingUpdateStrategy *appsv1beta1.RollingUpdateStatefulSetStrategy, updateRevision string, replicas []*v1.Pod) []int {
+	partitionOrdinals := apiutil.GetReserveOrdinalIntSet(rollingUpdateStrategy.PartitionOrdinals)
+
+	var updatedIdxs []int
+	var waitUpdateIdxs []int
+	for target := len(replicas) - 1; target >= 0; target-- {
+		if replicas[target] == nil || partitionOrdinals.Has(getOrdinal(replicas[target])) {
+			continue
+		}
+		if rollingUpdateStrategy.UnorderedUpdate == nil {
+			waitUpdateIdxs = append(waitUpdateIdxs, target)
+		} else if isTerminating(replicas[target]) || revision.IsPodUpdate(replicas[target], updateRevision) {
+			updatedIdxs = append(updatedIdxs, target)
+		} else {
+			waitUpdateIdxs = append(waitUpdateIdxs, target)
+		}
+	}
+
+	if rollingUpdateStrategy.UnorderedUpdate != nil && rollingUpdateStrategy.UnorderedUpdate.PriorityStrategy != nil {
+		waitUpdateIdxs = updatesort.NewPrioritySorter(rollingUpdateStrategy.UnorderedUpdate.PriorityStrategy).Sort(replicas, waitUpdateIdxs)
+	}
+
+	return sortLeaderLast(rollingUpdateStrategy, replicas, append(updatedIdxs, waitUpdateIdxs...))
+}
+
+// sortLeaderLast moves the leader pods to the end of indexes, so that the followers will be updated first.
+func sortLeaderLast(rollingUpdateStrategy *appsv1beta1.RollingUpdateStatefulSetStrategy, replicas []*v1.Pod, indexes []int) []int {
+	if rollingUpdateStrategy == nil || rollingUpdateStrategy.RoleAwareUpdate == nil {
+		return indexes
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return !isLeaderPod(rollingUpdateStrategy.RoleAwareUpdate, replicas[indexes[i]]) &&
+			isLeaderPod(rollingUpdateStrategy.RoleAwareUpdate, replicas[indexes[j]])
+	})
+	return indexes
+}
+
+// isLeaderPod returns true if the pod is recognized as leader by the role-aware update strategy.
+func isLeaderPod(strategy *appsv1beta1.RoleAwareUpdateStrategy, pod *v1.Pod) bool {
+	if strategy == nil || pod == nil {
+		return false
+	}
+	if strategy.LeaderSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(strategy.LeaderSelector)
+		if err != nil || selector.Empty() {
+			return false
+		}
+		return selector.Matches(labels.Set(pod.Labels))
+	}
+	if strategy.LeaderConditionType != "" {
+		_, condition := podutil.GetPodCondition(&pod.Status, strategy.LeaderConditionType)
+		return condition != nil && condition.Status == v1.ConditionTrue
+	}
+	return false
 }
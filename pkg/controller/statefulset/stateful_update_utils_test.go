@@ -23,6 +23,7 @@ import (
 	apps "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	appspub "github.com/openkruise/kruise/apis/apps/pub"
 	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
@@ -156,6 +157,69 @@ func TestSortPodsToUpdate(t *testing.T) {
 			},
 			expected: []int{8, 7, 1, 0},
 		},
+		{
+			strategy: &appsv1beta1.RollingUpdateStatefulSetStrategy{
+				RoleAwareUpdate: &appsv1beta1.RoleAwareUpdateStrategy{
+					LeaderSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"role": "leader"}},
+				},
+			},
+			updateRevision: "r1",
+			totalReplicas:  3,
+			replicas: []*v1.Pod{
+				{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{apps.ControllerRevisionHashLabelKey: "r0"}}},
+				{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{apps.ControllerRevisionHashLabelKey: "r0"}}},
+				{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{apps.ControllerRevisionHashLabelKey: "r0", "role": "leader"}}},
+			},
+			expected: []int{1, 0, 2},
+		},
+		{
+			strategy: &appsv1beta1.RollingUpdateStatefulSetStrategy{
+				UnorderedUpdate: &appsv1beta1.UnorderedUpdateStrategy{},
+				RoleAwareUpdate: &appsv1beta1.RoleAwareUpdateStrategy{LeaderConditionType: "Leader"},
+			},
+			updateRevision: "r1",
+			totalReplicas:  4,
+			replicas: []*v1.Pod{
+				{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{apps.ControllerRevisionHashLabelKey: "r0"}},
+					Status: v1.PodStatus{Conditions: []v1.PodCondition{{Type: "Leader", Status: v1.ConditionTrue}}}},
+				{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{apps.ControllerRevisionHashLabelKey: "r0"}},
+					Status: v1.PodStatus{Conditions: []v1.PodCondition{{Type: "Leader", Status: v1.ConditionFalse}}}},
+				{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{apps.ControllerRevisionHashLabelKey: "r1"}}},
+				{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{apps.ControllerRevisionHashLabelKey: "r0"}}},
+			},
+			expected: []int{2, 3, 1, 0},
+		},
+		{
+			strategy: &appsv1beta1.RollingUpdateStatefulSetStrategy{
+				Partition:         func() *int32 { var i int32 = 3; return &i }(),
+				PartitionOrdinals: []intstr.IntOrString{intstr.FromString("10-11"), intstr.FromInt32(14)},
+			},
+			updateRevision: "r1",
+			totalReplicas:  4,
+			replicas: []*v1.Pod{
+				{ObjectMeta: metav1.ObjectMeta{Name: "foo-10", Labels: map[string]string{apps.ControllerRevisionHashLabelKey: "r0"}}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "foo-11", Labels: map[string]string{apps.ControllerRevisionHashLabelKey: "r0"}}},
+				nil,
+				{ObjectMeta: metav1.ObjectMeta{Name: "foo-13", Labels: map[string]string{apps.ControllerRevisionHashLabelKey: "r0"}}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "foo-14", Labels: map[string]string{apps.ControllerRevisionHashLabelKey: "r0"}}},
+			},
+			expected: []int{3},
+		},
+		{
+			strategy: &appsv1beta1.RollingUpdateStatefulSetStrategy{
+				UnorderedUpdate:   &appsv1beta1.UnorderedUpdateStrategy{},
+				PartitionOrdinals: []intstr.IntOrString{intstr.FromString("0-1")},
+			},
+			updateRevision: "r1",
+			totalReplicas:  4,
+			replicas: []*v1.Pod{
+				{ObjectMeta: metav1.ObjectMeta{Name: "foo-0", Labels: map[string]string{apps.ControllerRevisionHashLabelKey: "r0"}}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "foo-1", Labels: map[string]string{apps.ControllerRevisionHashLabelKey: "r1"}}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "foo-2", Labels: map[string]string{apps.ControllerRevisionHashLabelKey: "r0"}}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "foo-3", Labels: map[string]string{apps.ControllerRevisionHashLabelKey: "r1"}}},
+			},
+			expected: []int{3, 2},
+		},
 	}
 
 	defer utilfeature.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.PreparingUpdateAsUpdate, true)()
@@ -30,6 +30,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientset "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
 	appslisters "k8s.io/client-go/listers/apps/v1"
@@ -51,9 +52,11 @@ import (
 
 	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
 	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	policyv1alpha1 "github.com/openkruise/kruise/apis/policy/v1alpha1"
 	"github.com/openkruise/kruise/pkg/client"
 	kruiseclientset "github.com/openkruise/kruise/pkg/client/clientset/versioned"
 	kruiseappslisters "github.com/openkruise/kruise/pkg/client/listers/apps/v1beta1"
+	"github.com/openkruise/kruise/pkg/control/pubcontrol"
 	"github.com/openkruise/kruise/pkg/features"
 	"github.com/openkruise/kruise/pkg/util"
 	utilclient "github.com/openkruise/kruise/pkg/util/client"
@@ -132,6 +135,10 @@ func newReconciler(mgr manager.Manager) (reconcile.Reconciler, error) {
 	if err != nil {
 		return nil, err
 	}
+	cmInformer, err := cacher.GetInformerForKind(context.TODO(), v1.SchemeGroupVersion.WithKind("ConfigMap"))
+	if err != nil {
+		return nil, err
+	}
 
 	statefulSetLister := kruiseappslisters.NewStatefulSetLister(statefulSetInformer.(toolscache.SharedIndexInformer).GetIndexer())
 	podLister := corelisters.NewPodLister(podInformer.(toolscache.SharedIndexInformer).GetIndexer())
@@ -151,6 +158,7 @@ func newReconciler(mgr manager.Manager) (reconcile.Reconciler, error) {
 	sigsruntimeClient = utilclient.NewClientFromManager(mgr, "statefulset-controller")
 
 	return &ReconcileStatefulSet{
+		kubeClient:   genericClient.KubeClient,
 		kruiseClient: genericClient.KruiseClient,
 		control: NewDefaultStatefulSetControl(
 			NewStatefulPodControl(
@@ -168,6 +176,7 @@ func newReconciler(mgr manager.Manager) (reconcile.Reconciler, error) {
 		podControl: kubecontroller.RealPodControl{KubeClient: genericClient.KubeClient, Recorder: recorder},
 		podLister:  podLister,
 		setLister:  statefulSetLister,
+		cmLister:   corelisters.NewConfigMapLister(cmInformer.(toolscache.SharedIndexInformer).GetIndexer()),
 	}, nil
 }
 
@@ -176,6 +185,7 @@ var _ reconcile.Reconciler = &ReconcileStatefulSet{}
 // ReconcileStatefulSet reconciles a StatefulSet object
 type ReconcileStatefulSet struct {
 	// client interface
+	kubeClient   clientset.Interface
 	kruiseClient kruiseclientset.Interface
 	// control returns an interface capable of syncing a stateful set.
 	// Abstracted out for testing.
@@ -186,6 +196,8 @@ type ReconcileStatefulSet struct {
 	podLister corelisters.PodLister
 	// setLister is able to list/get stateful sets from a shared informer's store
 	setLister kruiseappslisters.StatefulSetLister
+	// cmLister is able to list/get the coordination configmaps from a shared informer's store
+	cmLister corelisters.ConfigMapLister
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
@@ -221,6 +233,22 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// Watch for changes to ConfigMap coordinating the ordinals of StatefulSets
+	err = c.Watch(source.Kind(mgr.GetCache(), &v1.ConfigMap{}, &coordinationConfigMapEventHandler{setLister: r.(*ReconcileStatefulSet).setLister}))
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to PodUnavailableBudget, to continue the rolling updates throttled by it
+	if utilfeature.DefaultFeatureGate.Enabled(features.PodUnavailableBudgetDeleteGate) ||
+		utilfeature.DefaultFeatureGate.Enabled(features.PodUnavailableBudgetUpdateGate) {
+		err = c.Watch(source.Kind(mgr.GetCache(), &policyv1alpha1.PodUnavailableBudget{},
+			&pubcontrol.EnqueueWorkloadsForPub{Reader: mgr.GetCache(), GroupKind: controllerKind.GroupKind()}))
+		if err != nil {
+			return err
+		}
+	}
+
 	// Watch for changes to Pod created by StatefulSet
 	err = c.Watch(source.Kind(mgr.GetCache(), &v1.Pod{}, handler.TypedEnqueueRequestForOwner[*v1.Pod](
 		mgr.GetScheme(), mgr.GetRESTMapper(), &appsv1beta1.StatefulSet{}, handler.OnlyControllerOwner())))
@@ -238,11 +266,15 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 // +kubebuilder:rbac:groups=core,resources=pods/resize,verbs=get;patch;update
 // +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=persistentvolumes,verbs=get;update
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update
 // +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups=apps,resources=controllerrevisions,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps.kruise.io,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps.kruise.io,resources=statefulsets/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=apps.kruise.io,resources=statefulsets/finalizers,verbs=update
+// +kubebuilder:rbac:groups=policy.kruise.io,resources=podunavailablebudgets,verbs=get;list;watch
 
 // Reconcile reads that state of the cluster for a StatefulSet object and makes changes based on the state read
 // and what is in the StatefulSet.Spec
@@ -361,7 +393,11 @@ func (ssc *ReconcileStatefulSet) getPodsForStatefulSet(ctx context.Context, set
 func (ssc *ReconcileStatefulSet) syncStatefulSet(ctx context.Context, set *appsv1beta1.StatefulSet, pods []*v1.Pod) error {
 	klog.V(4).InfoS("Syncing StatefulSet with pods", "statefulSet", klog.KObj(set), "podCount", len(pods))
 	// TODO: investigate where we mutate the set during the update as it is not obvious.
-	if err := ssc.control.UpdateStatefulSet(ctx, set.DeepCopy(), pods); err != nil {
+	set = set.DeepCopy()
+	if err := ssc.coordinateOrdinals(ctx, set, pods); err != nil {
+		return err
+	}
+	if err := ssc.control.UpdateStatefulSet(ctx, set, pods); err != nil {
 		return err
 	}
 	klog.V(4).InfoS("Successfully synced StatefulSet", "statefulSet", klog.KObj(set))
@@ -789,6 +789,7 @@ func NewStatefulSetController(
 
 	ssc := &StatefulSetController{
 		ReconcileStatefulSet: ReconcileStatefulSet{
+			kubeClient:   kubeClient,
 			kruiseClient: kruiseClient,
 			control: NewDefaultStatefulSetControl(
 				NewStatefulPodControl(
@@ -0,0 +1,295 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statefulset
+
+import (
+	"fmt"
+	"hash/fnv"
+
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog/v2"
+
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	"github.com/openkruise/kruise/pkg/features"
+	utilfeature "github.com/openkruise/kruise/pkg/util/feature"
+	"github.com/openkruise/kruise/pkg/util/pvc"
+)
+
+const (
+	// pvcMigrationSuffix is the suffix of the names of the new claim and the copy Job during pvc migration.
+	pvcMigrationSuffix = "-migration"
+	// pvcMigrationVolumeAnnotationKey is added to the copy Job after the data has been copied,
+	// recording the volume of the new claim to be bound to the original claim name.
+	pvcMigrationVolumeAnnotationKey = "apps.kruise.io/pvc-migration-volume"
+	// pvcMigrationReclaimPolicyAnnotationKey is added to the volume of the new claim,
+	// recording its original reclaim policy before it is retained for swapping.
+	pvcMigrationReclaimPolicyAnnotationKey = "apps.kruise.io/pvc-migration-reclaim-policy"
+
+	pvcMigrationSourcePath = "/source"
+	pvcMigrationTargetPath = "/target"
+)
+
+var defaultPVCMigrationCommand = []string{"sh", "-c", "cp -a /source/. /target/"}
+
+// isPVCMigrationEnabled returns true if the claims of set should be migrated when they are incompatible with the templates.
+func isPVCMigrationEnabled(set *appsv1beta1.StatefulSet) bool {
+	return utilfeature.DefaultFeatureGate.Enabled(features.StatefulSetAutoResizePVCGate) &&
+		set.Spec.VolumeClaimUpdateStrategy.Type == appsv1beta1.OnPodRollingUpdateVolumeClaimUpdateStrategyType &&
+		set.Spec.VolumeClaimUpdateStrategy.Migration != nil
+}
+
+// getPVCMigrationName returns the name of the new claim and the copy Job for the claim to migrate.
+// It is no longer than 63 characters, because the Job name is used as label value of its pods.
+func getPVCMigrationName(claimName string) string {
+	name := claimName + pvcMigrationSuffix
+	if len(name) <= validation.DNS1123LabelMaxLength {
+		return name
+	}
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(claimName))
+	prefix := claimName[:validation.DNS1123LabelMaxLength-len(pvcMigrationSuffix)-9]
+	return fmt.Sprintf("%s-%08x%s", prefix, hash.Sum32(), pvcMigrationSuffix)
+}
+
+// IsClaimsNeedMigration returns true if any claim of the pod should be migrated to its template.
+func (spc *StatefulPodControl) IsClaimsNeedMigration(set *appsv1beta1.StatefulSet, pod *v1.Pod) (bool, error) {
+	if !isPVCMigrationEnabled(set) {
+		return false, nil
+	}
+	fn := func(claim, template *v1.PersistentVolumeClaim) (bool, error) {
+		return pvc.IsClaimCompatibleWithoutSize(claim, template), nil
+	}
+	compatible, err := spc.handlePVCWithCustomFn(set, pod, true, fn)
+	return !compatible && err == nil, err
+}
+
+// MigrateClaims migrates the claims of the pod whose storageClassName or accessModes are different from the templates.
+// It should be called before the pod is created, so that the data will not be modified during copying.
+// It returns true if all the claims of the pod have been migrated.
+func (spc *StatefulPodControl) MigrateClaims(set *appsv1beta1.StatefulSet, pod *v1.Pod) (bool, error) {
+	if !isPVCMigrationEnabled(set) {
+		return true, nil
+	}
+	completed := true
+	claims := getPersistentVolumeClaims(set, pod)
+	for i := range set.Spec.VolumeClaimTemplates {
+		template := &set.Spec.VolumeClaimTemplates[i]
+		expected := claims[template.Name]
+		migrated, err := spc.migrateClaim(set, pod, template, &expected)
+		if err != nil {
+			return false, err
+		}
+		completed = completed && migrated
+	}
+	return completed, nil
+}
+
+// migrateClaim copies data from the claim to a new claim created from the template with a Job,
+// then retains the volume of the new claim and binds it to a claim with the original name.
+func (spc *StatefulPodControl) migrateClaim(set *appsv1beta1.StatefulSet, pod *v1.Pod, template, expected *v1.PersistentVolumeClaim) (bool, error) {
+	migrationName := getPVCMigrationName(expected.Name)
+	job, err := spc.objectMgr.GetJob(set.Namespace, migrationName)
+	if apierrors.IsNotFound(err) {
+		job = nil
+	} else if err != nil {
+		return false, fmt.Errorf("could not get pvc migration job %s: %w", migrationName, err)
+	}
+	claim, err := spc.objectMgr.GetClaim(set.Namespace, expected.Name)
+	if apierrors.IsNotFound(err) {
+		claim = nil
+	} else if err != nil {
+		return false, fmt.Errorf("could not get claim %s: %w", expected.Name, err)
+	}
+
+	if job != nil && job.Annotations[pvcMigrationVolumeAnnotationKey] != "" {
+		return spc.swapMigratedClaim(set, pod, job, claim, expected)
+	}
+	if claim == nil || claim.DeletionTimestamp != nil || pvc.IsClaimCompatibleWithoutSize(claim, template) {
+		return true, nil
+	}
+
+	target, err := spc.objectMgr.GetClaim(set.Namespace, migrationName)
+	if apierrors.IsNotFound(err) {
+		target = expected.DeepCopy()
+		target.Name = migrationName
+		target.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(set, appsv1beta1.SchemeGroupVersion.WithKind("StatefulSet"))}
+		err = spc.objectMgr.CreateClaim(target)
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			spc.recorder.Eventf(set, v1.EventTypeWarning, "FailedMigratePVC", "create claim %s to migrate %s failed error: %s", migrationName, claim.Name, err)
+			return false, fmt.Errorf("could not create claim %s to migrate %s: %w", migrationName, claim.Name, err)
+		}
+		spc.recorder.Eventf(set, v1.EventTypeNormal, "MigratingPVC", "create claim %s to migrate %s", migrationName, claim.Name)
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("could not get claim %s: %w", migrationName, err)
+	} else if target.DeletionTimestamp != nil {
+		return false, nil
+	}
+
+	if job == nil {
+		job = newPVCMigrationJob(set, migrationName, claim.Name, target.Name)
+		if err := spc.objectMgr.CreateJob(job); err != nil && !apierrors.IsAlreadyExists(err) {
+			spc.recorder.Eventf(set, v1.EventTypeWarning, "FailedMigratePVC", "create job %s to migrate %s failed error: %s", migrationName, claim.Name, err)
+			return false, fmt.Errorf("could not create job %s to migrate %s: %w", migrationName, claim.Name, err)
+		}
+		spc.recorder.Eventf(set, v1.EventTypeNormal, "MigratingPVC", "create job %s to copy data from %s to %s", migrationName, claim.Name, target.Name)
+		return false, nil
+	}
+
+	if isJobFinished(job, batchv1.JobFailed) {
+		spc.recorder.Eventf(set, v1.EventTypeWarning, "FailedMigratePVC", "job %s to migrate %s failed, delete the job to retry", job.Name, claim.Name)
+		return false, nil
+	} else if !isJobFinished(job, batchv1.JobComplete) || target.Spec.VolumeName == "" {
+		klog.V(4).InfoS("StatefulSet was waiting for pvc migration job", "statefulSet", klog.KObj(set), "job", klog.KObj(job))
+		return false, nil
+	}
+
+	// retain the volume of the new claim, so that it can be bound to the original claim name
+	pv, err := spc.objectMgr.GetPersistentVolume(target.Spec.VolumeName)
+	if err != nil {
+		return false, fmt.Errorf("could not get volume %s of claim %s: %w", target.Spec.VolumeName, target.Name, err)
+	}
+	if pv.Spec.PersistentVolumeReclaimPolicy != v1.PersistentVolumeReclaimRetain {
+		pv = pv.DeepCopy()
+		if pv.Annotations == nil {
+			pv.Annotations = map[string]string{}
+		}
+		pv.Annotations[pvcMigrationReclaimPolicyAnnotationKey] = string(pv.Spec.PersistentVolumeReclaimPolicy)
+		pv.Spec.PersistentVolumeReclaimPolicy = v1.PersistentVolumeReclaimRetain
+		if err := spc.objectMgr.UpdatePersistentVolume(pv); err != nil {
+			return false, fmt.Errorf("could not retain volume %s of claim %s: %w", pv.Name, target.Name, err)
+		}
+	}
+	job = job.DeepCopy()
+	if job.Annotations == nil {
+		job.Annotations = map[string]string{}
+	}
+	job.Annotations[pvcMigrationVolumeAnnotationKey] = pv.Name
+	if err := spc.objectMgr.UpdateJob(job); err != nil {
+		return false, fmt.Errorf("could not update pvc migration job %s: %w", job.Name, err)
+	}
+	return false, nil
+}
+
+// swapMigratedClaim deletes the old claim and the new claim, then binds the retained volume to a claim with the original name.
+func (spc *StatefulPodControl) swapMigratedClaim(set *appsv1beta1.StatefulSet, pod *v1.Pod, job *batchv1.Job, claim, expected *v1.PersistentVolumeClaim) (bool, error) {
+	pvName := job.Annotations[pvcMigrationVolumeAnnotationKey]
+	if claim != nil && claim.Spec.VolumeName == pvName {
+		if err := spc.objectMgr.DeleteJob(job); err != nil && !apierrors.IsNotFound(err) {
+			return false, fmt.Errorf("could not delete pvc migration job %s: %w", job.Name, err)
+		}
+		spc.recorder.Eventf(set, v1.EventTypeNormal, "SuccessfulMigratePVC", "migrate claim %s to volume %s successful", claim.Name, pvName)
+		return true, nil
+	}
+
+	var deleting bool
+	for _, name := range []string{expected.Name, getPVCMigrationName(expected.Name)} {
+		c, err := spc.objectMgr.GetClaim(set.Namespace, name)
+		if apierrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return false, fmt.Errorf("could not get claim %s: %w", name, err)
+		}
+		deleting = true
+		if c.DeletionTimestamp == nil {
+			if err := spc.objectMgr.DeleteClaim(c); err != nil && !apierrors.IsNotFound(err) {
+				spc.recordClaimEvent("delete", set, pod, c, err)
+				return false, err
+			}
+		}
+	}
+	if deleting {
+		return false, nil
+	}
+
+	pv, err := spc.objectMgr.GetPersistentVolume(pvName)
+	if err != nil {
+		return false, fmt.Errorf("could not get volume %s: %w", pvName, err)
+	}
+	// pre-bind the volume to the claim to create, so that it will never be available to other claims
+	claimRef := &v1.ObjectReference{Kind: "PersistentVolumeClaim", APIVersion: "v1", Namespace: set.Namespace, Name: expected.Name}
+	if policy, ok := pv.Annotations[pvcMigrationReclaimPolicyAnnotationKey]; ok || !apiequality.Semantic.DeepEqual(pv.Spec.ClaimRef, claimRef) {
+		pv = pv.DeepCopy()
+		if ok {
+			pv.Spec.PersistentVolumeReclaimPolicy = v1.PersistentVolumeReclaimPolicy(policy)
+			delete(pv.Annotations, pvcMigrationReclaimPolicyAnnotationKey)
+		}
+		pv.Spec.ClaimRef = claimRef
+		if err := spc.objectMgr.UpdatePersistentVolume(pv); err != nil {
+			return false, fmt.Errorf("could not bind volume %s to claim %s: %w", pvName, expected.Name, err)
+		}
+	}
+
+	newClaim := expected.DeepCopy()
+	newClaim.Spec.VolumeName = pvName
+	err = spc.objectMgr.CreateClaim(newClaim)
+	if err != nil && apierrors.IsAlreadyExists(err) {
+		return false, nil
+	}
+	spc.recordClaimEvent("create", set, pod, newClaim, err)
+	return false, err
+}
+
+func newPVCMigrationJob(set *appsv1beta1.StatefulSet, name, sourceClaim, targetClaim string) *batchv1.Job {
+	migration := set.Spec.VolumeClaimUpdateStrategy.Migration
+	command := migration.Command
+	if len(command) == 0 {
+		command = defaultPVCMigrationCommand
+	}
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       set.Namespace,
+			Name:            name,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(set, appsv1beta1.SchemeGroupVersion.WithKind("StatefulSet"))},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: migration.BackoffLimit,
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					RestartPolicy: v1.RestartPolicyNever,
+					Containers: []v1.Container{{
+						Name:    "migrate",
+						Image:   migration.Image,
+						Command: command,
+						VolumeMounts: []v1.VolumeMount{
+							{Name: "source", MountPath: pvcMigrationSourcePath, ReadOnly: true},
+							{Name: "target", MountPath: pvcMigrationTargetPath},
+						},
+					}},
+					Volumes: []v1.Volume{
+						{Name: "source", VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: sourceClaim, ReadOnly: true}}},
+						{Name: "target", VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: targetClaim}}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func isJobFinished(job *batchv1.Job, conditionType batchv1.JobConditionType) bool {
+	for _, c := range job.Status.Conditions {
+		if c.Type == conditionType && c.Status == v1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statefulset
+
+import (
+	"strings"
+	"testing"
+
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kubernetes/pkg/controller"
+
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	kruisefake "github.com/openkruise/kruise/pkg/client/clientset/versioned/fake"
+	kruiseinformers "github.com/openkruise/kruise/pkg/client/informers/externalversions"
+	"github.com/openkruise/kruise/pkg/features"
+	utilfeature "github.com/openkruise/kruise/pkg/util/feature"
+)
+
+func TestGetPVCMigrationName(t *testing.T) {
+	if name := getPVCMigrationName("datadir-foo-0"); name != "datadir-foo-0-migration" {
+		t.Fatalf("unexpected migration name %s", name)
+	}
+	longName := strings.Repeat("a", 60) + "-foo-0"
+	name := getPVCMigrationName(longName)
+	if len(name) > validation.DNS1123LabelMaxLength || !strings.HasSuffix(name, pvcMigrationSuffix) {
+		t.Fatalf("unexpected migration name %s", name)
+	}
+	if name == getPVCMigrationName(strings.Repeat("a", 60)+"-foo-1") {
+		t.Fatalf("expected different migration names for different claims")
+	}
+}
+
+func TestMigrateClaims(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.StatefulSetAutoResizePVCGate, true)()
+
+	oldSC, newSC := "old", "new"
+	set := newStatefulSetWithGivenSC(1, 1, []*string{&newSC})
+	set.Spec.VolumeClaimUpdateStrategy = appsv1beta1.VolumeClaimUpdateStrategy{
+		Type:      appsv1beta1.OnPodRollingUpdateVolumeClaimUpdateStrategyType,
+		Migration: &appsv1beta1.VolumeClaimMigrationStrategy{Image: "busybox"},
+	}
+	pod := newStatefulSetPod(set, 0)
+
+	informerFactory := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), controller.NoResyncPeriodFunc())
+	kruiseInformerFactory := kruiseinformers.NewSharedInformerFactory(kruisefake.NewSimpleClientset(), controller.NoResyncPeriodFunc())
+	om := newFakeObjectManager(informerFactory, kruiseInformerFactory)
+	spc := NewStatefulPodControlFromManager(om, &noopRecorder{})
+
+	claim := getPersistentVolumeClaims(set, pod)["datadir-0"]
+	claim.Spec.StorageClassName = &oldSC
+	claim.Spec.VolumeName = "pv-old"
+	if err := om.CreateClaim(&claim); err != nil {
+		t.Fatal(err)
+	}
+	migrationName := getPVCMigrationName(claim.Name)
+
+	migrate := func(expected bool) {
+		t.Helper()
+		migrated, err := spc.MigrateClaims(set, pod)
+		if err != nil {
+			t.Fatalf("failed to migrate claims: %v", err)
+		}
+		if migrated != expected {
+			t.Fatalf("expected migrated %v, got %v", expected, migrated)
+		}
+	}
+
+	if need, err := spc.IsClaimsNeedMigration(set, pod); err != nil || !need {
+		t.Fatalf("expected claims need migration, got %v, %v", need, err)
+	}
+
+	// create the new claim and the copy job
+	migrate(false)
+	target, err := om.GetClaim(set.Namespace, migrationName)
+	if err != nil || *target.Spec.StorageClassName != newSC {
+		t.Fatalf("expected new claim with storage class %s, got %v, %v", newSC, target, err)
+	}
+	migrate(false)
+	job, err := om.GetJob(set.Namespace, migrationName)
+	if err != nil {
+		t.Fatalf("expected copy job, got %v", err)
+	}
+	volumes := job.Spec.Template.Spec.Volumes
+	if len(volumes) != 2 || volumes[0].PersistentVolumeClaim.ClaimName != claim.Name || volumes[1].PersistentVolumeClaim.ClaimName != migrationName {
+		t.Fatalf("unexpected volumes of copy job %v", volumes)
+	}
+
+	// wait for the job to complete
+	migrate(false)
+	target = target.DeepCopy()
+	target.Spec.VolumeName = "pv-new"
+	if err := om.UpdateClaim(target); err != nil {
+		t.Fatal(err)
+	}
+	if err := om.pvsIndexer.Add(&v1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-new"},
+		Spec: v1.PersistentVolumeSpec{
+			PersistentVolumeReclaimPolicy: v1.PersistentVolumeReclaimDelete,
+			ClaimRef:                      &v1.ObjectReference{Namespace: set.Namespace, Name: migrationName},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	job = job.DeepCopy()
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+	if err := om.UpdateJob(job); err != nil {
+		t.Fatal(err)
+	}
+
+	// retain the new volume
+	migrate(false)
+	pv, _ := om.GetPersistentVolume("pv-new")
+	if pv.Spec.PersistentVolumeReclaimPolicy != v1.PersistentVolumeReclaimRetain {
+		t.Fatalf("expected volume retained, got %v", pv.Spec.PersistentVolumeReclaimPolicy)
+	}
+	if job, _ = om.GetJob(set.Namespace, migrationName); job.Annotations[pvcMigrationVolumeAnnotationKey] != "pv-new" {
+		t.Fatalf("expected volume recorded in job, got %v", job.Annotations)
+	}
+
+	// delete the old claim and the new claim
+	migrate(false)
+	for _, name := range []string{claim.Name, migrationName} {
+		if _, err := om.GetClaim(set.Namespace, name); !apierrors.IsNotFound(err) {
+			t.Fatalf("expected claim %s deleted, got %v", name, err)
+		}
+	}
+
+	// pre-bind the new volume to the original claim name
+	migrate(false)
+	pv, _ = om.GetPersistentVolume("pv-new")
+	if pv.Spec.PersistentVolumeReclaimPolicy != v1.PersistentVolumeReclaimDelete {
+		t.Fatalf("expected volume with original reclaim policy, got %v", pv.Spec)
+	}
+	if ref := pv.Spec.ClaimRef; ref == nil || ref.Namespace != set.Namespace || ref.Name != claim.Name || ref.UID != "" {
+		t.Fatalf("expected volume pre-bound to claim %s, got %v", claim.Name, ref)
+	}
+	swapped, err := om.GetClaim(set.Namespace, claim.Name)
+	if err != nil || swapped.Spec.VolumeName != "pv-new" || *swapped.Spec.StorageClassName != newSC {
+		t.Fatalf("expected claim bound to the new volume, got %v, %v", swapped, err)
+	}
+
+	// clean up the job
+	migrate(true)
+	if _, err := om.GetJob(set.Namespace, migrationName); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected copy job deleted, got %v", err)
+	}
+	if need, err := spc.IsClaimsNeedMigration(set, pod); err != nil || need {
+		t.Fatalf("expected claims need no migration, got %v, %v", need, err)
+	}
+}
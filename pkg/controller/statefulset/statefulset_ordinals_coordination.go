@@ -0,0 +1,271 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statefulset
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	kruiseappslisters "github.com/openkruise/kruise/pkg/client/listers/apps/v1beta1"
+	"github.com/openkruise/kruise/pkg/features"
+	apiutil "github.com/openkruise/kruise/pkg/util/api"
+	utilfeature "github.com/openkruise/kruise/pkg/util/feature"
+)
+
+// coordinateOrdinals assigns the ordinals of set within the ordinal space shared with other StatefulSets,
+// reserves the unassigned ordinals below the highest assigned one, and records the ordinals occupied by set
+// into the coordination ConfigMap. The ordinals already assigned to set are kept stable, and the ordinals
+// released by the others are only taken when set scales up.
+// It must succeed before any pod is created, and the conflict of updating ConfigMap guarantees that
+// two StatefulSets never occupy the same ordinal.
+func (ssc *ReconcileStatefulSet) coordinateOrdinals(ctx context.Context, set *appsv1beta1.StatefulSet, pods []*v1.Pod) error {
+	if !utilfeature.DefaultFeatureGate.Enabled(features.StatefulSetStartOrdinal) ||
+		set.Spec.Ordinals == nil || set.Spec.Ordinals.Coordination == nil {
+		return nil
+	}
+
+	namespace, name := getCoordinationConfigMap(set)
+	cm, err := ssc.cmLister.ConfigMaps(namespace).Get(name)
+	notFound := errors.IsNotFound(err)
+	if err != nil && !notFound {
+		return err
+	}
+	if notFound {
+		cm = &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+	}
+
+	setKey := getCoordinationKey(set)
+	data := make(map[string]string, len(cm.Data)+1)
+	recorded := sets.New[int]()
+	others := sets.New[int]()
+	for key, value := range cm.Data {
+		if key != setKey {
+			ownerNamespace, ownerName := parseCoordinationKey(key, namespace)
+			if _, err := ssc.setLister.StatefulSets(ownerNamespace).Get(ownerName); errors.IsNotFound(err) {
+				// the StatefulSet has been deleted, so release its ordinals
+				klog.V(4).InfoS("Released ordinals of deleted StatefulSet", "statefulSet", klog.KObj(set), "configMap", klog.KObj(cm), "deleted", key)
+				continue
+			}
+		}
+		ordinals, err := parseCoordinatedOrdinals(value)
+		if err != nil {
+			return fmt.Errorf("failed to parse ordinals of %s in ConfigMap %s/%s: %v", key, namespace, name, err)
+		}
+		if key == setKey {
+			recorded = apiutil.GetReserveOrdinalIntSet(ordinals)
+			continue
+		}
+		data[key] = value
+		others = others.Union(apiutil.GetReserveOrdinalIntSet(ordinals))
+	}
+
+	podOrdinals := sets.New[int]()
+	for _, pod := range pods {
+		if ord := getOrdinal(pod); ord >= 0 {
+			podOrdinals.Insert(ord)
+		}
+	}
+	assigned := assignCoordinatedOrdinals(set, recorded, others, podOrdinals)
+	if assigned.Len() > 0 {
+		maxOrdinal := sets.List(assigned)[assigned.Len()-1]
+		for ord := getStartOrdinal(set); ord < maxOrdinal; ord++ {
+			if !assigned.Has(ord) {
+				set.Spec.ReserveOrdinals = append(set.Spec.ReserveOrdinals, intstr.FromInt32(int32(ord)))
+			}
+		}
+	}
+	// the condemned pods still occupy their ordinals until they are deleted
+	data[setKey] = formatCoordinatedOrdinals(assigned.Union(podOrdinals))
+
+	if len(data) == len(cm.Data) {
+		changed := false
+		for key, value := range data {
+			if cm.Data[key] != value {
+				changed = true
+				break
+			}
+		}
+		if !changed {
+			return nil
+		}
+	}
+
+	cm = cm.DeepCopy()
+	cm.Data = data
+	if notFound {
+		_, err = ssc.kubeClient.CoreV1().ConfigMaps(namespace).Create(ctx, cm, metav1.CreateOptions{})
+	} else {
+		_, err = ssc.kubeClient.CoreV1().ConfigMaps(namespace).Update(ctx, cm, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return err
+	}
+	klog.V(4).InfoS("Recorded occupied ordinals", "statefulSet", klog.KObj(set), "configMap", klog.KObj(cm), "ordinals", data[setKey])
+	return nil
+}
+
+// getCoordinationConfigMap returns the namespace and name of the coordination ConfigMap of set.
+func getCoordinationConfigMap(set *appsv1beta1.StatefulSet) (string, string) {
+	coordination := set.Spec.Ordinals.Coordination
+	if coordination.Namespace != "" {
+		return coordination.Namespace, coordination.ConfigMapName
+	}
+	return set.Namespace, coordination.ConfigMapName
+}
+
+// getCoordinationKey returns the key of set in the data of coordination ConfigMap.
+// The namespace never contains dots, so the key can be split at the first dot.
+func getCoordinationKey(set *appsv1beta1.StatefulSet) string {
+	return set.Namespace + "." + set.Name
+}
+
+// parseCoordinationKey returns the namespace and name of the StatefulSet recorded with key.
+// The key without namespace belongs to the StatefulSet in the same namespace as the ConfigMap.
+func parseCoordinationKey(key, cmNamespace string) (string, string) {
+	if namespace, name, found := strings.Cut(key, "."); found {
+		return namespace, name
+	}
+	return cmNamespace, key
+}
+
+// coordinationConfigMapEventHandler enqueues the StatefulSets recorded in the coordination ConfigMap,
+// so that they reserve the ordinals newly occupied by the others.
+type coordinationConfigMapEventHandler struct {
+	setLister kruiseappslisters.StatefulSetLister
+}
+
+var _ handler.TypedEventHandler[*v1.ConfigMap, reconcile.Request] = &coordinationConfigMapEventHandler{}
+
+func (e *coordinationConfigMapEventHandler) Create(ctx context.Context, evt event.TypedCreateEvent[*v1.ConfigMap], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	e.enqueueCoordinatedSets(evt.Object, q)
+}
+
+func (e *coordinationConfigMapEventHandler) Update(ctx context.Context, evt event.TypedUpdateEvent[*v1.ConfigMap], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	if reflect.DeepEqual(evt.ObjectOld.Data, evt.ObjectNew.Data) {
+		return
+	}
+	e.enqueueCoordinatedSets(evt.ObjectNew, q)
+}
+
+func (e *coordinationConfigMapEventHandler) Delete(ctx context.Context, evt event.TypedDeleteEvent[*v1.ConfigMap], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	e.enqueueCoordinatedSets(evt.Object, q)
+}
+
+func (e *coordinationConfigMapEventHandler) Generic(ctx context.Context, evt event.TypedGenericEvent[*v1.ConfigMap], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+}
+
+func (e *coordinationConfigMapEventHandler) enqueueCoordinatedSets(cm *v1.ConfigMap, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	for key := range cm.Data {
+		namespace, name := parseCoordinationKey(key, cm.Namespace)
+		set, err := e.setLister.StatefulSets(namespace).Get(name)
+		if err != nil || set.Spec.Ordinals == nil || set.Spec.Ordinals.Coordination == nil {
+			continue
+		}
+		// the key may be a coincidence in other ConfigMaps
+		if cmNamespace, cmName := getCoordinationConfigMap(set); cmNamespace != cm.Namespace || cmName != cm.Name {
+			continue
+		}
+		q.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}})
+	}
+}
+
+// assignCoordinatedOrdinals returns the ordinals of the replicas of set. It keeps the ordinals recorded for set or
+// occupied by its pods, and only assigns the lowest free ordinals when more replicas are needed. The ordinals recorded
+// by the other StatefulSets are never assigned, unless set already has pods on them.
+func assignCoordinatedOrdinals(set *appsv1beta1.StatefulSet, recorded, others, podOrdinals sets.Set[int]) sets.Set[int] {
+	startOrdinal := getStartOrdinal(set)
+	replicas := int(*set.Spec.Replicas)
+	reserveOrdinals := apiutil.GetReserveOrdinalIntSet(set.Spec.ReserveOrdinals)
+
+	var kept []int
+	for _, ord := range sets.List(recorded.Union(podOrdinals)) {
+		if ord < startOrdinal || reserveOrdinals.Has(ord) || (others.Has(ord) && !podOrdinals.Has(ord)) {
+			continue
+		}
+		kept = append(kept, ord)
+	}
+	if len(kept) > replicas {
+		kept = kept[:replicas]
+	}
+
+	assigned := sets.New(kept...)
+	for ord := startOrdinal; assigned.Len() < replicas; ord++ {
+		if !assigned.Has(ord) && !reserveOrdinals.Has(ord) && !others.Has(ord) {
+			assigned.Insert(ord)
+		}
+	}
+	return assigned
+}
+
+// parseCoordinatedOrdinals parses ordinals recorded in the coordination ConfigMap, such as "0-3,5".
+func parseCoordinatedOrdinals(value string) ([]intstr.IntOrString, error) {
+	var ordinals []intstr.IntOrString
+	for _, elem := range strings.Split(value, ",") {
+		if elem == "" {
+			continue
+		}
+		if strings.Contains(elem, "-") {
+			if _, _, err := apiutil.ParseRange(elem); err != nil {
+				return nil, err
+			}
+			ordinals = append(ordinals, intstr.FromString(elem))
+			continue
+		}
+		ord, err := strconv.Atoi(elem)
+		if err != nil || ord < 0 {
+			return nil, fmt.Errorf("invalid ordinal %s", elem)
+		}
+		ordinals = append(ordinals, intstr.FromInt32(int32(ord)))
+	}
+	return ordinals, nil
+}
+
+// formatCoordinatedOrdinals formats ordinals to be recorded in the coordination ConfigMap,
+// and the consecutive ordinals are merged into ranges.
+func formatCoordinatedOrdinals(ordinals sets.Set[int]) string {
+	sorted := sets.List(ordinals)
+	var elems []string
+	for i := 0; i < len(sorted); {
+		j := i
+		for j+1 < len(sorted) && sorted[j+1] == sorted[j]+1 {
+			j++
+		}
+		if i == j {
+			elems = append(elems, strconv.Itoa(sorted[i]))
+		} else {
+			elems = append(elems, fmt.Sprintf("%d-%d", sorted[i], sorted[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(elems, ",")
+}
@@ -0,0 +1,246 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statefulset
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes/fake"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	utilpointer "k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	kruiseappslisters "github.com/openkruise/kruise/pkg/client/listers/apps/v1beta1"
+	"github.com/openkruise/kruise/pkg/features"
+	utilfeature "github.com/openkruise/kruise/pkg/util/feature"
+)
+
+func TestFormatAndParseCoordinatedOrdinals(t *testing.T) {
+	value := formatCoordinatedOrdinals(sets.New(5, 0, 1, 2, 7, 8))
+	if value != "0-2,5,7-8" {
+		t.Fatalf("unexpected formatted ordinals %s", value)
+	}
+	ordinals, err := parseCoordinatedOrdinals(value)
+	if err != nil {
+		t.Fatalf("failed to parse ordinals: %v", err)
+	}
+	expected := []intstr.IntOrString{intstr.FromString("0-2"), intstr.FromInt32(5), intstr.FromString("7-8")}
+	if !reflect.DeepEqual(ordinals, expected) {
+		t.Fatalf("expected ordinals %v, got %v", expected, ordinals)
+	}
+	for _, invalid := range []string{"3-1", "a", "-1"} {
+		if _, err := parseCoordinatedOrdinals(invalid); err == nil {
+			t.Fatalf("expected error for %s", invalid)
+		}
+	}
+}
+
+// newCoordinationTestReconciler returns the reconciler whose ConfigMap lister is synced from kubeClient before coordinating.
+func newCoordinationTestReconciler(t *testing.T, kubeClient *fake.Clientset, sets ...*appsv1beta1.StatefulSet) (*ReconcileStatefulSet, func()) {
+	setIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, set := range sets {
+		_ = setIndexer.Add(set)
+	}
+	cmIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	syncConfigMaps := func() {
+		t.Helper()
+		cms, err := kubeClient.CoreV1().ConfigMaps(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			t.Fatalf("failed to list ConfigMaps: %v", err)
+		}
+		var objects []interface{}
+		for i := range cms.Items {
+			objects = append(objects, &cms.Items[i])
+		}
+		_ = cmIndexer.Replace(objects, "")
+	}
+	syncConfigMaps()
+	return &ReconcileStatefulSet{
+		kubeClient: kubeClient,
+		setLister:  kruiseappslisters.NewStatefulSetLister(setIndexer),
+		cmLister:   corelisters.NewConfigMapLister(cmIndexer),
+	}, syncConfigMaps
+}
+
+func TestCoordinateOrdinals(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.StatefulSetStartOrdinal, true)()
+
+	newSet := func(name string) *appsv1beta1.StatefulSet {
+		set := newStatefulSet(3)
+		set.Name = name
+		set.Spec.Ordinals = &appsv1beta1.StatefulSetOrdinals{
+			Coordination: &appsv1beta1.StatefulSetOrdinalsCoordination{ConfigMapName: "ordinals"},
+		}
+		return set
+	}
+	foo, bar := newSet("foo"), newSet("bar")
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: foo.Namespace, Name: "ordinals"},
+		// the StatefulSet deleted has been removed from the lister
+		Data: map[string]string{"default.bar": "0-1,4", "default.deleted": "2"},
+	}
+	kubeClient := fake.NewSimpleClientset(cm)
+	ssc, _ := newCoordinationTestReconciler(t, kubeClient, foo, bar)
+
+	// foo keeps the ordinals of its pods, and takes the lowest free ordinal for the third replica
+	pods := []*v1.Pod{newStatefulSetPod(foo, 2), newStatefulSetPod(foo, 6)}
+	if err := ssc.coordinateOrdinals(context.TODO(), foo, pods); err != nil {
+		t.Fatalf("failed to coordinate ordinals: %v", err)
+	}
+	expectedReserve := []intstr.IntOrString{intstr.FromInt32(0), intstr.FromInt32(1), intstr.FromInt32(4), intstr.FromInt32(5)}
+	if !reflect.DeepEqual(foo.Spec.ReserveOrdinals, expectedReserve) {
+		t.Fatalf("expected reserveOrdinals %v, got %v", expectedReserve, foo.Spec.ReserveOrdinals)
+	}
+	cm, _ = kubeClient.CoreV1().ConfigMaps(foo.Namespace).Get(context.TODO(), "ordinals", metav1.GetOptions{})
+	expectedData := map[string]string{"default.bar": "0-1,4", "default.foo": "2-3,6"}
+	if !reflect.DeepEqual(cm.Data, expectedData) {
+		t.Fatalf("expected data %v, got %v", expectedData, cm.Data)
+	}
+
+	// the ConfigMap is created if not exists
+	baz := newSet("baz")
+	baz.Spec.Ordinals.Coordination.ConfigMapName = "new-ordinals"
+	if err := ssc.coordinateOrdinals(context.TODO(), baz, nil); err != nil {
+		t.Fatalf("failed to coordinate ordinals: %v", err)
+	}
+	cm, err := kubeClient.CoreV1().ConfigMaps(baz.Namespace).Get(context.TODO(), "new-ordinals", metav1.GetOptions{})
+	if err != nil || cm.Data["default.baz"] != "0-2" {
+		t.Fatalf("expected ConfigMap created with ordinals 0-2, got %v, %v", cm, err)
+	}
+}
+
+func TestCoordinateOrdinalsWithExistingPods(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.StatefulSetStartOrdinal, true)()
+
+	newSet := func(name string, replicas int) *appsv1beta1.StatefulSet {
+		set := newStatefulSet(replicas)
+		set.Name = name
+		set.Spec.Ordinals = &appsv1beta1.StatefulSetOrdinals{
+			Coordination: &appsv1beta1.StatefulSetOrdinalsCoordination{ConfigMapName: "ordinals"},
+		}
+		return set
+	}
+	foo, bar := newSet("foo", 3), newSet("bar", 3)
+	kubeClient := fake.NewSimpleClientset()
+	ssc, syncConfigMaps := newCoordinationTestReconciler(t, kubeClient, foo, bar)
+
+	newPods := func(set *appsv1beta1.StatefulSet, ordinals ...int) []*v1.Pod {
+		var pods []*v1.Pod
+		for _, ord := range ordinals {
+			pods = append(pods, newStatefulSetPod(set, ord))
+		}
+		return pods
+	}
+	coordinate := func(set *appsv1beta1.StatefulSet, pods []*v1.Pod, expectedReserve []intstr.IntOrString, expectedData map[string]string) {
+		t.Helper()
+		set = set.DeepCopy()
+		if err := ssc.coordinateOrdinals(context.TODO(), set, pods); err != nil {
+			t.Fatalf("failed to coordinate ordinals of %s: %v", set.Name, err)
+		}
+		syncConfigMaps()
+		if !reflect.DeepEqual(set.Spec.ReserveOrdinals, expectedReserve) {
+			t.Fatalf("expected reserveOrdinals of %s %v, got %v", set.Name, expectedReserve, set.Spec.ReserveOrdinals)
+		}
+		cm, _ := kubeClient.CoreV1().ConfigMaps(set.Namespace).Get(context.TODO(), "ordinals", metav1.GetOptions{})
+		if !reflect.DeepEqual(cm.Data, expectedData) {
+			t.Fatalf("expected data %v, got %v", expectedData, cm.Data)
+		}
+	}
+
+	// both sets already have pods, and bar never reserves the ordinal 2 where it has a pod
+	fooPods, barPods := newPods(foo, 0, 1, 2), newPods(bar, 2, 3, 4)
+	coordinate(foo, fooPods, nil, map[string]string{"default.foo": "0-2"})
+	coordinate(bar, barPods, []intstr.IntOrString{intstr.FromInt32(0), intstr.FromInt32(1)}, map[string]string{"default.foo": "0-2", "default.bar": "2-4"})
+	coordinate(foo, fooPods, nil, map[string]string{"default.foo": "0-2", "default.bar": "2-4"})
+
+	// scale down foo, and its condemned pods still occupy their ordinals
+	foo.Spec.Replicas = utilpointer.Int32(1)
+	coordinate(foo, fooPods, nil, map[string]string{"default.foo": "0-2", "default.bar": "2-4"})
+	coordinate(bar, barPods, []intstr.IntOrString{intstr.FromInt32(0), intstr.FromInt32(1)}, map[string]string{"default.foo": "0-2", "default.bar": "2-4"})
+
+	// the ordinals are released after the condemned pods deleted, and bar keeps its ordinals
+	fooPods = newPods(foo, 0)
+	coordinate(foo, fooPods, nil, map[string]string{"default.foo": "0", "default.bar": "2-4"})
+	coordinate(bar, barPods, []intstr.IntOrString{intstr.FromInt32(0), intstr.FromInt32(1)}, map[string]string{"default.foo": "0", "default.bar": "2-4"})
+
+	// bar takes the released ordinal only when it scales up
+	bar.Spec.Replicas = utilpointer.Int32(4)
+	coordinate(bar, barPods, []intstr.IntOrString{intstr.FromInt32(0)}, map[string]string{"default.foo": "0", "default.bar": "1-4"})
+}
+
+func TestCoordinateOrdinalsAcrossNamespaces(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.StatefulSetStartOrdinal, true)()
+
+	newSet := func(namespace, name string) *appsv1beta1.StatefulSet {
+		set := newStatefulSet(2)
+		set.Namespace = namespace
+		set.Name = name
+		set.Spec.Ordinals = &appsv1beta1.StatefulSetOrdinals{
+			Coordination: &appsv1beta1.StatefulSetOrdinalsCoordination{ConfigMapName: "ordinals", Namespace: "shared"},
+		}
+		return set
+	}
+	foo, bar := newSet("ns-foo", "foo"), newSet("ns-bar", "bar")
+	kubeClient := fake.NewSimpleClientset()
+	ssc, syncConfigMaps := newCoordinationTestReconciler(t, kubeClient, foo, bar)
+
+	if err := ssc.coordinateOrdinals(context.TODO(), foo, nil); err != nil {
+		t.Fatalf("failed to coordinate ordinals: %v", err)
+	}
+	syncConfigMaps()
+	if err := ssc.coordinateOrdinals(context.TODO(), bar, nil); err != nil {
+		t.Fatalf("failed to coordinate ordinals: %v", err)
+	}
+	expectedReserve := []intstr.IntOrString{intstr.FromInt32(0), intstr.FromInt32(1)}
+	if !reflect.DeepEqual(bar.Spec.ReserveOrdinals, expectedReserve) {
+		t.Fatalf("expected reserveOrdinals %v, got %v", expectedReserve, bar.Spec.ReserveOrdinals)
+	}
+	cm, err := kubeClient.CoreV1().ConfigMaps("shared").Get(context.TODO(), "ordinals", metav1.GetOptions{})
+	expectedData := map[string]string{"ns-foo.foo": "0-1", "ns-bar.bar": "2-3"}
+	if err != nil || !reflect.DeepEqual(cm.Data, expectedData) {
+		t.Fatalf("expected data %v, got %v, %v", expectedData, cm, err)
+	}
+
+	// the sets recorded in the ConfigMap are enqueued when it changes
+	handler := &coordinationConfigMapEventHandler{setLister: ssc.setLister}
+	q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	oldCM := cm.DeepCopy()
+	oldCM.Data = map[string]string{"ns-foo.foo": "0-1"}
+	handler.Update(context.TODO(), event.TypedUpdateEvent[*v1.ConfigMap]{ObjectOld: oldCM, ObjectNew: cm}, q)
+	if q.Len() != 2 {
+		t.Fatalf("expected 2 sets enqueued, got %d", q.Len())
+	}
+
+	// the same keys in other ConfigMaps are ignored
+	q = workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	otherCM := cm.DeepCopy()
+	otherCM.Namespace = "ns-foo"
+	handler.Create(context.TODO(), event.TypedCreateEvent[*v1.ConfigMap]{Object: otherCM}, q)
+	if q.Len() != 0 {
+		t.Fatalf("expected no sets enqueued, got %d", q.Len())
+	}
+}
@@ -32,6 +32,7 @@ import (
 
 	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
 	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	apiutil "github.com/openkruise/kruise/pkg/util/api"
 	"github.com/openkruise/kruise/pkg/util/expectations"
 	imagejobutilfunc "github.com/openkruise/kruise/pkg/util/imagejob/utilfunction"
 	"github.com/openkruise/kruise/pkg/util/inplaceupdate"
@@ -66,6 +67,9 @@ func (dss *defaultStatefulSetControl) createImagePullJobsForInPlaceUpdate(sts *a
 		// partition, _ = intstrutil.GetValueFromIntOrPercent(sts.Spec.UpdateStrategy.Partition, int(*sts.Spec.Replicas), true)
 		partition = int(*sts.Spec.UpdateStrategy.RollingUpdate.Partition)
 	}
+	if len(sts.Spec.UpdateStrategy.RollingUpdate.PartitionOrdinals) > 0 {
+		partition = apiutil.GetReserveOrdinalIntSet(sts.Spec.UpdateStrategy.RollingUpdate.PartitionOrdinals).Len()
+	}
 	maxUnavailable, _ = intstrutil.GetValueFromIntOrPercent(
 		intstrutil.ValueOrDefault(sts.Spec.UpdateStrategy.RollingUpdate.MaxUnavailable, intstrutil.FromString("1")), int(*sts.Spec.Replicas), false)
 	if partition == 0 && maxUnavailable >= int(*sts.Spec.Replicas) {
@@ -71,6 +71,10 @@ type PVCHandler interface {
 
 func (spc *StatefulPodControl) IsOwnedPVCsReady(set *appsv1beta1.StatefulSet, pod *v1.Pod) (bool, error) {
 	checkFn := func(claim, template *v1.PersistentVolumeClaim) (bool, error) {
+		// the claim to migrate is still serving the pod until the pod is recreated
+		if isPVCMigrationEnabled(set) && !pvc.IsClaimCompatibleWithoutSize(claim, template) {
+			return true, nil
+		}
 		_, ready := pvc.IsPVCCompatibleAndReady(claim, template)
 		if !ready {
 			return false, nil
@@ -82,7 +86,7 @@ func (spc *StatefulPodControl) IsOwnedPVCsReady(set *appsv1beta1.StatefulSet, po
 
 func (spc *StatefulPodControl) IsClaimsCompatible(set *appsv1beta1.StatefulSet, pod *v1.Pod) (bool, error) {
 	fn := func(claim, template *v1.PersistentVolumeClaim) (bool, error) {
-		if matched, _ := pvc.CompareWithCheckFn(claim, template, pvc.IsPVCNeedExpand); !matched {
+		if matched, _ := pvc.CompareWithCheckFn(claim, template, pvc.IsPVCNeedPatch); !matched {
 			return false, nil
 		}
 		return true, nil
@@ -93,16 +97,20 @@ func (spc *StatefulPodControl) IsClaimsCompatible(set *appsv1beta1.StatefulSet,
 // TODO: if rollback, pass when kep1790 enabled
 func (spc *StatefulPodControl) TryPatchPVC(set *appsv1beta1.StatefulSet, pod *v1.Pod) error {
 	fn := func(claim, template *v1.PersistentVolumeClaim) (bool, error) {
-		matched, needExpand := pvc.CompareWithCheckFn(claim, template, pvc.IsPVCNeedExpand)
+		matched, needPatch := pvc.CompareWithCheckFn(claim, template, pvc.IsPVCNeedPatch)
 		if matched {
 			return true, nil
 		}
-		if !needExpand {
+		if !needPatch {
+			// the claim will be migrated when the pod is recreated
+			if isPVCMigrationEnabled(set) {
+				return true, nil
+			}
 			spc.recorder.Eventf(set, v1.EventTypeWarning, "FailedUpdatePVC", "failed to update pvc %s: contains diff other than spec resource, wait pvc to be deleted", claim.Name)
 			return false, fmt.Errorf("can not patch pvc %s: contains diff other than spec resource, wait pvc to be deleted", claim.Name)
 		}
-		// only pvc expand => check storage class allow expansion
-		if claim.Spec.StorageClassName != nil {
+		// pvc expand => check storage class allow expansion
+		if pvc.IsPVCNeedExpand(claim, template) && claim.Spec.StorageClassName != nil {
 			scName := *claim.Spec.StorageClassName
 			sc, err := spc.objectMgr.GetStorageClass(scName)
 			if err != nil {
@@ -116,11 +124,18 @@ func (spc *StatefulPodControl) TryPatchPVC(set *appsv1beta1.StatefulSet, pod *v1
 			}
 		}
 
+		verb := "Update"
+		if pvc.IsPVCNeedExpand(claim, template) {
+			verb = "Resize"
+		}
 		claimClone := claim.DeepCopy()
 		needsUpdate := resizeClaim(set, claimClone, template)
+		if patchClaimMetadataAndAttributes(claimClone, template) {
+			needsUpdate = true
+		}
 		if needsUpdate {
 			err := spc.objectMgr.UpdateClaim(claimClone)
-			spc.recordClaimEvent("Resize", set, pod, claimClone, err)
+			spc.recordClaimEvent(verb, set, pod, claimClone, err)
 			if err != nil {
 				return false, fmt.Errorf("could not update claim %s: %w", claim.Name, err)
 			}
@@ -152,6 +167,7 @@ func (ssc *defaultStatefulSetControl) updatePVCStatus(status *appsv1beta1.Statef
 		templateNameMap[templates[i].Name] = &status.VolumeClaims[i]
 	}
 
+	migrationEnabled := isPVCMigrationEnabled(set)
 	fn := func(claim, template *v1.PersistentVolumeClaim) (bool, error) {
 		if compatible, ready := pvc.IsPVCCompatibleAndReady(claim, template); compatible {
 			templateStatus := templateNameMap[template.Name]
@@ -159,6 +175,11 @@ func (ssc *defaultStatefulSetControl) updatePVCStatus(status *appsv1beta1.Statef
 			if ready {
 				templateStatus.CompatibleReadyReplicas++
 			}
+		} else if migrationEnabled && !pvc.IsClaimCompatibleWithoutSize(claim, template) {
+			// the new claim is created once the migration starts
+			if _, err := ssc.podControl.objectMgr.GetClaim(claim.Namespace, getPVCMigrationName(claim.Name)); err == nil {
+				templateNameMap[template.Name].MigratingReplicas++
+			}
 		}
 		return true, nil
 	}
@@ -220,3 +241,28 @@ func resizeClaim(set *appsv1beta1.StatefulSet, claim, template *v1.PersistentVol
 	}
 	return false
 }
+
+// patchClaimMetadataAndAttributes sets the labels, annotations and VolumeAttributesClass of template onto the claim,
+// which can be updated in place without recreating pod.
+func patchClaimMetadataAndAttributes(claim, template *v1.PersistentVolumeClaim) (needUpdate bool) {
+	if pvc.IsPVCNeedPatchMetadata(claim, template) {
+		if claim.Labels == nil && len(template.Labels) > 0 {
+			claim.Labels = map[string]string{}
+		}
+		for key, value := range template.Labels {
+			claim.Labels[key] = value
+		}
+		if claim.Annotations == nil && len(template.Annotations) > 0 {
+			claim.Annotations = map[string]string{}
+		}
+		for key, value := range template.Annotations {
+			claim.Annotations[key] = value
+		}
+		needUpdate = true
+	}
+	if pvc.IsPVCNeedModifyVolumeAttributesClass(claim, template) {
+		claim.Spec.VolumeAttributesClassName = template.Spec.VolumeAttributesClassName
+		needUpdate = true
+	}
+	return needUpdate
+}
@@ -23,6 +23,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
 
 	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
 	kruisefake "github.com/openkruise/kruise/pkg/client/clientset/versioned/fake"
@@ -410,6 +411,53 @@ func TestTryPatchPVC(t *testing.T) {
 	}
 }
 
+func TestTryPatchPVCMetadataAndAttributes(t *testing.T) {
+	sc := newStorageClass("cannot_expand", false)
+	set := newStatefulSetWithGivenSC(5, 1, []*string{&sc.Name})
+	set.Spec.VolumeClaimUpdateStrategy.Type = appsv1beta1.OnPodRollingUpdateVolumeClaimUpdateStrategyType
+	template := &set.Spec.VolumeClaimTemplates[0]
+	template.Spec.Resources.Requests[v1.ResourceStorage] = resource.MustParse("1Gi")
+	template.Labels = map[string]string{"tier": "gold"}
+	template.Annotations = map[string]string{"iops": "3000"}
+	template.Spec.VolumeAttributesClassName = ptr.To("gold")
+
+	claim := newTestPVCWithSC("datadir-0-foo-0", &sc.Name, true, true, nil)
+	claim.Labels = map[string]string{"tier": "silver", "other": "kept"}
+	client := fake.NewSimpleClientset(&sc, &claim)
+	kruiseClient := kruisefake.NewSimpleClientset(set)
+	om, _, _, stop := setupController(client, kruiseClient)
+	defer close(stop)
+	spc := NewStatefulPodControlFromManager(om, &noopRecorder{})
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "foo-0"}}
+
+	compatible, err := spc.IsClaimsCompatible(set, pod)
+	assert.Nil(t, err)
+	assert.False(t, compatible)
+
+	// the storage class does not allow expansion, but the claim can be patched without resizing
+	assert.Nil(t, spc.TryPatchPVC(set, pod))
+	patched, err := om.GetClaim(set.Namespace, claim.Name)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"tier": "gold", "other": "kept"}, patched.Labels)
+	assert.Equal(t, "3000", patched.Annotations["iops"])
+	assert.Equal(t, "gold", *patched.Spec.VolumeAttributesClassName)
+
+	compatible, err = spc.IsClaimsCompatible(set, pod)
+	assert.Nil(t, err)
+	assert.True(t, compatible)
+
+	// wait for the volume to be modified
+	completed, err := spc.IsOwnedPVCsCompleted(set, pod)
+	assert.Nil(t, err)
+	assert.False(t, completed)
+	patched = patched.DeepCopy()
+	patched.Status.CurrentVolumeAttributesClassName = ptr.To("gold")
+	assert.Nil(t, om.UpdateClaim(patched))
+	completed, err = spc.IsOwnedPVCsCompleted(set, pod)
+	assert.Nil(t, err)
+	assert.True(t, completed)
+}
+
 func TestIsOwnedPVCsCompleted(t *testing.T) {
 	simpleSetFn := func(scs []*string) *appsv1beta1.StatefulSet {
 		statefulSet := newStatefulSetWithGivenSC(5, len(scs), scs)
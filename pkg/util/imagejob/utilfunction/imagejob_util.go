@@ -30,13 +30,19 @@ import (
 	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
 )
 
-func CreateJobForWorkload(c client.Client, owner metav1.Object, gvk schema.GroupVersionKind, name, image string, labels map[string]string, annotations map[string]string, podSelector metav1.LabelSelector, pullSecrets []string) error {
+// GetPullTimeoutSeconds returns the timeout of pulling image on each node for the ImagePullJobs of the workload.
+func GetPullTimeoutSeconds(owner metav1.Object) int32 {
 	var pullTimeoutSeconds int32 = 300
 	if str, ok := owner.GetAnnotations()[appsv1beta1.ImagePreDownloadTimeoutSecondsKey]; ok {
 		if i, err := strconv.ParseInt(str, 10, 32); err == nil {
 			pullTimeoutSeconds = int32(i)
 		}
 	}
+	return pullTimeoutSeconds
+}
+
+func CreateJobForWorkload(c client.Client, owner metav1.Object, gvk schema.GroupVersionKind, name, image string, labels map[string]string, annotations map[string]string, podSelector metav1.LabelSelector, pullSecrets []string) error {
+	pullTimeoutSeconds := GetPullTimeoutSeconds(owner)
 
 	parallelism := intstr.FromInt(1)
 	if str, ok := owner.GetAnnotations()[appsv1beta1.ImagePreDownloadParallelismKey]; ok {
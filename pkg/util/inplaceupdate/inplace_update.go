@@ -64,8 +64,9 @@ type UpdateResult struct {
 type UpdateOptions struct {
 	IgnoreVolumeClaimTemplatesHashDiff bool
 
-	GracePeriodSeconds int32
-	AdditionalFuncs    []func(*v1.Pod)
+	GracePeriodSeconds   int32
+	ContainerUpdateOrder []appspub.InPlaceUpdateContainerOrder
+	AdditionalFuncs      []func(*v1.Pod)
 
 	CalculateSpec                  func(oldRevision, newRevision *apps.ControllerRevision, opts *UpdateOptions) *UpdateSpec
 	PatchSpecToPod                 func(pod *v1.Pod, spec *UpdateSpec, state *appspub.InPlaceUpdateState) (*v1.Pod, map[string]*v1.ResourceRequirements, error)
@@ -93,6 +94,8 @@ type UpdateSpec struct {
 	UpdateEnvFromMetadata bool                               `json:"updateEnvFromMetadata,omitempty"`
 	GraceSeconds          int32                              `json:"graceSeconds,omitempty"`
 
+	ContainerUpdateOrder []appspub.InPlaceUpdateContainerOrder `json:"containerUpdateOrder,omitempty"`
+
 	OldTemplate *v1.PodTemplateSpec `json:"oldTemplate,omitempty"`
 	NewTemplate *v1.PodTemplateSpec `json:"newTemplate,omitempty"`
 }
@@ -151,6 +154,12 @@ func (c *realControl) Refresh(pod *v1.Pod, opts *UpdateOptions) RefreshResult {
 				return RefreshResult{}
 			}
 
+			// wait for the interval since previous batch
+			if delayDuration := getNextBatchDelay(&state); delayDuration > 0 {
+				klog.V(5).InfoS("Pod in-place update waiting for interval before next batch", "namespace", pod.Namespace, "name", pod.Name, "delay", delayDuration)
+				return RefreshResult{DelayDuration: delayDuration}
+			}
+
 			// do update the next containers
 			if updated, err := c.updateNextBatch(pod, opts); err != nil {
 				return RefreshResult{RefreshErr: err}
@@ -279,6 +288,7 @@ func (c *realControl) updateNextBatch(pod *v1.Pod, opts *UpdateOptions) (bool, e
 			ContainerRefMetadata:  state.NextContainerRefMetadata,
 			UpdateEnvFromMetadata: state.UpdateEnvFromMetadata,
 			ContainerResources:    state.NextContainerResources,
+			ContainerUpdateOrder:  opts.ContainerUpdateOrder,
 		}
 		var expectedResources map[string]*v1.ResourceRequirements
 		clone, expectedResources, err = opts.PatchSpecToPod(clone, &spec, &state)
@@ -318,6 +328,7 @@ func (c *realControl) Update(pod *v1.Pod, oldRevision, newRevision *apps.Control
 	if spec == nil {
 		return UpdateResult{}
 	}
+	spec.ContainerUpdateOrder = opts.ContainerUpdateOrder
 
 	// TODO(FillZpp): maybe we should check if the previous in-place update has completed
 
@@ -480,6 +491,19 @@ func doPreCheckBeforeNext(pod *v1.Pod, preCheck *appspub.InPlaceUpdatePreCheckBe
 	return nil
 }
 
+// getNextBatchDelay returns the duration to wait for the interval of previous batch.
+func getNextBatchDelay(state *appspub.InPlaceUpdateState) time.Duration {
+	if state.PreCheckBeforeNext == nil || state.PreCheckBeforeNext.IntervalSeconds <= 0 || len(state.ContainerBatchesRecord) == 0 {
+		return 0
+	}
+	lastBatch := state.ContainerBatchesRecord[len(state.ContainerBatchesRecord)-1]
+	interval := time.Duration(state.PreCheckBeforeNext.IntervalSeconds) * time.Second
+	if span := Clock.Since(lastBatch.Timestamp.Time); span < interval {
+		return roundupSeconds(interval - span)
+	}
+	return 0
+}
+
 func hasEqualCondition(pod *v1.Pod, newCondition *v1.PodCondition) bool {
 	oldCondition := util.GetCondition(pod, newCondition.Type)
 	isEqual := oldCondition != nil && oldCondition.Status == newCondition.Status &&
@@ -98,30 +98,13 @@ func defaultPatchUpdateSpecToPod(pod *v1.Pod, spec *UpdateSpec, state *appspub.I
 		pod.Annotations = make(map[string]string)
 	}
 
-	// prepare containers that should update this time and next time, according to their priorities
-	containersToUpdate := sets.NewString()
-	var highestPriority *int
-	var containersWithHighestPriority []string
-	for i := range pod.Spec.Containers {
-		c := &pod.Spec.Containers[i]
-		_, existImage := spec.ContainerImages[c.Name]
-		_, existMetadata := spec.ContainerRefMetadata[c.Name]
-		_, existResource := spec.ContainerResources[c.Name]
-		if !existImage && !existMetadata && !existResource {
-			continue
-		}
-		priority := utilcontainerlaunchpriority.GetContainerPriority(c)
-		if priority == nil {
-			containersToUpdate.Insert(c.Name)
-		} else if highestPriority == nil || *highestPriority < *priority {
-			highestPriority = priority
-			containersWithHighestPriority = []string{c.Name}
-		} else if *highestPriority == *priority {
-			containersWithHighestPriority = append(containersWithHighestPriority, c.Name)
-		}
-	}
-	for _, cName := range containersWithHighestPriority {
-		containersToUpdate.Insert(cName)
+	// prepare containers that should update this time and next time, according to the update order or their priorities
+	var containersToUpdate sets.String
+	var intervalSeconds int32
+	if len(spec.ContainerUpdateOrder) > 0 {
+		containersToUpdate, intervalSeconds = getContainersToUpdateByOrder(pod, spec)
+	} else {
+		containersToUpdate = getContainersToUpdateByPriority(pod, spec)
 	}
 	addMetadataSharedContainersToUpdate(pod, containersToUpdate, spec.ContainerRefMetadata)
 
@@ -198,7 +181,7 @@ func defaultPatchUpdateSpecToPod(pod *v1.Pod, spec *UpdateSpec, state *appspub.I
 	// start to update when these containers have updated ready
 	// TODO: currently we only support ContainersRequiredReady, not sure if we have to add ContainersPreferredReady in future
 	if len(state.NextContainerImages) > 0 || len(state.NextContainerRefMetadata) > 0 || len(state.NextContainerResources) > 0 {
-		state.PreCheckBeforeNext = &appspub.InPlaceUpdatePreCheckBeforeNext{ContainersRequiredReady: containersToUpdate.List()}
+		state.PreCheckBeforeNext = &appspub.InPlaceUpdatePreCheckBeforeNext{ContainersRequiredReady: containersToUpdate.List(), IntervalSeconds: intervalSeconds}
 	} else {
 		state.PreCheckBeforeNext = nil
 	}
@@ -218,6 +201,63 @@ func defaultPatchUpdateSpecToPod(pod *v1.Pod, spec *UpdateSpec, state *appspub.I
 	return pod, nil, nil
 }
 
+func isContainerInUpdateSpec(spec *UpdateSpec, name string) bool {
+	_, existImage := spec.ContainerImages[name]
+	_, existMetadata := spec.ContainerRefMetadata[name]
+	_, existResource := spec.ContainerResources[name]
+	return existImage || existMetadata || existResource
+}
+
+// getContainersToUpdateByPriority returns the containers without priority and the containers with the highest priority.
+func getContainersToUpdateByPriority(pod *v1.Pod, spec *UpdateSpec) sets.String {
+	containersToUpdate := sets.NewString()
+	var highestPriority *int
+	var containersWithHighestPriority []string
+	for i := range pod.Spec.Containers {
+		c := &pod.Spec.Containers[i]
+		if !isContainerInUpdateSpec(spec, c.Name) {
+			continue
+		}
+		priority := utilcontainerlaunchpriority.GetContainerPriority(c)
+		if priority == nil {
+			containersToUpdate.Insert(c.Name)
+		} else if highestPriority == nil || *highestPriority < *priority {
+			highestPriority = priority
+			containersWithHighestPriority = []string{c.Name}
+		} else if *highestPriority == *priority {
+			containersWithHighestPriority = append(containersWithHighestPriority, c.Name)
+		}
+	}
+	for _, cName := range containersWithHighestPriority {
+		containersToUpdate.Insert(cName)
+	}
+	return containersToUpdate
+}
+
+// getContainersToUpdateByOrder returns the containers in the first batch of ContainerUpdateOrder that have to update,
+// and the interval seconds of this batch. If no listed container has to update, it returns all the remaining containers.
+func getContainersToUpdateByOrder(pod *v1.Pod, spec *UpdateSpec) (sets.String, int32) {
+	for _, batch := range spec.ContainerUpdateOrder {
+		containersToUpdate := sets.NewString()
+		for _, cName := range batch.Containers {
+			if isContainerInUpdateSpec(spec, cName) {
+				containersToUpdate.Insert(cName)
+			}
+		}
+		if containersToUpdate.Len() > 0 {
+			return containersToUpdate, batch.IntervalSeconds
+		}
+	}
+
+	containersToUpdate := sets.NewString()
+	for i := range pod.Spec.Containers {
+		if name := pod.Spec.Containers[i].Name; isContainerInUpdateSpec(spec, name) {
+			containersToUpdate.Insert(name)
+		}
+	}
+	return containersToUpdate, 0
+}
+
 func addMetadataSharedContainersToUpdate(pod *v1.Pod, containersToUpdate sets.String, containerRefMetadata map[string]metav1.ObjectMeta) {
 	labelsToUpdate := sets.NewString()
 	annotationsToUpdate := sets.NewString()
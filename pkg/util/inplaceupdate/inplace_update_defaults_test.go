@@ -439,6 +439,88 @@ func TestDefaultPatchUpdateSpecToPod(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "update container images with update order, batch 1st",
+			spec: &UpdateSpec{
+				ContainerImages: map[string]string{"c1": "c1-img-new", "c2": "c2-img-new", "c3": "c3-img-new"},
+				ContainerUpdateOrder: []appspub.InPlaceUpdateContainerOrder{
+					{Containers: []string{"c3"}, IntervalSeconds: 30},
+					{Containers: []string{"c1"}},
+				},
+			},
+			state: &appspub.InPlaceUpdateState{},
+			expectedState: &appspub.InPlaceUpdateState{
+				LastContainerStatuses:  map[string]appspub.InPlaceUpdateContainerStatus{"c3": {ImageID: "containerd://c3-img"}},
+				NextContainerImages:    map[string]string{"c1": "c1-img-new", "c2": "c2-img-new"},
+				PreCheckBeforeNext:     &appspub.InPlaceUpdatePreCheckBeforeNext{ContainersRequiredReady: []string{"c3"}, IntervalSeconds: 30},
+				ContainerBatchesRecord: []appspub.InPlaceUpdateContainerBatch{{Timestamp: metav1.NewTime(now), Containers: []string{"c3"}}},
+			},
+			expectedPatch: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []map[string]interface{}{
+						{
+							"name":  "c3",
+							"image": "c3-img-new",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "update container images with update order, batch 2nd",
+			spec: &UpdateSpec{
+				ContainerImages: map[string]string{"c1": "c1-img-new", "c2": "c2-img-new"},
+				ContainerUpdateOrder: []appspub.InPlaceUpdateContainerOrder{
+					{Containers: []string{"c3"}, IntervalSeconds: 30},
+					{Containers: []string{"c1"}},
+				},
+			},
+			state: &appspub.InPlaceUpdateState{
+				LastContainerStatuses:  map[string]appspub.InPlaceUpdateContainerStatus{"c3": {ImageID: "containerd://c3-img"}},
+				ContainerBatchesRecord: []appspub.InPlaceUpdateContainerBatch{{Timestamp: metav1.NewTime(now), Containers: []string{"c3"}}},
+			},
+			expectedState: &appspub.InPlaceUpdateState{
+				LastContainerStatuses:  map[string]appspub.InPlaceUpdateContainerStatus{"c1": {ImageID: "containerd://c1-img"}, "c3": {ImageID: "containerd://c3-img"}},
+				NextContainerImages:    map[string]string{"c2": "c2-img-new"},
+				PreCheckBeforeNext:     &appspub.InPlaceUpdatePreCheckBeforeNext{ContainersRequiredReady: []string{"c1"}},
+				ContainerBatchesRecord: []appspub.InPlaceUpdateContainerBatch{{Timestamp: metav1.NewTime(now), Containers: []string{"c3"}}, {Timestamp: metav1.NewTime(now), Containers: []string{"c1"}}},
+			},
+			expectedPatch: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []map[string]interface{}{
+						{
+							"name":  "c1",
+							"image": "c1-img-new",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "update container images with update order, batch 3rd for the unlisted containers",
+			spec: &UpdateSpec{
+				ContainerImages: map[string]string{"c2": "c2-img-new"},
+				ContainerUpdateOrder: []appspub.InPlaceUpdateContainerOrder{
+					{Containers: []string{"c3"}, IntervalSeconds: 30},
+					{Containers: []string{"c1"}},
+				},
+			},
+			state: &appspub.InPlaceUpdateState{},
+			expectedState: &appspub.InPlaceUpdateState{
+				LastContainerStatuses:  map[string]appspub.InPlaceUpdateContainerStatus{"c2": {ImageID: "containerd://c2-img"}},
+				ContainerBatchesRecord: []appspub.InPlaceUpdateContainerBatch{{Timestamp: metav1.NewTime(now), Containers: []string{"c2"}}},
+			},
+			expectedPatch: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []map[string]interface{}{
+						{
+							"name":  "c2",
+							"image": "c2-img-new",
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -258,6 +258,7 @@ func TestCheckInPlaceUpdateCompleted(t *testing.T) {
 func TestRefresh(t *testing.T) {
 	aHourAgo := metav1.NewTime(time.Unix(time.Now().Add(-time.Hour).Unix(), 0))
 	tenSecondsAgo := metav1.NewTime(time.Now().Add(-time.Second * 10))
+	tenSecondsBeforeAHourAgo := metav1.NewTime(aHourAgo.Add(-time.Second * 10))
 
 	cases := []struct {
 		name        string
@@ -619,6 +620,63 @@ func TestRefresh(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "do not in-place update the next batch if interval of previous batch not passed",
+			pod: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						apps.StatefulSetRevisionLabel: "new-revision",
+					},
+					Annotations: map[string]string{
+						appspub.InPlaceUpdateStateKey: util.DumpJSON(appspub.InPlaceUpdateState{
+							Revision:               "new-revision",
+							UpdateTimestamp:        tenSecondsBeforeAHourAgo,
+							LastContainerStatuses:  map[string]appspub.InPlaceUpdateContainerStatus{"c1": {ImageID: "c1-img1-ID"}},
+							ContainerBatchesRecord: []appspub.InPlaceUpdateContainerBatch{{Timestamp: tenSecondsBeforeAHourAgo, Containers: []string{"c1"}}},
+							NextContainerImages:    map[string]string{"c2": "c2-img2"},
+							PreCheckBeforeNext:     &appspub.InPlaceUpdatePreCheckBeforeNext{ContainersRequiredReady: []string{"c1"}, IntervalSeconds: 60},
+						}),
+					},
+				},
+				Spec: v1.PodSpec{
+					Containers:     []v1.Container{{Name: "c1", Image: "c1-img2"}, {Name: "c2", Image: "c2-img1"}},
+					ReadinessGates: []v1.PodReadinessGate{{ConditionType: appspub.InPlaceUpdateReady}},
+				},
+				Status: v1.PodStatus{
+					ContainerStatuses: []v1.ContainerStatus{
+						{Name: "c1", ImageID: "c1-img2-ID", Ready: true},
+						{Name: "c2", ImageID: "c2-img1-ID"},
+					},
+				},
+			},
+			expectedPod: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						apps.StatefulSetRevisionLabel: "new-revision",
+					},
+					Annotations: map[string]string{
+						appspub.InPlaceUpdateStateKey: util.DumpJSON(appspub.InPlaceUpdateState{
+							Revision:               "new-revision",
+							UpdateTimestamp:        tenSecondsBeforeAHourAgo,
+							LastContainerStatuses:  map[string]appspub.InPlaceUpdateContainerStatus{"c1": {ImageID: "c1-img1-ID"}},
+							ContainerBatchesRecord: []appspub.InPlaceUpdateContainerBatch{{Timestamp: tenSecondsBeforeAHourAgo, Containers: []string{"c1"}}},
+							NextContainerImages:    map[string]string{"c2": "c2-img2"},
+							PreCheckBeforeNext:     &appspub.InPlaceUpdatePreCheckBeforeNext{ContainersRequiredReady: []string{"c1"}, IntervalSeconds: 60},
+						}),
+					},
+				},
+				Spec: v1.PodSpec{
+					Containers:     []v1.Container{{Name: "c1", Image: "c1-img2"}, {Name: "c2", Image: "c2-img1"}},
+					ReadinessGates: []v1.PodReadinessGate{{ConditionType: appspub.InPlaceUpdateReady}},
+				},
+				Status: v1.PodStatus{
+					ContainerStatuses: []v1.ContainerStatus{
+						{Name: "c1", ImageID: "c1-img2-ID", Ready: true},
+						{Name: "c2", ImageID: "c2-img1-ID"},
+					},
+				},
+			},
+		},
 	}
 
 	Clock = testingclock.NewFakeClock(aHourAgo.Time)
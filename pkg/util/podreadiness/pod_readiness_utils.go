@@ -28,6 +28,17 @@ import (
 	"github.com/openkruise/kruise/pkg/util/podadapter"
 )
 
+// NewNotReadyCondition returns a not-ready KruisePodReady condition with the key, for the pod that has no condition yet.
+func NewNotReadyCondition(msg Message) v1.PodCondition {
+	_, messages := addMessage("", msg)
+	return v1.PodCondition{
+		Type:               appspub.KruisePodReadyConditionType,
+		Status:             v1.ConditionFalse,
+		Message:            messages.dump(),
+		LastTransitionTime: metav1.Now(),
+	}
+}
+
 func addNotReadyKey(adp podadapter.Adapter, pod *v1.Pod, msg Message, condType v1.PodConditionType) error {
 	if alreadyHasKey(pod, msg, condType) {
 		return nil
@@ -135,6 +146,10 @@ func ContainsReadinessGate(pod *v1.Pod) bool {
 	return containsReadinessGate(pod, appspub.KruisePodReadyConditionType)
 }
 
+func HasNotReadyKey(pod *v1.Pod, msg Message) bool {
+	return alreadyHasKey(pod, msg, appspub.KruisePodReadyConditionType)
+}
+
 func getReadinessCondition(pod *v1.Pod, condType v1.PodConditionType) *v1.PodCondition {
 	if pod == nil {
 		return nil
@@ -234,10 +234,12 @@ func IsRunningAndReady(pod *v1.Pod) bool {
 	return pod.Status.Phase == v1.PodRunning && podutil.IsPodReady(pod) && pod.DeletionTimestamp.IsZero()
 }
 
+// GetPodContainerImageIDs returns the imageIDs of the containers and the initContainers of pod.
 func GetPodContainerImageIDs(pod *v1.Pod) map[string]string {
-	cImageIDs := make(map[string]string, len(pod.Status.ContainerStatuses))
-	for i := range pod.Status.ContainerStatuses {
-		c := &pod.Status.ContainerStatuses[i]
+	cImageIDs := make(map[string]string, len(pod.Status.InitContainerStatuses)+len(pod.Status.ContainerStatuses))
+	containerStatuses := append(append([]v1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for i := range containerStatuses {
+		c := &containerStatuses[i]
 		//ImageID format: docker-pullable://busybox@sha256:a9286defaba7b3a519d585ba0e37d0b2cbee74ebfe590960b0b1d6a5e97d1e1d
 		imageID := c.ImageID
 		if strings.Contains(imageID, "://") {
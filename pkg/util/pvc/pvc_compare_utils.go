@@ -40,7 +40,7 @@ func IsPatchPVCCompleted(claim, template *v1.PersistentVolumeClaim) bool {
 			}
 		}
 		// if pending, patch PVC completed
-		return pending
+		return pending && IsVolumeAttributesClassModified(claim, template)
 	}
 	return false
 }
@@ -82,7 +82,7 @@ func IsPVCCompatibleAndReady(claim, template *v1.PersistentVolumeClaim) (compati
 			return true
 		}
 		return false
-	}(claim, template)
+	}(claim, template) && !IsPVCNeedPatchMetadata(claim, template) && !IsPVCNeedModifyVolumeAttributesClass(claim, template)
 
 	ready = func(claim, template *v1.PersistentVolumeClaim) bool {
 		// cap >= spec => ready
@@ -93,7 +93,7 @@ func IsPVCCompatibleAndReady(claim, template *v1.PersistentVolumeClaim) (compati
 			return true
 		}
 		return false
-	}(claim, template)
+	}(claim, template) && IsVolumeAttributesClassModified(claim, template)
 	return
 }
 
@@ -118,3 +118,44 @@ func IsPVCNeedExpand(claim, template *v1.PersistentVolumeClaim) bool {
 	}
 	return false
 }
+
+// IsPVCNeedPatchMetadata checks if the labels or annotations of the template are not all set on the claim.
+// The labels and annotations added to the claim by others are ignored.
+func IsPVCNeedPatchMetadata(claim, template *v1.PersistentVolumeClaim) bool {
+	for key, value := range template.Labels {
+		if v, ok := claim.Labels[key]; !ok || v != value {
+			return true
+		}
+	}
+	for key, value := range template.Annotations {
+		if v, ok := claim.Annotations[key]; !ok || v != value {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPVCNeedModifyVolumeAttributesClass checks if the template specifies a VolumeAttributesClass
+// different from the claim, which can be modified without recreating the claim.
+func IsPVCNeedModifyVolumeAttributesClass(claim, template *v1.PersistentVolumeClaim) bool {
+	if template.Spec.VolumeAttributesClassName == nil {
+		return false
+	}
+	return claim.Spec.VolumeAttributesClassName == nil || *claim.Spec.VolumeAttributesClassName != *template.Spec.VolumeAttributesClassName
+}
+
+// IsVolumeAttributesClassModified checks if the VolumeAttributesClass of the template has been applied to the volume.
+func IsVolumeAttributesClassModified(claim, template *v1.PersistentVolumeClaim) bool {
+	if template.Spec.VolumeAttributesClassName == nil {
+		return true
+	}
+	return claim.Status.ModifyVolumeStatus == nil && claim.Status.CurrentVolumeAttributesClassName != nil &&
+		*claim.Status.CurrentVolumeAttributesClassName == *template.Spec.VolumeAttributesClassName
+}
+
+// IsPVCNeedPatch checks if the claim has to be patched in place to match the template,
+// including expanding storage, patching metadata and modifying VolumeAttributesClass.
+func IsPVCNeedPatch(claim, template *v1.PersistentVolumeClaim) bool {
+	return IsPVCNeedExpand(claim, template) || IsPVCNeedPatchMetadata(claim, template) ||
+		IsPVCNeedModifyVolumeAttributesClass(claim, template)
+}
@@ -5,6 +5,7 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestCompareWithCheckFn(t *testing.T) {
@@ -526,3 +527,71 @@ func TestIsPatchPVCCompleted(t *testing.T) {
 		})
 	}
 }
+
+func TestIsPVCNeedPatch(t *testing.T) {
+	newClaim := func(labels, annotations map[string]string, vac, currentVAC *string) *v1.PersistentVolumeClaim {
+		return &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Labels: labels, Annotations: annotations},
+			Spec: v1.PersistentVolumeClaimSpec{
+				VolumeAttributesClassName: vac,
+				Resources: v1.VolumeResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceStorage: resource.MustParse("1Gi")},
+				},
+			},
+			Status: v1.PersistentVolumeClaimStatus{
+				Capacity:                         v1.ResourceList{v1.ResourceStorage: resource.MustParse("1Gi")},
+				CurrentVolumeAttributesClassName: currentVAC,
+			},
+		}
+	}
+	template := newClaim(map[string]string{"tier": "gold"}, map[string]string{"iops": "3000"}, pointerToString("gold"), nil)
+
+	tests := []struct {
+		name           string
+		claim          *v1.PersistentVolumeClaim
+		expectedPatch  bool
+		expectedCompat bool
+		expectedReady  bool
+	}{
+		{
+			name:          "labels differ",
+			claim:         newClaim(map[string]string{"tier": "silver"}, map[string]string{"iops": "3000"}, pointerToString("gold"), pointerToString("gold")),
+			expectedPatch: true,
+			expectedReady: true,
+		},
+		{
+			name:          "annotations missing",
+			claim:         newClaim(map[string]string{"tier": "gold"}, nil, pointerToString("gold"), pointerToString("gold")),
+			expectedPatch: true,
+			expectedReady: true,
+		},
+		{
+			name:          "volume attributes class differs",
+			claim:         newClaim(map[string]string{"tier": "gold"}, map[string]string{"iops": "3000"}, pointerToString("silver"), pointerToString("silver")),
+			expectedPatch: true,
+		},
+		{
+			name:           "volume attributes class is modifying",
+			claim:          newClaim(map[string]string{"tier": "gold", "other": "kept"}, map[string]string{"iops": "3000"}, pointerToString("gold"), pointerToString("silver")),
+			expectedCompat: true,
+		},
+		{
+			name:           "all matched",
+			claim:          newClaim(map[string]string{"tier": "gold", "other": "kept"}, map[string]string{"iops": "3000"}, pointerToString("gold"), pointerToString("gold")),
+			expectedCompat: true,
+			expectedReady:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPVCNeedPatch(tt.claim, template); got != tt.expectedPatch {
+				t.Errorf("IsPVCNeedPatch() = %v, want %v", got, tt.expectedPatch)
+			}
+			compatible, ready := IsPVCCompatibleAndReady(tt.claim, template)
+			if compatible != tt.expectedCompat || ready != tt.expectedReady {
+				t.Errorf("IsPVCCompatibleAndReady() = %v, %v, want %v, %v", compatible, ready, tt.expectedCompat, tt.expectedReady)
+			}
+		})
+	}
+}
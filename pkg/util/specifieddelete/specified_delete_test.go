@@ -60,6 +60,15 @@ func TestIsSpecifiedDelete(t *testing.T) {
 	}
 }
 
+func TestIsSpecifiedReplace(t *testing.T) {
+	pod := &corev1.Pod{}
+	pod.SetLabels(map[string]string{v1alpha1.SpecifiedDeleteKey: "true"})
+	assert.False(t, IsSpecifiedReplace(pod))
+
+	pod.SetLabels(map[string]string{v1alpha1.SpecifiedReplaceKey: "true"})
+	assert.True(t, IsSpecifiedReplace(pod))
+}
+
 func TestPatchPodSpecifiedDelete(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = clientgoscheme.AddToScheme(scheme)
@@ -19,6 +19,7 @@ import (
 
 	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
 	clonesetcore "github.com/openkruise/kruise/pkg/controller/cloneset/core"
+	clonesetutils "github.com/openkruise/kruise/pkg/controller/cloneset/utils"
 	"github.com/openkruise/kruise/pkg/util"
 	webhookutil "github.com/openkruise/kruise/pkg/webhook/util"
 	"github.com/openkruise/kruise/pkg/webhook/util/convertor"
@@ -38,6 +39,9 @@ func (h *CloneSetCreateUpdateHandler) validateCloneSetSpec(spec, oldSpec *appsv1
 	allErrs := field.ErrorList{}
 
 	allErrs = append(allErrs, apivalidation.ValidateNonnegativeField(int64(*spec.Replicas), fldPath.Child("replicas"))...)
+	if spec.StandbyReplicas != nil {
+		allErrs = append(allErrs, apivalidation.ValidateNonnegativeField(int64(*spec.StandbyReplicas), fldPath.Child("standbyReplicas"))...)
+	}
 	if spec.Selector == nil {
 		allErrs = append(allErrs, field.Required(fldPath.Child("selector"), ""))
 	} else {
@@ -91,13 +95,20 @@ func (h *CloneSetCreateUpdateHandler) validateCloneSetSpec(spec, oldSpec *appsv1
 	}
 
 	allErrs = append(allErrs, h.validateScaleStrategy(&spec.ScaleStrategy, oldScaleStrategy, metadata, fldPath.Child("scaleStrategy"))...)
+	if spec.ScaleStrategy.PVCSnapshotPolicy != nil && len(spec.VolumeClaimTemplates) == 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("scaleStrategy", "pvcSnapshotPolicy"), "pvcSnapshotPolicy requires volumeClaimTemplates"))
+	}
 	allErrs = append(allErrs, h.validateUpdateStrategy(&spec.UpdateStrategy, int(*spec.Replicas), fldPath.Child("updateStrategy"))...)
+	allErrs = append(allErrs, webhookutil.ValidateInPlaceUpdateStrategy(spec.UpdateStrategy.InPlaceUpdateStrategy, &spec.Template,
+		fldPath.Child("updateStrategy", "inPlaceUpdateStrategy"))...)
 
 	if spec.ProgressDeadlineSeconds != nil {
 		allErrs = append(allErrs, apivalidation.ValidateNonnegativeField(int64(*spec.ProgressDeadlineSeconds), fldPath.Child("progressDeadlineSeconds"))...)
 		if *spec.ProgressDeadlineSeconds != math.MaxInt32 && *spec.ProgressDeadlineSeconds <= spec.MinReadySeconds {
 			allErrs = append(allErrs, field.Invalid(fldPath.Child("progressDeadlineSeconds"), spec.ProgressDeadlineSeconds, "must be greater than minReadySeconds"))
 		}
+	} else if spec.RollbackOnFailure {
+		allErrs = append(allErrs, field.Required(fldPath.Child("progressDeadlineSeconds"), "progressDeadlineSeconds is required for rollbackOnFailure"))
 	}
 
 	return allErrs
@@ -111,6 +122,10 @@ func (h *CloneSetCreateUpdateHandler) validateScaleStrategy(strategy, oldStrateg
 		return allErrs
 	}
 
+	if strategy.ScaleDownPolicy != nil {
+		allErrs = append(allErrs, validateScaleDownPolicy(strategy.ScaleDownPolicy, fldPath.Child("scaleDownPolicy"))...)
+	}
+
 	podsToDeleteSet := sets.NewString(strategy.PodsToDelete...)
 
 	if oldStrategy != nil && len(oldStrategy.PodsToDelete) > 0 {
@@ -131,6 +146,33 @@ func (h *CloneSetCreateUpdateHandler) validateScaleStrategy(strategy, oldStrateg
 	return allErrs
 }
 
+func validateScaleDownPolicy(policy *appsv1alpha1.CloneSetScaleDownPolicy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	scorerTypes := sets.NewString()
+	for i, scorer := range policy.Scorers {
+		scorerPath := fldPath.Child("scorers").Index(i)
+		if !clonesetutils.IsVictimScorerRegistered(scorer.Type) {
+			allErrs = append(allErrs, field.Invalid(scorerPath.Child("type"), scorer.Type, "unknown scorer type"))
+		} else if scorerTypes.Has(string(scorer.Type)) {
+			allErrs = append(allErrs, field.Duplicate(scorerPath.Child("type"), scorer.Type))
+		}
+		scorerTypes.Insert(string(scorer.Type))
+		if scorer.Weight < 1 || scorer.Weight > 100 {
+			allErrs = append(allErrs, field.Invalid(scorerPath.Child("weight"), scorer.Weight, "must be in the range 1-100"))
+		}
+		if scorer.TopologyKey != "" {
+			if scorer.Type != appsv1alpha1.ZoneSpreadScaleDownScorer {
+				allErrs = append(allErrs, field.Forbidden(scorerPath.Child("topologyKey"), "topologyKey is only supported by ZoneSpread scorer"))
+			} else {
+				allErrs = append(allErrs, unversionedvalidation.ValidateLabelName(scorer.TopologyKey, scorerPath.Child("topologyKey"))...)
+			}
+		}
+	}
+
+	return allErrs
+}
+
 func (h *CloneSetCreateUpdateHandler) validateUpdateStrategy(strategy *appsv1alpha1.CloneSetUpdateStrategy, replicas int, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	var err error
@@ -177,17 +219,72 @@ func (h *CloneSetCreateUpdateHandler) validateUpdateStrategy(strategy *appsv1alp
 			allErrs = append(allErrs, field.Invalid(fldPath.Child("maxSurge"), strategy.MaxSurge.String(),
 				fmt.Sprintf("failed GetScaledValueFromIntOrPercent for maxSurge: %v", err)))
 		}
-		if strategy.Type == appsv1alpha1.InPlaceOnlyCloneSetUpdateStrategyType && maxSurge > 0 {
+		if strategy.Type == appsv1alpha1.InPlaceOnlyCloneSetUpdateStrategyType && maxSurge > 0 && !strategy.InPlaceUpdateSurge {
 			allErrs = append(allErrs, field.Invalid(fldPath.Child("maxSurge"), strategy.MaxSurge.String(),
 				"can not use maxSurge with strategy type InPlaceOnly"))
 		}
 	}
 
+	if strategy.InPlaceUpdateSurge {
+		if strategy.Type == appsv1alpha1.RecreateCloneSetUpdateStrategyType {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("inPlaceUpdateSurge"), strategy.InPlaceUpdateSurge,
+				"can not use inPlaceUpdateSurge with strategy type ReCreate"))
+		}
+		if maxSurge < 1 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("inPlaceUpdateSurge"), strategy.InPlaceUpdateSurge,
+				"maxSurge should not be less than 1 for inPlaceUpdateSurge"))
+		}
+	}
+
 	if replicas > 0 && maxUnavailable < 1 && maxSurge < 1 {
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxUnavailable"), strategy.MaxUnavailable,
 			"maxUnavailable and maxSurge should not both be less than 1"))
 	}
 
+	if len(strategy.Steps) > 0 {
+		if partition > 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("partition"), "can not use non-zero partition with steps"))
+		}
+		allErrs = append(allErrs, validateUpdateSteps(strategy.Steps, replicas, fldPath.Child("steps"))...)
+	}
+
+	for i := range strategy.UpdateWindows {
+		if err := clonesetutils.ValidateUpdateWindow(&strategy.UpdateWindows[i]); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("updateWindows").Index(i), strategy.UpdateWindows[i], err.Error()))
+		}
+	}
+
+	return allErrs
+}
+
+func validateUpdateSteps(steps []appsv1alpha1.CloneSetUpdateStep, replicas int, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	lastPartition := math.MaxInt32
+	for i := range steps {
+		step := &steps[i]
+		stepPath := fldPath.Index(i)
+		partition, err := util.GetScaledValueFromIntOrPercent(&step.Partition, replicas, true)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(stepPath.Child("partition"), step.Partition.String(),
+				fmt.Sprintf("failed GetScaledValueFromIntOrPercent for partition: %v", err)))
+		} else if partition > lastPartition {
+			allErrs = append(allErrs, field.Invalid(stepPath.Child("partition"), step.Partition.String(),
+				"partition should not be greater than the one of the previous step"))
+		} else {
+			lastPartition = partition
+		}
+		allErrs = append(allErrs, apivalidation.ValidateNonnegativeField(int64(partition), stepPath.Child("partition"))...)
+		allErrs = append(allErrs, apivalidation.ValidateNonnegativeField(int64(step.PauseSeconds), stepPath.Child("pauseSeconds"))...)
+		for j, check := range step.Checks {
+			checkPath := stepPath.Child("checks").Index(j)
+			if check.PodConditionType == "" {
+				allErrs = append(allErrs, field.Required(checkPath.Child("podConditionType"), ""))
+			}
+			allErrs = append(allErrs, apivalidation.ValidateNonnegativeField(int64(check.TimeoutSeconds), checkPath.Child("timeoutSeconds"))...)
+		}
+	}
+
 	return allErrs
 }
 
@@ -204,8 +301,10 @@ func (h *CloneSetCreateUpdateHandler) validateCloneSetUpdate(cloneSet, oldCloneS
 	clone.Spec.RevisionHistoryLimit = oldCloneSet.Spec.RevisionHistoryLimit
 	clone.Spec.VolumeClaimTemplates = oldCloneSet.Spec.VolumeClaimTemplates
 	clone.Spec.ProgressDeadlineSeconds = oldCloneSet.Spec.ProgressDeadlineSeconds
+	clone.Spec.RollbackOnFailure = oldCloneSet.Spec.RollbackOnFailure
+	clone.Spec.StandbyReplicas = oldCloneSet.Spec.StandbyReplicas
 	if !apiequality.Semantic.DeepEqual(clone.Spec, oldCloneSet.Spec) {
-		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec"), "updates to cloneset spec for fields other than 'replicas', 'template', 'lifecycle', 'scaleStrategy', 'updateStrategy', 'minReadySeconds', 'progressDeadlineSeconds', 'volumeClaimTemplates' and 'revisionHistoryLimit' are forbidden"))
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec"), "updates to cloneset spec for fields other than 'replicas', 'template', 'lifecycle', 'scaleStrategy', 'updateStrategy', 'minReadySeconds', 'progressDeadlineSeconds', 'rollbackOnFailure', 'standbyReplicas', 'volumeClaimTemplates' and 'revisionHistoryLimit' are forbidden"))
 	}
 
 	coreControl := clonesetcore.New(cloneSet)
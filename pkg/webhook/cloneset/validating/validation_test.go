@@ -114,6 +114,27 @@ func TestValidate(t *testing.T) {
 	}
 
 	successCases := []testCase{
+		{
+			spec: &appsv1alpha1.CloneSetSpec{
+				Replicas: &val1,
+				Selector: &metav1.LabelSelector{MatchLabels: validLabels},
+				Template: validPodTemplate.Template,
+				UpdateStrategy: appsv1alpha1.CloneSetUpdateStrategy{
+					Type:           appsv1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType,
+					Partition:      &intOrStr0,
+					MaxUnavailable: &intOrStr1,
+				},
+				ScaleStrategy: appsv1alpha1.CloneSetScaleStrategy{
+					ScaleDownPolicy: &appsv1alpha1.CloneSetScaleDownPolicy{
+						Scorers: []appsv1alpha1.CloneSetScaleDownScorer{
+							{Type: appsv1alpha1.DeletionCostScaleDownScorer, Weight: 20},
+							{Type: appsv1alpha1.NodeBinPackingScaleDownScorer, Weight: 50},
+							{Type: appsv1alpha1.ZoneSpreadScaleDownScorer, Weight: 30, TopologyKey: v1.LabelTopologyZone},
+						},
+					},
+				},
+			},
+		},
 		{
 			spec: &appsv1alpha1.CloneSetSpec{
 				Replicas: &val1,
@@ -155,6 +176,35 @@ func TestValidate(t *testing.T) {
 				},
 			},
 		},
+		{
+			spec: &appsv1alpha1.CloneSetSpec{
+				Replicas: &val2,
+				Selector: &metav1.LabelSelector{MatchLabels: validLabels},
+				Template: validPodTemplate.Template,
+				UpdateStrategy: appsv1alpha1.CloneSetUpdateStrategy{
+					Type:               appsv1alpha1.InPlaceOnlyCloneSetUpdateStrategyType,
+					Partition:          &intOrStr0,
+					MaxUnavailable:     &intOrStr0,
+					MaxSurge:           &intOrStr1,
+					InPlaceUpdateSurge: true,
+				},
+			},
+		},
+		{
+			spec: &appsv1alpha1.CloneSetSpec{
+				Replicas: &val2,
+				Selector: &metav1.LabelSelector{MatchLabels: validLabels},
+				Template: validPodTemplate.Template,
+				UpdateStrategy: appsv1alpha1.CloneSetUpdateStrategy{
+					Type:           appsv1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType,
+					Partition:      &intOrStr0,
+					MaxUnavailable: &intOrStr1,
+					UpdateWindows: []appsv1alpha1.CloneSetUpdateWindow{
+						{Start: "22:00", End: "06:00", Days: []string{"Sat", "Sun"}, TimeZone: "Asia/Shanghai"},
+					},
+				},
+			},
+		},
 		{
 			spec: &appsv1alpha1.CloneSetSpec{
 				Replicas: &val1,
@@ -276,6 +326,22 @@ func TestValidate(t *testing.T) {
 				MinReadySeconds:         5,
 			},
 		},
+		{
+			spec: &appsv1alpha1.CloneSetSpec{
+				Replicas: &val2,
+				Selector: &metav1.LabelSelector{MatchLabels: validLabels},
+				Template: validPodTemplate.Template,
+				UpdateStrategy: appsv1alpha1.CloneSetUpdateStrategy{
+					Type:           appsv1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType,
+					Partition:      util.GetIntOrStrPointer(intstr.FromInt32(0)),
+					MaxUnavailable: &intOrStr1,
+					Steps: []appsv1alpha1.CloneSetUpdateStep{
+						{Partition: intstr.FromString("50%"), PauseSeconds: 60},
+						{Partition: intstr.FromInt32(0), Checks: []appsv1alpha1.CloneSetUpdateStepCheck{{PodConditionType: "Healthy"}}},
+					},
+				},
+			},
+		},
 	}
 
 	for i, successCase := range successCases {
@@ -636,6 +702,221 @@ func TestValidate(t *testing.T) {
 			},
 			expectField: "spec",
 		},
+		"steps-with-partition": {
+			spec: &appsv1alpha1.CloneSetSpec{
+				Replicas: &val2,
+				Selector: &metav1.LabelSelector{MatchLabels: validLabels},
+				Template: validPodTemplate.Template,
+				UpdateStrategy: appsv1alpha1.CloneSetUpdateStrategy{
+					Type:           appsv1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType,
+					Partition:      util.GetIntOrStrPointer(intstr.FromInt32(1)),
+					MaxUnavailable: &intOrStr1,
+					Steps:          []appsv1alpha1.CloneSetUpdateStep{{Partition: intstr.FromInt32(0)}},
+				},
+			},
+			expectField: "spec.updateStrategy.partition",
+		},
+		"steps-with-increasing-partition": {
+			spec: &appsv1alpha1.CloneSetSpec{
+				Replicas: &val2,
+				Selector: &metav1.LabelSelector{MatchLabels: validLabels},
+				Template: validPodTemplate.Template,
+				UpdateStrategy: appsv1alpha1.CloneSetUpdateStrategy{
+					Type:           appsv1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType,
+					MaxUnavailable: &intOrStr1,
+					Steps: []appsv1alpha1.CloneSetUpdateStep{
+						{Partition: intstr.FromInt32(0)},
+						{Partition: intstr.FromInt32(1)},
+					},
+				},
+			},
+			expectField: "spec.updateStrategy.steps[1].partition",
+		},
+		"steps-with-empty-check": {
+			spec: &appsv1alpha1.CloneSetSpec{
+				Replicas: &val2,
+				Selector: &metav1.LabelSelector{MatchLabels: validLabels},
+				Template: validPodTemplate.Template,
+				UpdateStrategy: appsv1alpha1.CloneSetUpdateStrategy{
+					Type:           appsv1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType,
+					MaxUnavailable: &intOrStr1,
+					Steps: []appsv1alpha1.CloneSetUpdateStep{
+						{Partition: intstr.FromInt32(0), Checks: []appsv1alpha1.CloneSetUpdateStepCheck{{}}},
+					},
+				},
+			},
+			expectField: "spec.updateStrategy.steps[0].checks[0].podConditionType",
+		},
+		"rollback-without-progress-deadline": {
+			spec: &appsv1alpha1.CloneSetSpec{
+				Replicas:          &val2,
+				Selector:          &metav1.LabelSelector{MatchLabels: validLabels},
+				Template:          validPodTemplate.Template,
+				RollbackOnFailure: true,
+				UpdateStrategy: appsv1alpha1.CloneSetUpdateStrategy{
+					Type:           appsv1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType,
+					Partition:      &intOrStr0,
+					MaxUnavailable: &intOrStr1,
+				},
+			},
+			expectField: "spec.progressDeadlineSeconds",
+		},
+		"negative-standby-replicas": {
+			spec: &appsv1alpha1.CloneSetSpec{
+				Replicas:        &val2,
+				StandbyReplicas: &minus1,
+				Selector:        &metav1.LabelSelector{MatchLabels: validLabels},
+				Template:        validPodTemplate.Template,
+				UpdateStrategy: appsv1alpha1.CloneSetUpdateStrategy{
+					Type:           appsv1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType,
+					Partition:      &intOrStr0,
+					MaxUnavailable: &intOrStr1,
+				},
+			},
+			expectField: "spec.standbyReplicas",
+		},
+		"pvc-snapshot-policy-without-volume-claim-templates": {
+			spec: &appsv1alpha1.CloneSetSpec{
+				Replicas: &val2,
+				Selector: &metav1.LabelSelector{MatchLabels: validLabels},
+				Template: validPodTemplate.Template,
+				ScaleStrategy: appsv1alpha1.CloneSetScaleStrategy{
+					PVCSnapshotPolicy: &appsv1alpha1.CloneSetPVCSnapshotPolicy{},
+				},
+				UpdateStrategy: appsv1alpha1.CloneSetUpdateStrategy{
+					Type:           appsv1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType,
+					Partition:      &intOrStr0,
+					MaxUnavailable: &intOrStr1,
+				},
+			},
+			expectField: "spec.scaleStrategy.pvcSnapshotPolicy",
+		},
+		"invalid-update-window-time": {
+			spec: &appsv1alpha1.CloneSetSpec{
+				Replicas: &val2,
+				Selector: &metav1.LabelSelector{MatchLabels: validLabels},
+				Template: validPodTemplate.Template,
+				UpdateStrategy: appsv1alpha1.CloneSetUpdateStrategy{
+					Type:           appsv1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType,
+					Partition:      &intOrStr0,
+					MaxUnavailable: &intOrStr1,
+					UpdateWindows:  []appsv1alpha1.CloneSetUpdateWindow{{Start: "25:00", End: "06:00"}},
+				},
+			},
+			expectField: "spec.updateStrategy.updateWindows[0]",
+		},
+		"invalid-update-window-time-zone": {
+			spec: &appsv1alpha1.CloneSetSpec{
+				Replicas: &val2,
+				Selector: &metav1.LabelSelector{MatchLabels: validLabels},
+				Template: validPodTemplate.Template,
+				UpdateStrategy: appsv1alpha1.CloneSetUpdateStrategy{
+					Type:           appsv1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType,
+					Partition:      &intOrStr0,
+					MaxUnavailable: &intOrStr1,
+					UpdateWindows:  []appsv1alpha1.CloneSetUpdateWindow{{Start: "22:00", End: "06:00", TimeZone: "Mars/Olympus"}},
+				},
+			},
+			expectField: "spec.updateStrategy.updateWindows[0]",
+		},
+		"in-place-update-surge-with-recreate": {
+			spec: &appsv1alpha1.CloneSetSpec{
+				Replicas: &val2,
+				Selector: &metav1.LabelSelector{MatchLabels: validLabels},
+				Template: validPodTemplate.Template,
+				UpdateStrategy: appsv1alpha1.CloneSetUpdateStrategy{
+					Type:               appsv1alpha1.RecreateCloneSetUpdateStrategyType,
+					Partition:          &intOrStr0,
+					MaxSurge:           &intOrStr1,
+					InPlaceUpdateSurge: true,
+				},
+			},
+			expectField: "spec.updateStrategy.inPlaceUpdateSurge",
+		},
+		"in-place-update-surge-without-max-surge": {
+			spec: &appsv1alpha1.CloneSetSpec{
+				Replicas: &val2,
+				Selector: &metav1.LabelSelector{MatchLabels: validLabels},
+				Template: validPodTemplate.Template,
+				UpdateStrategy: appsv1alpha1.CloneSetUpdateStrategy{
+					Type:               appsv1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType,
+					Partition:          &intOrStr0,
+					MaxUnavailable:     &intOrStr1,
+					InPlaceUpdateSurge: true,
+				},
+			},
+			expectField: "spec.updateStrategy.inPlaceUpdateSurge",
+		},
+		"container-update-order-with-unknown-container": {
+			spec: &appsv1alpha1.CloneSetSpec{
+				Replicas: &val2,
+				Selector: &metav1.LabelSelector{MatchLabels: validLabels},
+				Template: validPodTemplate.Template,
+				UpdateStrategy: appsv1alpha1.CloneSetUpdateStrategy{
+					Type:           appsv1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType,
+					Partition:      &intOrStr0,
+					MaxUnavailable: &intOrStr1,
+					InPlaceUpdateStrategy: &appspub.InPlaceUpdateStrategy{
+						ContainerUpdateOrder: []appspub.InPlaceUpdateContainerOrder{{Containers: []string{"abc"}}, {Containers: []string{"log-agent"}}},
+					},
+				},
+			},
+			expectField: "spec.updateStrategy.inPlaceUpdateStrategy.containerUpdateOrder[1].containers[0]",
+		},
+		"scale-down-policy-with-unknown-scorer": {
+			spec: &appsv1alpha1.CloneSetSpec{
+				Replicas: &val2,
+				Selector: &metav1.LabelSelector{MatchLabels: validLabels},
+				Template: validPodTemplate.Template,
+				ScaleStrategy: appsv1alpha1.CloneSetScaleStrategy{
+					ScaleDownPolicy: &appsv1alpha1.CloneSetScaleDownPolicy{
+						Scorers: []appsv1alpha1.CloneSetScaleDownScorer{{Type: "Random", Weight: 10}},
+					},
+				},
+				UpdateStrategy: appsv1alpha1.CloneSetUpdateStrategy{
+					Type:           appsv1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType,
+					Partition:      &intOrStr0,
+					MaxUnavailable: &intOrStr1,
+				},
+			},
+			expectField: "spec.scaleStrategy.scaleDownPolicy.scorers[0].type",
+		},
+		"scale-down-policy-with-invalid-weight": {
+			spec: &appsv1alpha1.CloneSetSpec{
+				Replicas: &val2,
+				Selector: &metav1.LabelSelector{MatchLabels: validLabels},
+				Template: validPodTemplate.Template,
+				ScaleStrategy: appsv1alpha1.CloneSetScaleStrategy{
+					ScaleDownPolicy: &appsv1alpha1.CloneSetScaleDownPolicy{
+						Scorers: []appsv1alpha1.CloneSetScaleDownScorer{{Type: appsv1alpha1.NodeBinPackingScaleDownScorer, Weight: 101}},
+					},
+				},
+				UpdateStrategy: appsv1alpha1.CloneSetUpdateStrategy{
+					Type:           appsv1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType,
+					Partition:      &intOrStr0,
+					MaxUnavailable: &intOrStr1,
+				},
+			},
+			expectField: "spec.scaleStrategy.scaleDownPolicy.scorers[0].weight",
+		},
+		"scale-down-policy-with-duplicated-scorer": {
+			spec: &appsv1alpha1.CloneSetSpec{
+				Replicas: &val2,
+				Selector: &metav1.LabelSelector{MatchLabels: validLabels},
+				Template: validPodTemplate.Template,
+				ScaleStrategy: appsv1alpha1.CloneSetScaleStrategy{
+					ScaleDownPolicy: &appsv1alpha1.CloneSetScaleDownPolicy{
+						Scorers: []appsv1alpha1.CloneSetScaleDownScorer{{Type: appsv1alpha1.DeletionCostScaleDownScorer, Weight: 10}, {Type: appsv1alpha1.DeletionCostScaleDownScorer, Weight: 20}},
+					},
+				},
+				UpdateStrategy: appsv1alpha1.CloneSetUpdateStrategy{
+					Type:           appsv1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType,
+					Partition:      &intOrStr0,
+					MaxUnavailable: &intOrStr1,
+				},
+			},
+			expectField: "spec.scaleStrategy.scaleDownPolicy.scorers[1].type",
+		},
 	}
 
 	for k, v := range errorCases {
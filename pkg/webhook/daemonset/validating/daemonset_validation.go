@@ -2,6 +2,7 @@ package validating
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strconv"
 
@@ -11,6 +12,8 @@ import (
 	metavalidation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	appsvalidation "k8s.io/kubernetes/pkg/apis/apps/validation"
@@ -229,6 +232,59 @@ func validateDaemonSetSpecV1beta1(spec *appsv1beta1.DaemonSetSpec, fldPath *fiel
 			allErrs = append(allErrs, field.Forbidden(fldPath.Child("lifecycle", "inPlaceUpdate"), "inPlaceUpdate hook has not supported yet"))
 		}
 	}
+
+	allErrs = append(allErrs, validateNodeOverrides(spec, fldPath.Child("nodeOverrides"))...)
+	if spec.NodeDrainPolicy != nil {
+		allErrs = append(allErrs, validateNodeDrainPolicy(spec.NodeDrainPolicy, fldPath.Child("nodeDrainPolicy"))...)
+	}
+	return allErrs
+}
+
+func validateNodeDrainPolicy(policy *appsv1beta1.DaemonSetNodeDrainPolicy, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, key := range policy.Taints {
+		for _, msg := range validation.IsQualifiedName(key) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("taints").Index(i), key, msg))
+		}
+	}
+	if policy.WaitForOtherPods && !policy.EvictPods {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("waitForOtherPods"), policy.WaitForOtherPods, "requires evictPods to be true"))
+	}
+	return allErrs
+}
+
+func validateNodeOverrides(spec *appsv1beta1.DaemonSetSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, override := range spec.NodeOverrides {
+		idxPath := fldPath.Index(i)
+		if override.NodeSelector == nil {
+			allErrs = append(allErrs, field.Required(idxPath.Child("nodeSelector"), ""))
+		} else {
+			allErrs = append(allErrs, metavalidation.ValidateLabelSelector(override.NodeSelector, metavalidation.LabelSelectorValidationOptions{}, idxPath.Child("nodeSelector"))...)
+		}
+
+		if override.Patch.Raw == nil {
+			allErrs = append(allErrs, field.Required(idxPath.Child("patch"), ""))
+			continue
+		}
+		templateBytes, _ := json.Marshal(spec.Template)
+		modified, err := strategicpatch.StrategicMergePatch(templateBytes, override.Patch.Raw, &corev1.PodTemplateSpec{})
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("patch"), string(override.Patch.Raw), fmt.Sprintf("failed to merge patch: %v", err)))
+			continue
+		}
+		newTemplate := &corev1.PodTemplateSpec{}
+		if err = json.Unmarshal(modified, newTemplate); err != nil {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("patch"), string(override.Patch.Raw), fmt.Sprintf("failed to unmarshal: %v", err)))
+			continue
+		}
+		coreTemplate, err := convertor.ConvertPodTemplateSpec(newTemplate)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("patch"), string(override.Patch.Raw), fmt.Sprintf("Convert_v1_PodTemplateSpec_To_core_PodTemplateSpec failed: %v", err)))
+			continue
+		}
+		allErrs = append(allErrs, corevalidation.ValidatePodTemplateSpec(coreTemplate, idxPath.Child("patch"), webhookutil.DefaultPodValidationOptions)...)
+	}
 	return allErrs
 }
 
@@ -270,6 +326,9 @@ func validateRollingUpdateDaemonSetV1beta1(rollingUpdate *appsv1beta1.RollingUpd
 	case !hasUnavailable && !hasSurge:
 		allErrs = append(allErrs, field.Required(fldPath.Child("maxUnavailable"), "cannot be 0 when maxSurge is 0"))
 	}
+	if rollingUpdate.HostPortHandoff && !hasSurge {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("hostPortHandoff"), rollingUpdate.HostPortHandoff, "requires maxSurge to be non-zero"))
+	}
 
 	switch rollingUpdate.Type {
 	case "", appsv1beta1.StandardRollingUpdateType:
@@ -287,5 +346,32 @@ func validateRollingUpdateDaemonSetV1beta1(rollingUpdate *appsv1beta1.RollingUpd
 		allErrs = append(allErrs, appsvalidation.IsNotMoreThan100Percent(*rollingUpdate.Partition, fldPath.Child("rollingUpdate").Child("partition"))...)
 	}
 
+	allErrs = append(allErrs, validateRollingUpdateWaves(rollingUpdate.Waves, fldPath.Child("waves"))...)
+	return allErrs
+}
+
+func validateRollingUpdateWaves(waves []appsv1beta1.RollingUpdateWave, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	names := sets.NewString()
+	for i, wave := range waves {
+		idxPath := fldPath.Index(i)
+		if wave.Name == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), ""))
+		} else if names.Has(wave.Name) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), wave.Name))
+		}
+		names.Insert(wave.Name)
+
+		if wave.NodeSelector == nil {
+			allErrs = append(allErrs, field.Required(idxPath.Child("nodeSelector"), ""))
+		} else {
+			allErrs = append(allErrs, metavalidation.ValidateLabelSelector(wave.NodeSelector, metavalidation.LabelSelectorValidationOptions{}, idxPath.Child("nodeSelector"))...)
+		}
+
+		if wave.MaxUnavailable != nil {
+			allErrs = append(allErrs, appsvalidation.ValidatePositiveIntOrPercent(*wave.MaxUnavailable, idxPath.Child("maxUnavailable"))...)
+			allErrs = append(allErrs, appsvalidation.IsNotMoreThan100Percent(*wave.MaxUnavailable, idxPath.Child("maxUnavailable"))...)
+		}
+	}
 	return allErrs
 }
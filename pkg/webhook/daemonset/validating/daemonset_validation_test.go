@@ -25,8 +25,10 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	appspub "github.com/openkruise/kruise/apis/apps/pub"
 	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
@@ -738,6 +740,142 @@ func TestValidateDaemonSetV1beta1(t *testing.T) {
 	}
 }
 
+func TestValidateNodeOverrides(t *testing.T) {
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "big"}}
+	for _, c := range []struct {
+		Title       string
+		Override    appsv1beta1.DaemonSetNodeOverride
+		ExpectValid bool
+	}{
+		{
+			"valid override",
+			appsv1beta1.DaemonSetNodeOverride{
+				NodeSelector: selector,
+				Patch:        runtime.RawExtension{Raw: []byte(`{"spec":{"containers":[{"name":"a","env":[{"name":"SIZE","value":"big"}]}]}}`)},
+			},
+			true,
+		},
+		{
+			"missing nodeSelector",
+			appsv1beta1.DaemonSetNodeOverride{Patch: runtime.RawExtension{Raw: []byte(`{"metadata":{"labels":{"pool":"big"}}}`)}},
+			false,
+		},
+		{
+			"missing patch",
+			appsv1beta1.DaemonSetNodeOverride{NodeSelector: selector},
+			false,
+		},
+		{
+			"invalid template patched",
+			appsv1beta1.DaemonSetNodeOverride{
+				NodeSelector: selector,
+				Patch:        runtime.RawExtension{Raw: []byte(`{"spec":{"containers":[{"name":"a","image":""}]}}`)},
+			},
+			false,
+		},
+	} {
+		spec := &appsv1beta1.DaemonSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers:    []corev1.Container{{Name: "a", Image: "b", ImagePullPolicy: corev1.PullIfNotPresent, TerminationMessagePolicy: corev1.TerminationMessageReadFile}},
+					RestartPolicy: corev1.RestartPolicyAlways,
+					DNSPolicy:     corev1.DNSClusterFirst,
+				},
+			},
+			NodeOverrides: []appsv1beta1.DaemonSetNodeOverride{c.Override},
+		}
+		errs := validateNodeOverrides(spec, field.NewPath("nodeOverrides"))
+		if valid := len(errs) == 0; valid != c.ExpectValid {
+			t.Fatalf("case: %s, expected valid: %v, got errors: %v", c.Title, c.ExpectValid, errs)
+		}
+	}
+}
+
+func TestValidateNodeDrainPolicy(t *testing.T) {
+	for _, c := range []struct {
+		Title       string
+		Policy      *appsv1beta1.DaemonSetNodeDrainPolicy
+		ExpectValid bool
+	}{
+		{
+			"valid policy",
+			&appsv1beta1.DaemonSetNodeDrainPolicy{Taints: []string{"node.kubernetes.io/draining"}, EvictPods: true, WaitForOtherPods: true},
+			true,
+		},
+		{
+			"invalid taint key",
+			&appsv1beta1.DaemonSetNodeDrainPolicy{Taints: []string{""}},
+			false,
+		},
+		{
+			"waitForOtherPods without evictPods",
+			&appsv1beta1.DaemonSetNodeDrainPolicy{WaitForOtherPods: true},
+			false,
+		},
+	} {
+		errs := validateNodeDrainPolicy(c.Policy, field.NewPath("nodeDrainPolicy"))
+		if valid := len(errs) == 0; valid != c.ExpectValid {
+			t.Fatalf("case: %s, expected valid: %v, got errors: %v", c.Title, c.ExpectValid, errs)
+		}
+	}
+}
+
+func TestValidateHostPortHandoff(t *testing.T) {
+	zero, one := intstr.FromInt(0), intstr.FromInt(1)
+	rollingUpdate := &appsv1beta1.RollingUpdateDaemonSet{MaxUnavailable: &zero, MaxSurge: &one, HostPortHandoff: true}
+	if errs := validateRollingUpdateDaemonSetV1beta1(rollingUpdate, field.NewPath("rollingUpdate")); len(errs) != 0 {
+		t.Fatalf("expected valid, got errors: %v", errs)
+	}
+	rollingUpdate = &appsv1beta1.RollingUpdateDaemonSet{MaxUnavailable: &one, HostPortHandoff: true}
+	if errs := validateRollingUpdateDaemonSetV1beta1(rollingUpdate, field.NewPath("rollingUpdate")); len(errs) == 0 {
+		t.Fatalf("expected errors for hostPortHandoff without maxSurge")
+	}
+}
+
+func TestValidateRollingUpdateWaves(t *testing.T) {
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "a"}}
+	one, negative := intstr.FromInt(1), intstr.FromInt(-1)
+	for _, c := range []struct {
+		Title       string
+		Waves       []appsv1beta1.RollingUpdateWave
+		ExpectValid bool
+	}{
+		{
+			"valid waves",
+			[]appsv1beta1.RollingUpdateWave{
+				{Name: "a", NodeSelector: selector, MaxUnavailable: &one},
+				{Name: "b", NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "b"}}},
+			},
+			true,
+		},
+		{
+			"duplicate name",
+			[]appsv1beta1.RollingUpdateWave{{Name: "a", NodeSelector: selector}, {Name: "a", NodeSelector: selector}},
+			false,
+		},
+		{
+			"missing name",
+			[]appsv1beta1.RollingUpdateWave{{NodeSelector: selector}},
+			false,
+		},
+		{
+			"missing nodeSelector",
+			[]appsv1beta1.RollingUpdateWave{{Name: "a"}},
+			false,
+		},
+		{
+			"negative maxUnavailable",
+			[]appsv1beta1.RollingUpdateWave{{Name: "a", NodeSelector: selector, MaxUnavailable: &negative}},
+			false,
+		},
+	} {
+		errs := validateRollingUpdateWaves(c.Waves, field.NewPath("waves"))
+		if valid := len(errs) == 0; valid != c.ExpectValid {
+			t.Fatalf("case: %s, expected valid: %v, got errors: %v", c.Title, c.ExpectValid, errs)
+		}
+	}
+}
+
 func TestValidateDaemonSetUpdateV1beta1(t *testing.T) {
 	handler := DaemonSetCreateUpdateHandler{}
 	validLabels := map[string]string{"a": "b"}
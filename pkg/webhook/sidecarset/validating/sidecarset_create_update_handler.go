@@ -118,12 +118,6 @@ func validateSidecarSetName(name string, _ bool) (allErrs []string) {
 func (h *SidecarSetCreateUpdateHandler) validateSidecarSetSpec(obj *appsv1beta1.SidecarSet, fldPath *field.Path) field.ErrorList {
 	spec := &obj.Spec
 	allErrs := field.ErrorList{}
-	// currently when initContainer restartPolicy = Always, kruise don't support in-place update
-	for _, c := range obj.Spec.InitContainers {
-		if sidecarcontrol.IsSidecarContainer(c.Container) && obj.Spec.UpdateStrategy.Type == appsv1beta1.RollingUpdateSidecarSetStrategyType {
-			allErrs = append(allErrs, field.Required(fldPath.Child("updateStrategy"), "The initContainer in-place upgrade is not currently supported."))
-		}
-	}
 
 	// validate spec selector
 	if spec.Selector == nil {
@@ -249,6 +243,42 @@ func validateSidecarSetUpdateStrategy(strategy *appsv1beta1.SidecarSetUpdateStra
 				allErrs = append(allErrs, field.Required(fldPath.Child("scatterStrategy"), err.Error()))
 			}
 		}
+		if len(strategy.RolloutPhases) > 0 {
+			if intStrIsSet(strategy.Partition) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("partition"), strategy.Partition.String(), "Partition and RolloutPhases cannot be used together"))
+			}
+			allErrs = append(allErrs, validateRolloutPhases(strategy.RolloutPhases, fldPath.Child("rolloutPhases"))...)
+		}
+	}
+	return allErrs
+}
+
+func validateRolloutPhases(phases []appsv1beta1.SidecarSetRolloutPhase, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := sets.NewString()
+	for i, phase := range phases {
+		idxPath := fldPath.Index(i)
+		if phase.Name == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), ""))
+		} else if names.Has(phase.Name) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), phase.Name))
+		}
+		names.Insert(phase.Name)
+
+		if phase.NamespaceSelector == nil && len(phase.Workloads) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath, "namespaceSelector or workloads is required"))
+		}
+		if phase.NamespaceSelector != nil {
+			allErrs = append(allErrs, validateSelector(phase.NamespaceSelector, idxPath.Child("namespaceSelector"))...)
+		}
+		for j, workload := range phase.Workloads {
+			if workload.Kind == "" {
+				allErrs = append(allErrs, field.Required(idxPath.Child("workloads").Index(j).Child("kind"), ""))
+			}
+		}
+		if phase.HealthGate != nil && phase.HealthGate.MinReadySeconds < 0 {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("healthGate", "minReadySeconds"), phase.HealthGate.MinReadySeconds, "must be non-negative"))
+		}
 	}
 	return allErrs
 }
@@ -9,6 +9,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/pointer"
@@ -647,7 +648,7 @@ func TestValidateSidecarSet(t *testing.T) {
 			expectErrs: 1,
 		},
 		{
-			caseName: "The initContainer in-place upgrade is not currently supported.",
+			caseName: "native sidecar initContainer supports in-place upgrade",
 			sidecarSet: appsv1beta1.SidecarSet{
 				ObjectMeta: metav1.ObjectMeta{Name: "test-sidecarset"},
 				Spec: appsv1beta1.SidecarSetSpec{
@@ -677,7 +678,7 @@ func TestValidateSidecarSet(t *testing.T) {
 					},
 				},
 			},
-			expectErrs: 1,
+			expectErrs: 0,
 		},
 		// ResourcesPolicy validation test cases
 		{
@@ -1418,3 +1419,70 @@ func TestSidecarSetVolumeConflict(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateRolloutPhases(t *testing.T) {
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"env": "canary"}}
+	cases := []struct {
+		name       string
+		strategy   appsv1beta1.SidecarSetUpdateStrategy
+		expectErrs int
+	}{
+		{
+			name: "valid phases",
+			strategy: appsv1beta1.SidecarSetUpdateStrategy{
+				RolloutPhases: []appsv1beta1.SidecarSetRolloutPhase{
+					{Name: "canary", NamespaceSelector: selector, HealthGate: &appsv1beta1.SidecarSetRolloutHealthGate{MinReadySeconds: 60, NoRestarts: true}},
+					{Name: "web", Workloads: []appsv1beta1.SidecarSetRolloutWorkload{{Kind: "Deployment", Name: "web"}}},
+				},
+			},
+		},
+		{
+			name: "phase without name and selector",
+			strategy: appsv1beta1.SidecarSetUpdateStrategy{
+				RolloutPhases: []appsv1beta1.SidecarSetRolloutPhase{{}},
+			},
+			expectErrs: 2,
+		},
+		{
+			name: "duplicated phase name",
+			strategy: appsv1beta1.SidecarSetUpdateStrategy{
+				RolloutPhases: []appsv1beta1.SidecarSetRolloutPhase{
+					{Name: "canary", NamespaceSelector: selector},
+					{Name: "canary", NamespaceSelector: selector},
+				},
+			},
+			expectErrs: 1,
+		},
+		{
+			name: "workload without kind and negative minReadySeconds",
+			strategy: appsv1beta1.SidecarSetUpdateStrategy{
+				RolloutPhases: []appsv1beta1.SidecarSetRolloutPhase{
+					{
+						Name:       "web",
+						Workloads:  []appsv1beta1.SidecarSetRolloutWorkload{{Name: "web"}},
+						HealthGate: &appsv1beta1.SidecarSetRolloutHealthGate{MinReadySeconds: -1},
+					},
+				},
+			},
+			expectErrs: 2,
+		},
+		{
+			name: "phases with partition",
+			strategy: appsv1beta1.SidecarSetUpdateStrategy{
+				Partition:     ptr.To(intstr.FromInt32(1)),
+				RolloutPhases: []appsv1beta1.SidecarSetRolloutPhase{{Name: "canary", NamespaceSelector: selector}},
+			},
+			expectErrs: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.strategy.Type = appsv1beta1.RollingUpdateSidecarSetStrategyType
+			allErrs := validateSidecarSetUpdateStrategy(&tc.strategy, field.NewPath("spec", "updateStrategy"))
+			if len(allErrs) != tc.expectErrs {
+				t.Fatalf("expect errors len %d, but got: %v", tc.expectErrs, util.DumpJSON(allErrs))
+			}
+		})
+	}
+}
@@ -11,9 +11,12 @@ import (
 	v1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	unversionedvalidation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	appsvalidation "k8s.io/kubernetes/pkg/apis/apps/validation"
 	apivalidation "k8s.io/kubernetes/pkg/apis/core/validation"
@@ -66,6 +69,129 @@ func validateReserveOrdinals(spec *appsv1beta1.StatefulSetSpec, fldPath *field.P
 	return allErrs
 }
 
+func validateStartupDependencies(spec *appsv1beta1.StatefulSetSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if len(spec.StartupDependencies) == 0 {
+		return allErrs
+	}
+	if spec.PodManagementPolicy != apps.ParallelPodManagement {
+		allErrs = append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("only works with '%s' podManagementPolicy", apps.ParallelPodManagement)))
+	}
+	for i, dependency := range spec.StartupDependencies {
+		idxPath := fldPath.Index(i)
+		allErrs = append(allErrs, validateOrdinalList(dependency.Ordinals, idxPath.Child("ordinals"))...)
+		allErrs = append(allErrs, validateOrdinalList(dependency.DependsOnOrdinals, idxPath.Child("dependsOnOrdinals"))...)
+		if len(dependency.DependsOnOrdinals) == 0 && dependency.DependsOnSelector == nil {
+			allErrs = append(allErrs, field.Required(idxPath, "one of dependsOnOrdinals and dependsOnSelector must be set"))
+		}
+		if dependency.DependsOnSelector != nil {
+			allErrs = append(allErrs, unversionedvalidation.ValidateLabelSelector(dependency.DependsOnSelector,
+				unversionedvalidation.LabelSelectorValidationOptions{}, idxPath.Child("dependsOnSelector"))...)
+			if len(dependency.DependsOnSelector.MatchLabels)+len(dependency.DependsOnSelector.MatchExpressions) == 0 {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("dependsOnSelector"), dependency.DependsOnSelector, "empty selector is invalid"))
+			}
+		}
+	}
+	if len(allErrs) == 0 {
+		if ord, found := findStartupDependencyCycle(spec.StartupDependencies); found {
+			allErrs = append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("pod with ordinal %d waits for itself through a dependency cycle", ord)))
+		}
+	}
+	return allErrs
+}
+
+// findStartupDependencyCycle returns an ordinal in the cycle of dependsOnOrdinals, which makes the pods wait
+// for each other forever. The dependsOnSelector can not be resolved without pods, so it is ignored.
+func findStartupDependencyCycle(dependencies []appsv1beta1.StatefulSetStartupDependency) (int, bool) {
+	ordinals := sets.New[int]()
+	for _, dependency := range dependencies {
+		ordinals.Insert(sets.List(apiutil.GetReserveOrdinalIntSet(dependency.Ordinals))...)
+		ordinals.Insert(sets.List(apiutil.GetReserveOrdinalIntSet(dependency.DependsOnOrdinals))...)
+	}
+	edges := make(map[int]sets.Set[int], ordinals.Len())
+	for _, dependency := range dependencies {
+		dependsOnOrdinals := apiutil.GetReserveOrdinalIntSet(dependency.DependsOnOrdinals)
+		waiting := apiutil.GetReserveOrdinalIntSet(dependency.Ordinals)
+		if len(dependency.Ordinals) == 0 {
+			// all the pods except the dependencies will wait
+			waiting = ordinals.Difference(dependsOnOrdinals)
+		}
+		for ord := range waiting {
+			if edges[ord] == nil {
+				edges[ord] = sets.New[int]()
+			}
+			// a pod never waits for itself
+			edges[ord].Insert(sets.List(dependsOnOrdinals.Clone().Delete(ord))...)
+		}
+	}
+
+	// visiting ordinals are in the current path, and visited ordinals are known to be out of any cycle
+	visiting, visited := sets.New[int](), sets.New[int]()
+	var cycleOrdinal int
+	var visit func(ord int) bool
+	visit = func(ord int) bool {
+		if visited.Has(ord) {
+			return false
+		}
+		if visiting.Has(ord) {
+			cycleOrdinal = ord
+			return true
+		}
+		visiting.Insert(ord)
+		for _, next := range sets.List(edges[ord]) {
+			if visit(next) {
+				return true
+			}
+		}
+		visiting.Delete(ord)
+		visited.Insert(ord)
+		return false
+	}
+	for _, ord := range sets.List(ordinals) {
+		if visit(ord) {
+			return cycleOrdinal, true
+		}
+	}
+	return 0, false
+}
+
+func validateOrdinals(ordinals *appsv1beta1.StatefulSetOrdinals, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if ordinals == nil || ordinals.Coordination == nil {
+		return allErrs
+	}
+	coordinationPath := fldPath.Child("coordination", "configMapName")
+	if ordinals.Coordination.ConfigMapName == "" {
+		return append(allErrs, field.Required(coordinationPath, ""))
+	}
+	for _, msg := range apivalidation.ValidateConfigMapName(ordinals.Coordination.ConfigMapName, false) {
+		allErrs = append(allErrs, field.Invalid(coordinationPath, ordinals.Coordination.ConfigMapName, msg))
+	}
+	if namespace := ordinals.Coordination.Namespace; namespace != "" {
+		for _, msg := range apivalidation.ValidateNamespaceName(namespace, false) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("coordination", "namespace"), namespace, msg))
+		}
+	}
+	return allErrs
+}
+
+func validateOrdinalList(ordinals []intstr.IntOrString, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, elem := range ordinals {
+		if elem.Type == intstr.String {
+			if !reserveOrdinalRangeRexp.MatchString(elem.StrVal) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Index(i), elem.StrVal, "is not a valid range"))
+			} else if _, _, err := apiutil.ParseRange(elem.StrVal); err != nil {
+				allErrs = append(allErrs, field.Invalid(fldPath.Index(i), elem.StrVal, err.Error()))
+			}
+		}
+		if elem.Type == intstr.Int && elem.IntVal < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), elem.IntVal, "must be non-negative"))
+		}
+	}
+	return allErrs
+}
+
 func validateScaleStrategy(spec *appsv1beta1.StatefulSetSpec, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
 
@@ -128,6 +254,31 @@ func validateRollingUpdateStatefulSetStrategyTypeUnorderedUpdate(spec *appsv1bet
 	}
 	return allErrs
 }
+
+func validateRoleAwareUpdate(roleAwareUpdate *appsv1beta1.RoleAwareUpdateStrategy, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if roleAwareUpdate == nil {
+		return allErrs
+	}
+
+	if roleAwareUpdate.LeaderSelector == nil && roleAwareUpdate.LeaderConditionType == "" {
+		allErrs = append(allErrs, field.Required(fldPath, "one of leaderSelector and leaderConditionType must be set"))
+	} else if roleAwareUpdate.LeaderSelector != nil && roleAwareUpdate.LeaderConditionType != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "leaderSelector and leaderConditionType can not be set at the same time"))
+	}
+	if roleAwareUpdate.LeaderSelector != nil {
+		allErrs = append(allErrs, unversionedvalidation.ValidateLabelSelector(roleAwareUpdate.LeaderSelector,
+			unversionedvalidation.LabelSelectorValidationOptions{}, fldPath.Child("leaderSelector"))...)
+		if len(roleAwareUpdate.LeaderSelector.MatchLabels)+len(roleAwareUpdate.LeaderSelector.MatchExpressions) == 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("leaderSelector"), roleAwareUpdate.LeaderSelector, "empty selector is invalid"))
+		}
+	}
+	if hook := roleAwareUpdate.SwitchoverHook; hook != nil && len(hook.FinalizersHandler)+len(hook.LabelsHandler) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("switchoverHook"), "one of finalizersHandler and labelsHandler must be set"))
+	}
+	return allErrs
+}
+
 func validateRollingUpdateStatefulSetStrategyType(spec *appsv1beta1.StatefulSetSpec, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
 
@@ -136,6 +287,8 @@ func validateRollingUpdateStatefulSetStrategyType(spec *appsv1beta1.StatefulSetS
 			apivalidation.ValidateNonnegativeField(
 				int64(*spec.UpdateStrategy.RollingUpdate.Partition),
 				fldPath.Child("updateStrategy").Child("rollingUpdate").Child("partition"))...)
+		allErrs = append(allErrs, validateOrdinalList(spec.UpdateStrategy.RollingUpdate.PartitionOrdinals,
+			fldPath.Child("updateStrategy").Child("rollingUpdate").Child("partitionOrdinals"))...)
 		// validate `minReadySeconds` field's range
 		allErrs = append(allErrs,
 			apivalidation.ValidateNonnegativeField(
@@ -158,9 +311,17 @@ func validateRollingUpdateStatefulSetStrategyType(spec *appsv1beta1.StatefulSetS
 		// validate the `PodUpdatePolicy` related fields
 		allErrs = append(allErrs, validatePodUpdatePolicy(spec, fldPath)...)
 
+		// validate the `InPlaceUpdateStrategy` related fields
+		allErrs = append(allErrs, webhookutil.ValidateInPlaceUpdateStrategy(spec.UpdateStrategy.RollingUpdate.InPlaceUpdateStrategy, &spec.Template,
+			fldPath.Child("updateStrategy").Child("rollingUpdate").Child("inPlaceUpdateStrategy"))...)
+
 		// validate the `spec.UpdateStrategy.RollingUpdate.UnorderedUpdate` related fields
 		allErrs = append(allErrs, validateRollingUpdateStatefulSetStrategyTypeUnorderedUpdate(spec, fldPath)...)
 
+		// validate the `spec.UpdateStrategy.RollingUpdate.RoleAwareUpdate` related fields
+		allErrs = append(allErrs, validateRoleAwareUpdate(spec.UpdateStrategy.RollingUpdate.RoleAwareUpdate,
+			fldPath.Child("updateStrategy").Child("rollingUpdate").Child("roleAwareUpdate"))...)
+
 	}
 	return allErrs
 }
@@ -230,15 +391,37 @@ func validateActiveDeadlineSeconds(spec *appsv1beta1.StatefulSetSpec, fldPath *f
 	return allErrs
 }
 
+func validateVolumeClaimUpdateStrategy(strategy *appsv1beta1.VolumeClaimUpdateStrategy, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if strategy.Migration == nil {
+		return allErrs
+	}
+
+	if strategy.Type != appsv1beta1.OnPodRollingUpdateVolumeClaimUpdateStrategyType {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("migration"),
+			fmt.Sprintf("migration can only work with %s type", appsv1beta1.OnPodRollingUpdateVolumeClaimUpdateStrategyType)))
+	}
+	if strategy.Migration.Image == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("migration", "image"), ""))
+	}
+	if strategy.Migration.BackoffLimit != nil {
+		allErrs = append(allErrs, apivalidation.ValidateNonnegativeField(int64(*strategy.Migration.BackoffLimit), fldPath.Child("migration", "backoffLimit"))...)
+	}
+	return allErrs
+}
+
 // ValidateStatefulSetSpec tests if required fields in the StatefulSet spec are set.
 func validateStatefulSetSpec(spec *appsv1beta1.StatefulSetSpec, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
 
 	allErrs = append(allErrs, validatePodManagementPolicy(spec, fldPath)...)
 	allErrs = append(allErrs, validateReserveOrdinals(spec, fldPath)...)
+	allErrs = append(allErrs, validateOrdinals(spec.Ordinals, fldPath.Child("ordinals"))...)
+	allErrs = append(allErrs, validateStartupDependencies(spec, fldPath.Child("startupDependencies"))...)
 	allErrs = append(allErrs, validateScaleStrategy(spec, fldPath)...)
 	allErrs = append(allErrs, validateUpdateStrategyType(spec, fldPath)...)
 	allErrs = append(allErrs, ValidatePersistentVolumeClaimRetentionPolicy(spec.PersistentVolumeClaimRetentionPolicy, fldPath.Child("persistentVolumeClaimRetentionPolicy"))...)
+	allErrs = append(allErrs, validateVolumeClaimUpdateStrategy(&spec.VolumeClaimUpdateStrategy, fldPath.Child("volumeClaimUpdateStrategy"))...)
 
 	allErrs = append(allErrs, apivalidation.ValidateNonnegativeField(int64(*spec.Replicas), fldPath.Child("replicas"))...)
 
@@ -337,9 +520,10 @@ func ValidateStatefulSetUpdate(statefulSet, oldStatefulSet *appsv1beta1.Stateful
 	statefulSet.Spec.Lifecycle = oldStatefulSet.Spec.Lifecycle
 	statefulSet.Spec.RevisionHistoryLimit = oldStatefulSet.Spec.RevisionHistoryLimit
 	statefulSet.Spec.Ordinals = oldStatefulSet.Spec.Ordinals
+	statefulSet.Spec.StartupDependencies = oldStatefulSet.Spec.StartupDependencies
 
 	if !apiequality.Semantic.DeepEqual(statefulSet.Spec, oldStatefulSet.Spec) {
-		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec"), "updates to statefulset spec for fields other than 'replicas', 'ordinals', 'template', 'reserveOrdinals', 'lifecycle', 'revisionHistoryLimit', 'persistentVolumeClaimRetentionPolicy', `volumeClaimTemplates`, `VolumeClaimUpdateStrategy` and 'updateStrategy' are forbidden"))
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec"), "updates to statefulset spec for fields other than 'replicas', 'ordinals', 'template', 'reserveOrdinals', 'lifecycle', 'revisionHistoryLimit', 'persistentVolumeClaimRetentionPolicy', 'startupDependencies', `volumeClaimTemplates`, `VolumeClaimUpdateStrategy` and 'updateStrategy' are forbidden"))
 	}
 	statefulSet.Spec.Replicas = restoreReplicas
 	statefulSet.Spec.Template = restoreTemplate
@@ -371,7 +555,8 @@ func validateTemplateInPlaceOnly(oldTemp, newTemp *v1.PodTemplateSpec) error {
 	return nil
 }
 
-// ValidateVolumeClaimTemplateUpdate tests if only size expand when sc allow expansion.
+// ValidateVolumeClaimTemplateUpdate tests if only size expand when sc allow expansion,
+// unless the claims can be migrated.
 func ValidateVolumeClaimTemplateUpdate(c client.Client, sts, oldSts *appsv1beta1.StatefulSet) field.ErrorList {
 	if sts.Spec.VolumeClaimUpdateStrategy.Type == "" ||
 		sts.Spec.VolumeClaimUpdateStrategy.Type == appsv1beta1.OnPVCDeleteVolumeClaimUpdateStrategyType {
@@ -394,11 +579,28 @@ func ValidateVolumeClaimTemplateUpdate(c client.Client, sts, oldSts *appsv1beta1
 			return field.ErrorList{field.Forbidden(field.NewPath("spec", templateIdStr, "name"), "volumeClaimTemplate name can not be modified")}
 		}
 
+		// the volumeAttributesClassName of claims is dropped by apiserver if VolumeAttributesClass is not supported,
+		// and the controller would keep updating the claims without any effect
+		if template.Spec.VolumeAttributesClassName != nil &&
+			!apiequality.Semantic.DeepEqual(template.Spec.VolumeAttributesClassName, oldTemplate.Spec.VolumeAttributesClassName) &&
+			!isVolumeAttributesClassSupported(c) {
+			return field.ErrorList{field.Forbidden(field.NewPath("spec", templateIdStr, "spec", "volumeAttributesClassName"),
+				"VolumeAttributesClass is not supported by the cluster")}
+		}
+
 		matched, resizeOnly := pvc.CompareWithCheckFn(oldTemplate, &template, isPVCResize)
 		if matched {
 			continue
 		}
 		if !resizeOnly {
+			// the storageClassName, accessModes and size can be changed by migrating the claims
+			if sts.Spec.VolumeClaimUpdateStrategy.Migration != nil {
+				if isPVCMigrationOnly(oldTemplate, &template) {
+					continue
+				}
+				return field.ErrorList{field.Invalid(field.NewPath("spec", templateIdStr), template,
+					"only storageClassName, accessModes and size of volumeClaimTemplate can be modified by migration")}
+			}
 			return field.ErrorList{field.Invalid(field.NewPath("spec", templateIdStr), template, "volumeClaimTemplate can not be modified when OnRollingUpdate")}
 		}
 		// check if sc allow volume expand
@@ -450,6 +652,21 @@ func GetDefaultStorageClass(c client.Client) (*storagev1.StorageClass, error) {
 	return defaultSC, nil
 }
 
+// isPVCMigrationOnly returns whether the template differs from the old one only in storageClassName, accessModes and size.
+func isPVCMigrationOnly(oldTemplate, template *v1.PersistentVolumeClaim) bool {
+	spec := oldTemplate.Spec.DeepCopy()
+	spec.StorageClassName = template.Spec.StorageClassName
+	spec.AccessModes = template.Spec.AccessModes
+	spec.Resources = template.Spec.Resources
+	return apiequality.Semantic.DeepEqual(*spec, template.Spec)
+}
+
+// isVolumeAttributesClassSupported returns whether VolumeAttributesClass is served by the cluster.
+func isVolumeAttributesClassSupported(c client.Client) bool {
+	_, err := c.RESTMapper().RESTMapping(schema.GroupKind{Group: storagev1.GroupName, Kind: "VolumeAttributesClass"})
+	return !meta.IsNoMatchError(err)
+}
+
 func isPVCResize(claim, template *v1.PersistentVolumeClaim) bool {
 	if claim.Spec.Resources.Requests.Storage().Cmp(*template.Spec.Resources.Requests.Storage()) != 0 ||
 		claim.Spec.Resources.Limits.Storage().Cmp(*template.Spec.Resources.Limits.Storage()) != 0 {
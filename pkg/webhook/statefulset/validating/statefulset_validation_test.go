@@ -26,10 +26,13 @@ import (
 	apps "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	storagev1beta1 "k8s.io/api/storage/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -147,6 +150,79 @@ func TestValidateStatefulSet(t *testing.T) {
 					}()},
 			},
 		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "abc-123", Namespace: metav1.NamespaceDefault},
+			Spec: appsv1beta1.StatefulSetSpec{
+				PodManagementPolicy: apps.ParallelPodManagement,
+				Selector:            &metav1.LabelSelector{MatchLabels: validLabels},
+				Template:            validPodTemplate.Template,
+				Replicas:            &val3,
+				UpdateStrategy: appsv1beta1.StatefulSetUpdateStrategy{
+					Type: apps.RollingUpdateStatefulSetStrategyType,
+					RollingUpdate: &appsv1beta1.RollingUpdateStatefulSetStrategy{
+						Partition:       &val2,
+						PodUpdatePolicy: appsv1beta1.RecreatePodUpdateStrategyType,
+						MaxUnavailable:  &maxUnavailable1,
+						MinReadySeconds: ptr.To[int32](0),
+						RoleAwareUpdate: &appsv1beta1.RoleAwareUpdateStrategy{
+							LeaderSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"role": "leader"}},
+							SwitchoverHook: &appspub.LifecycleHook{LabelsHandler: map[string]string{"switchover": "true"}},
+						},
+					},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "abc-123", Namespace: metav1.NamespaceDefault},
+			Spec: appsv1beta1.StatefulSetSpec{
+				PodManagementPolicy: apps.ParallelPodManagement,
+				Selector:            &metav1.LabelSelector{MatchLabels: validLabels},
+				Template:            validPodTemplate.Template,
+				Replicas:            &val3,
+				ReserveOrdinals:     []intstr.IntOrString{intstr.FromInt32(1)},
+				UpdateStrategy: appsv1beta1.StatefulSetUpdateStrategy{
+					Type: apps.RollingUpdateStatefulSetStrategyType,
+					RollingUpdate: &appsv1beta1.RollingUpdateStatefulSetStrategy{
+						Partition:         &val2,
+						PartitionOrdinals: []intstr.IntOrString{intstr.FromInt32(0), intstr.FromString("2-3")},
+						PodUpdatePolicy:   appsv1beta1.RecreatePodUpdateStrategyType,
+						MaxUnavailable:    &maxUnavailable1,
+						MinReadySeconds:   ptr.To[int32](0),
+					},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "abc-123", Namespace: metav1.NamespaceDefault},
+			Spec: appsv1beta1.StatefulSetSpec{
+				PodManagementPolicy: apps.ParallelPodManagement,
+				Selector:            &metav1.LabelSelector{MatchLabels: validLabels},
+				Template:            validPodTemplate.Template,
+				Replicas:            &val3,
+				UpdateStrategy:      appsv1beta1.StatefulSetUpdateStrategy{Type: apps.OnDeleteStatefulSetStrategyType},
+				StartupDependencies: []appsv1beta1.StatefulSetStartupDependency{
+					{DependsOnOrdinals: []intstr.IntOrString{intstr.FromInt32(0)}},
+					{
+						Ordinals:          []intstr.IntOrString{intstr.FromString("1-2")},
+						DependsOnSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"role": "coordinator"}},
+					},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "abc-123", Namespace: metav1.NamespaceDefault},
+			Spec: appsv1beta1.StatefulSetSpec{
+				PodManagementPolicy: apps.ParallelPodManagement,
+				Selector:            &metav1.LabelSelector{MatchLabels: validLabels},
+				Template:            validPodTemplate.Template,
+				Replicas:            &val3,
+				UpdateStrategy:      appsv1beta1.StatefulSetUpdateStrategy{Type: apps.OnDeleteStatefulSetStrategyType},
+				StartupDependencies: []appsv1beta1.StatefulSetStartupDependency{
+					{Ordinals: []intstr.IntOrString{intstr.FromInt32(2)}, DependsOnOrdinals: []intstr.IntOrString{intstr.FromInt32(1)}},
+					{Ordinals: []intstr.IntOrString{intstr.FromInt32(1)}, DependsOnOrdinals: []intstr.IntOrString{intstr.FromString("0-1")}},
+				},
+			},
+		},
 	}
 
 	for i, successCase := range successCases {
@@ -422,6 +498,202 @@ func TestValidateStatefulSet(t *testing.T) {
 				},
 			},
 		},
+		"role aware update without leader": {
+			ObjectMeta: metav1.ObjectMeta{Name: "abc-123", Namespace: metav1.NamespaceDefault},
+			Spec: appsv1beta1.StatefulSetSpec{
+				PodManagementPolicy: apps.OrderedReadyPodManagement,
+				Selector:            &metav1.LabelSelector{MatchLabels: validLabels},
+				Template:            validPodTemplate.Template,
+				Replicas:            &val3,
+				UpdateStrategy: appsv1beta1.StatefulSetUpdateStrategy{Type: apps.RollingUpdateStatefulSetStrategyType,
+					RollingUpdate: &appsv1beta1.RollingUpdateStatefulSetStrategy{
+						Partition:       &val2,
+						PodUpdatePolicy: appsv1beta1.RecreatePodUpdateStrategyType,
+						MaxUnavailable:  &maxUnavailable1,
+						MinReadySeconds: ptr.To[int32](0),
+						RoleAwareUpdate: &appsv1beta1.RoleAwareUpdateStrategy{},
+					},
+				},
+			},
+		},
+		"role aware update with both leader selector and condition": {
+			ObjectMeta: metav1.ObjectMeta{Name: "abc-123", Namespace: metav1.NamespaceDefault},
+			Spec: appsv1beta1.StatefulSetSpec{
+				PodManagementPolicy: apps.OrderedReadyPodManagement,
+				Selector:            &metav1.LabelSelector{MatchLabels: validLabels},
+				Template:            validPodTemplate.Template,
+				Replicas:            &val3,
+				UpdateStrategy: appsv1beta1.StatefulSetUpdateStrategy{Type: apps.RollingUpdateStatefulSetStrategyType,
+					RollingUpdate: &appsv1beta1.RollingUpdateStatefulSetStrategy{
+						Partition:       &val2,
+						PodUpdatePolicy: appsv1beta1.RecreatePodUpdateStrategyType,
+						MaxUnavailable:  &maxUnavailable1,
+						MinReadySeconds: ptr.To[int32](0),
+						RoleAwareUpdate: &appsv1beta1.RoleAwareUpdateStrategy{
+							LeaderSelector:      &metav1.LabelSelector{MatchLabels: map[string]string{"role": "leader"}},
+							LeaderConditionType: "Leader",
+						},
+					},
+				},
+			},
+		},
+		"invalid ordinals coordination": {
+			ObjectMeta: metav1.ObjectMeta{Name: "abc-123", Namespace: metav1.NamespaceDefault},
+			Spec: appsv1beta1.StatefulSetSpec{
+				PodManagementPolicy: apps.OrderedReadyPodManagement,
+				Selector:            &metav1.LabelSelector{MatchLabels: validLabels},
+				Template:            validPodTemplate.Template,
+				Replicas:            &val3,
+				UpdateStrategy:      appsv1beta1.StatefulSetUpdateStrategy{Type: apps.OnDeleteStatefulSetStrategyType},
+				Ordinals: &appsv1beta1.StatefulSetOrdinals{
+					Coordination: &appsv1beta1.StatefulSetOrdinalsCoordination{ConfigMapName: "Invalid_Name"},
+				},
+			},
+		},
+		"invalid ordinals coordination namespace": {
+			ObjectMeta: metav1.ObjectMeta{Name: "abc-123", Namespace: metav1.NamespaceDefault},
+			Spec: appsv1beta1.StatefulSetSpec{
+				PodManagementPolicy: apps.OrderedReadyPodManagement,
+				Selector:            &metav1.LabelSelector{MatchLabels: validLabels},
+				Template:            validPodTemplate.Template,
+				Replicas:            &val3,
+				UpdateStrategy:      appsv1beta1.StatefulSetUpdateStrategy{Type: apps.OnDeleteStatefulSetStrategyType},
+				Ordinals: &appsv1beta1.StatefulSetOrdinals{
+					Coordination: &appsv1beta1.StatefulSetOrdinalsCoordination{ConfigMapName: "ordinals", Namespace: "Invalid.Namespace"},
+				},
+			},
+		},
+		"startup dependencies with ordered ready policy": {
+			ObjectMeta: metav1.ObjectMeta{Name: "abc-123", Namespace: metav1.NamespaceDefault},
+			Spec: appsv1beta1.StatefulSetSpec{
+				PodManagementPolicy: apps.OrderedReadyPodManagement,
+				Selector:            &metav1.LabelSelector{MatchLabels: validLabels},
+				Template:            validPodTemplate.Template,
+				Replicas:            &val3,
+				UpdateStrategy:      appsv1beta1.StatefulSetUpdateStrategy{Type: apps.OnDeleteStatefulSetStrategyType},
+				StartupDependencies: []appsv1beta1.StatefulSetStartupDependency{
+					{DependsOnOrdinals: []intstr.IntOrString{intstr.FromInt32(0)}},
+				},
+			},
+		},
+		"startup dependency without dependencies": {
+			ObjectMeta: metav1.ObjectMeta{Name: "abc-123", Namespace: metav1.NamespaceDefault},
+			Spec: appsv1beta1.StatefulSetSpec{
+				PodManagementPolicy: apps.ParallelPodManagement,
+				Selector:            &metav1.LabelSelector{MatchLabels: validLabels},
+				Template:            validPodTemplate.Template,
+				Replicas:            &val3,
+				UpdateStrategy:      appsv1beta1.StatefulSetUpdateStrategy{Type: apps.OnDeleteStatefulSetStrategyType},
+				StartupDependencies: []appsv1beta1.StatefulSetStartupDependency{
+					{Ordinals: []intstr.IntOrString{intstr.FromInt32(1)}},
+				},
+			},
+		},
+		"startup dependencies with ordinal cycle": {
+			ObjectMeta: metav1.ObjectMeta{Name: "abc-123", Namespace: metav1.NamespaceDefault},
+			Spec: appsv1beta1.StatefulSetSpec{
+				PodManagementPolicy: apps.ParallelPodManagement,
+				Selector:            &metav1.LabelSelector{MatchLabels: validLabels},
+				Template:            validPodTemplate.Template,
+				Replicas:            &val3,
+				UpdateStrategy:      appsv1beta1.StatefulSetUpdateStrategy{Type: apps.OnDeleteStatefulSetStrategyType},
+				StartupDependencies: []appsv1beta1.StatefulSetStartupDependency{
+					{Ordinals: []intstr.IntOrString{intstr.FromInt32(0)}, DependsOnOrdinals: []intstr.IntOrString{intstr.FromInt32(1)}},
+					{Ordinals: []intstr.IntOrString{intstr.FromInt32(1)}, DependsOnOrdinals: []intstr.IntOrString{intstr.FromInt32(0)}},
+				},
+			},
+		},
+		"startup dependencies with cycle through all the other pods": {
+			ObjectMeta: metav1.ObjectMeta{Name: "abc-123", Namespace: metav1.NamespaceDefault},
+			Spec: appsv1beta1.StatefulSetSpec{
+				PodManagementPolicy: apps.ParallelPodManagement,
+				Selector:            &metav1.LabelSelector{MatchLabels: validLabels},
+				Template:            validPodTemplate.Template,
+				Replicas:            &val3,
+				UpdateStrategy:      appsv1beta1.StatefulSetUpdateStrategy{Type: apps.OnDeleteStatefulSetStrategyType},
+				StartupDependencies: []appsv1beta1.StatefulSetStartupDependency{
+					{DependsOnOrdinals: []intstr.IntOrString{intstr.FromInt32(0)}},
+					{Ordinals: []intstr.IntOrString{intstr.FromInt32(0)}, DependsOnOrdinals: []intstr.IntOrString{intstr.FromInt32(2)}},
+				},
+			},
+		},
+		"startup dependency with empty selector": {
+			ObjectMeta: metav1.ObjectMeta{Name: "abc-123", Namespace: metav1.NamespaceDefault},
+			Spec: appsv1beta1.StatefulSetSpec{
+				PodManagementPolicy: apps.ParallelPodManagement,
+				Selector:            &metav1.LabelSelector{MatchLabels: validLabels},
+				Template:            validPodTemplate.Template,
+				Replicas:            &val3,
+				UpdateStrategy:      appsv1beta1.StatefulSetUpdateStrategy{Type: apps.OnDeleteStatefulSetStrategyType},
+				StartupDependencies: []appsv1beta1.StatefulSetStartupDependency{
+					{DependsOnSelector: &metav1.LabelSelector{}},
+				},
+			},
+		},
+		"invalid partition ordinals": {
+			ObjectMeta: metav1.ObjectMeta{Name: "abc-123", Namespace: metav1.NamespaceDefault},
+			Spec: appsv1beta1.StatefulSetSpec{
+				PodManagementPolicy: apps.ParallelPodManagement,
+				Selector:            &metav1.LabelSelector{MatchLabels: validLabels},
+				Template:            validPodTemplate.Template,
+				Replicas:            &val3,
+				UpdateStrategy: appsv1beta1.StatefulSetUpdateStrategy{Type: apps.RollingUpdateStatefulSetStrategyType,
+					RollingUpdate: &appsv1beta1.RollingUpdateStatefulSetStrategy{
+						Partition:         &val2,
+						PartitionOrdinals: []intstr.IntOrString{intstr.FromString("3-1")},
+						PodUpdatePolicy:   appsv1beta1.RecreatePodUpdateStrategyType,
+						MaxUnavailable:    &maxUnavailable1,
+						MinReadySeconds:   ptr.To[int32](0),
+					},
+				},
+			},
+		},
+		"negative partition ordinals": {
+			ObjectMeta: metav1.ObjectMeta{Name: "abc-123", Namespace: metav1.NamespaceDefault},
+			Spec: appsv1beta1.StatefulSetSpec{
+				PodManagementPolicy: apps.ParallelPodManagement,
+				Selector:            &metav1.LabelSelector{MatchLabels: validLabels},
+				Template:            validPodTemplate.Template,
+				Replicas:            &val3,
+				UpdateStrategy: appsv1beta1.StatefulSetUpdateStrategy{Type: apps.RollingUpdateStatefulSetStrategyType,
+					RollingUpdate: &appsv1beta1.RollingUpdateStatefulSetStrategy{
+						Partition:         &val2,
+						PartitionOrdinals: []intstr.IntOrString{intstr.FromInt32(-1)},
+						PodUpdatePolicy:   appsv1beta1.RecreatePodUpdateStrategyType,
+						MaxUnavailable:    &maxUnavailable1,
+						MinReadySeconds:   ptr.To[int32](0),
+					},
+				},
+			},
+		},
+		"volume claim migration without image": {
+			ObjectMeta: metav1.ObjectMeta{Name: "abc-123", Namespace: metav1.NamespaceDefault},
+			Spec: appsv1beta1.StatefulSetSpec{
+				PodManagementPolicy: apps.OrderedReadyPodManagement,
+				Selector:            &metav1.LabelSelector{MatchLabels: validLabels},
+				Template:            validPodTemplate.Template,
+				Replicas:            &val3,
+				UpdateStrategy:      appsv1beta1.StatefulSetUpdateStrategy{Type: apps.RollingUpdateStatefulSetStrategyType},
+				VolumeClaimUpdateStrategy: appsv1beta1.VolumeClaimUpdateStrategy{
+					Type:      appsv1beta1.OnPodRollingUpdateVolumeClaimUpdateStrategyType,
+					Migration: &appsv1beta1.VolumeClaimMigrationStrategy{},
+				},
+			},
+		},
+		"volume claim migration with OnDelete type": {
+			ObjectMeta: metav1.ObjectMeta{Name: "abc-123", Namespace: metav1.NamespaceDefault},
+			Spec: appsv1beta1.StatefulSetSpec{
+				PodManagementPolicy: apps.OrderedReadyPodManagement,
+				Selector:            &metav1.LabelSelector{MatchLabels: validLabels},
+				Template:            validPodTemplate.Template,
+				Replicas:            &val3,
+				UpdateStrategy:      appsv1beta1.StatefulSetUpdateStrategy{Type: apps.RollingUpdateStatefulSetStrategyType},
+				VolumeClaimUpdateStrategy: appsv1beta1.VolumeClaimUpdateStrategy{
+					Type:      appsv1beta1.OnPVCDeleteVolumeClaimUpdateStrategyType,
+					Migration: &appsv1beta1.VolumeClaimMigrationStrategy{Image: "busybox"},
+				},
+			},
+		},
 		"empty pod management policy": {
 			ObjectMeta: metav1.ObjectMeta{Name: "abc-123", Namespace: metav1.NamespaceDefault},
 			Spec: appsv1beta1.StatefulSetSpec{
@@ -481,6 +753,15 @@ func TestValidateStatefulSet(t *testing.T) {
 					f != "spec.updateStrategy.rollingUpdate.maxUnavailable" &&
 					f != "spec.updateStrategy.rollingUpdate.minReadySeconds" &&
 					f != "spec.updateStrategy.rollingUpdate.podUpdatePolicy" &&
+					f != "spec.updateStrategy.rollingUpdate.roleAwareUpdate" &&
+					f != "spec.updateStrategy.rollingUpdate.partitionOrdinals[0]" &&
+					f != "spec.ordinals.coordination.configMapName" &&
+					f != "spec.ordinals.coordination.namespace" &&
+					f != "spec.startupDependencies" &&
+					f != "spec.startupDependencies[0]" &&
+					f != "spec.startupDependencies[0].dependsOnSelector" &&
+					f != "spec.volumeClaimUpdateStrategy.migration" &&
+					f != "spec.volumeClaimUpdateStrategy.migration.image" &&
 					f != "spec.template.spec.readinessGates" &&
 					f != "spec.podManagementPolicy" &&
 					f != "spec.template.spec.activeDeadlineSeconds" {
@@ -847,6 +1128,109 @@ func TestValidateVolumeClaimTemplateUpdate(t *testing.T) {
 			},
 			expectedErrors: true,
 		},
+		{
+			name: "on pod rolling update strategy with migration and change sc",
+			sts: &appsv1beta1.StatefulSet{
+				Spec: appsv1beta1.StatefulSetSpec{
+					VolumeClaimUpdateStrategy: appsv1beta1.VolumeClaimUpdateStrategy{
+						Type:      appsv1beta1.OnPodRollingUpdateVolumeClaimUpdateStrategyType,
+						Migration: &appsv1beta1.VolumeClaimMigrationStrategy{Image: "busybox"},
+					},
+					VolumeClaimTemplates: []v1.PersistentVolumeClaim{
+						{
+							ObjectMeta: metav1.ObjectMeta{Name: "pvc-1"},
+							Spec:       v1.PersistentVolumeClaimSpec{StorageClassName: &allowExpandSC.Name},
+						},
+					},
+				},
+			},
+			oldSts: &appsv1beta1.StatefulSet{
+				Spec: appsv1beta1.StatefulSetSpec{
+					VolumeClaimTemplates: []v1.PersistentVolumeClaim{
+						{
+							ObjectMeta: metav1.ObjectMeta{Name: "pvc-1"},
+							Spec:       v1.PersistentVolumeClaimSpec{StorageClassName: &disallowExpandSC.Name},
+						},
+					},
+				},
+			},
+			expectedErrors: false,
+		},
+		{
+			name: "on pod rolling update strategy with migration and change sc and volumeMode",
+			sts: &appsv1beta1.StatefulSet{
+				Spec: appsv1beta1.StatefulSetSpec{
+					VolumeClaimUpdateStrategy: appsv1beta1.VolumeClaimUpdateStrategy{
+						Type:      appsv1beta1.OnPodRollingUpdateVolumeClaimUpdateStrategyType,
+						Migration: &appsv1beta1.VolumeClaimMigrationStrategy{Image: "busybox"},
+					},
+					VolumeClaimTemplates: []v1.PersistentVolumeClaim{
+						{
+							ObjectMeta: metav1.ObjectMeta{Name: "pvc-1"},
+							Spec: v1.PersistentVolumeClaimSpec{
+								StorageClassName: &allowExpandSC.Name,
+								VolumeMode:       ptr.To(v1.PersistentVolumeBlock),
+							},
+						},
+					},
+				},
+			},
+			oldSts: &appsv1beta1.StatefulSet{
+				Spec: appsv1beta1.StatefulSetSpec{
+					VolumeClaimTemplates: []v1.PersistentVolumeClaim{
+						{
+							ObjectMeta: metav1.ObjectMeta{Name: "pvc-1"},
+							Spec:       v1.PersistentVolumeClaimSpec{StorageClassName: &disallowExpandSC.Name},
+						},
+					},
+				},
+			},
+			expectedErrors: true,
+		},
+		{
+			name: "on pod rolling update strategy with migration and change access modes and size",
+			sts: &appsv1beta1.StatefulSet{
+				Spec: appsv1beta1.StatefulSetSpec{
+					VolumeClaimUpdateStrategy: appsv1beta1.VolumeClaimUpdateStrategy{
+						Type:      appsv1beta1.OnPodRollingUpdateVolumeClaimUpdateStrategyType,
+						Migration: &appsv1beta1.VolumeClaimMigrationStrategy{Image: "busybox"},
+					},
+					VolumeClaimTemplates: []v1.PersistentVolumeClaim{
+						{
+							ObjectMeta: metav1.ObjectMeta{Name: "pvc-1"},
+							Spec: v1.PersistentVolumeClaimSpec{
+								StorageClassName: &disallowExpandSC.Name,
+								AccessModes:      []v1.PersistentVolumeAccessMode{v1.ReadWriteMany},
+								Resources: v1.VolumeResourceRequirements{
+									Requests: map[v1.ResourceName]resource.Quantity{
+										v1.ResourceStorage: resource.MustParse("3Gi"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			oldSts: &appsv1beta1.StatefulSet{
+				Spec: appsv1beta1.StatefulSetSpec{
+					VolumeClaimTemplates: []v1.PersistentVolumeClaim{
+						{
+							ObjectMeta: metav1.ObjectMeta{Name: "pvc-1"},
+							Spec: v1.PersistentVolumeClaimSpec{
+								StorageClassName: &disallowExpandSC.Name,
+								AccessModes:      []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+								Resources: v1.VolumeResourceRequirements{
+									Requests: map[v1.ResourceName]resource.Quantity{
+										v1.ResourceStorage: resource.MustParse("1Gi"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedErrors: false,
+		},
 		{
 			name: "on pod rolling update strategy and expand size with expansion allowed sc",
 			sts: &appsv1beta1.StatefulSet{
@@ -1068,6 +1452,79 @@ func TestValidateVolumeClaimTemplateUpdate(t *testing.T) {
 	}
 }
 
+func TestValidateVolumeClaimTemplateUpdateWithVolumeAttributesClass(t *testing.T) {
+	newSts := func(vacName *string) *appsv1beta1.StatefulSet {
+		return &appsv1beta1.StatefulSet{
+			Spec: appsv1beta1.StatefulSetSpec{
+				VolumeClaimUpdateStrategy: appsv1beta1.VolumeClaimUpdateStrategy{
+					Type: appsv1beta1.OnPodRollingUpdateVolumeClaimUpdateStrategyType,
+				},
+				VolumeClaimTemplates: []v1.PersistentVolumeClaim{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "pvc-1"},
+						Spec: v1.PersistentVolumeClaimSpec{
+							VolumeAttributesClassName: vacName,
+							Resources: v1.VolumeResourceRequirements{
+								Requests: map[v1.ResourceName]resource.Quantity{
+									v1.ResourceStorage: resource.MustParse("1Gi"),
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	restMapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{storagev1beta1.SchemeGroupVersion})
+	restMapper.Add(storagev1beta1.SchemeGroupVersion.WithKind("VolumeAttributesClass"), meta.RESTScopeRoot)
+	supportedClient := fake.NewClientBuilder().WithScheme(testScheme).WithRESTMapper(restMapper).Build()
+	unsupportedClient := fake.NewClientBuilder().WithScheme(testScheme).Build()
+
+	tests := []struct {
+		name           string
+		supported      bool
+		sts            *appsv1beta1.StatefulSet
+		oldSts         *appsv1beta1.StatefulSet
+		expectedErrors bool
+	}{
+		{
+			name:      "modify VolumeAttributesClass when supported",
+			supported: true,
+			sts:       newSts(ptr.To("gold")),
+			oldSts:    newSts(ptr.To("silver")),
+		},
+		{
+			name:           "modify VolumeAttributesClass when not supported",
+			sts:            newSts(ptr.To("gold")),
+			oldSts:         newSts(ptr.To("silver")),
+			expectedErrors: true,
+		},
+		{
+			name:           "set VolumeAttributesClass when not supported",
+			sts:            newSts(ptr.To("gold")),
+			oldSts:         newSts(nil),
+			expectedErrors: true,
+		},
+		{
+			name:   "keep VolumeAttributesClass when not supported",
+			sts:    newSts(ptr.To("gold")),
+			oldSts: newSts(ptr.To("gold")),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := unsupportedClient
+			if tt.supported {
+				c = supportedClient
+			}
+			errs := ValidateVolumeClaimTemplateUpdate(c, tt.sts, tt.oldSts)
+			if hasErrors := len(errs) > 0; tt.expectedErrors != hasErrors {
+				t.Errorf("expected errors: %v, got: %v", tt.expectedErrors, errs)
+			}
+		})
+	}
+}
+
 func TestGetDefaultStorageClass(t *testing.T) {
 	// Create StorageClass objects to use in the test.
 	newSCWithCreateTime := func(name string, allowExpansion, isDefault bool, createTime time.Time) *storagev1.StorageClass {
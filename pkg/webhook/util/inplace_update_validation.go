@@ -0,0 +1,56 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	apivalidation "k8s.io/kubernetes/pkg/apis/core/validation"
+
+	appspub "github.com/openkruise/kruise/apis/apps/pub"
+)
+
+// ValidateInPlaceUpdateStrategy validates the containerUpdateOrder of in-place update strategy against the pod template.
+func ValidateInPlaceUpdateStrategy(strategy *appspub.InPlaceUpdateStrategy, template *v1.PodTemplateSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if strategy == nil {
+		return allErrs
+	}
+
+	containerNames := sets.NewString()
+	for i := range template.Spec.Containers {
+		containerNames.Insert(template.Spec.Containers[i].Name)
+	}
+	orderedNames := sets.NewString()
+	for i, batch := range strategy.ContainerUpdateOrder {
+		batchPath := fldPath.Child("containerUpdateOrder").Index(i)
+		if len(batch.Containers) == 0 {
+			allErrs = append(allErrs, field.Required(batchPath.Child("containers"), ""))
+		}
+		for j, name := range batch.Containers {
+			if !containerNames.Has(name) {
+				allErrs = append(allErrs, field.NotFound(batchPath.Child("containers").Index(j), name))
+			} else if orderedNames.Has(name) {
+				allErrs = append(allErrs, field.Duplicate(batchPath.Child("containers").Index(j), name))
+			}
+			orderedNames.Insert(name)
+		}
+		allErrs = append(allErrs, apivalidation.ValidateNonnegativeField(int64(batch.IntervalSeconds), batchPath.Child("intervalSeconds"))...)
+	}
+	return allErrs
+}